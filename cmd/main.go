@@ -2,6 +2,7 @@ package main
 
 import (
 	"log"
+	"os"
 
 	"github.com/sunr3d/order-stream-processor/internal/config"
 	"github.com/sunr3d/order-stream-processor/internal/entrypoint"
@@ -16,7 +17,25 @@ func main() {
 
 	zapLogger := logger.New(cfg.LogLevel)
 
-	if err = entrypoint.Run(cfg, zapLogger); err != nil {
-		log.Fatalf("ошибка при запуске приложения: %s\n", err.Error())
+	command := ""
+	if len(os.Args) > 1 {
+		command = os.Args[1]
+	}
+
+	switch command {
+	case "":
+		if err = entrypoint.Run(cfg, zapLogger); err != nil {
+			log.Fatalf("ошибка при запуске приложения: %s\n", err.Error())
+		}
+	case "dlq-replay":
+		if err = entrypoint.ReplayDLQ(cfg, zapLogger); err != nil {
+			log.Fatalf("ошибка при переотправке DLQ: %s\n", err.Error())
+		}
+	case "migrate":
+		if err = entrypoint.Migrate(cfg, zapLogger, os.Args[2:]); err != nil {
+			log.Fatalf("ошибка при выполнении миграций: %s\n", err.Error())
+		}
+	default:
+		log.Fatalf("неизвестная команда: %s\n", command)
 	}
 }
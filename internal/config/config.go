@@ -7,8 +7,22 @@ type Config struct {
 	HTTPTimeout time.Duration `envconfig:"HTTP_TIMEOUT" default:"30s"`
 	LogLevel    string        `envconfig:"LOG_LEVEL" default:"info"`
 
+	HealthTimeout time.Duration `envconfig:"HEALTH_TIMEOUT" default:"2s"`
+
+	// IdempotencyTTL — сколько хранится ответ на запрос с заголовком Idempotency-Key, 0 — заголовок игнорируется
+	IdempotencyTTL time.Duration `envconfig:"IDEMPOTENCY_TTL" default:"24h"`
+	// IdempotencyMaxBody — максимальный размер тела запроса с Idempotency-Key, больший отклоняется с 413
+	IdempotencyMaxBody int64 `envconfig:"IDEMPOTENCY_MAX_BODY" default:"1048576"`
+	// IdempotencyMaxEntries — сколько ключей хранится на реплике, при заполнении вытесняются самые старые ответы
+	IdempotencyMaxEntries int `envconfig:"IDEMPOTENCY_MAX_ENTRIES" default:"10000"`
+
 	Postgres PostgresConfig `envconfig:"POSTGRES"`
 	Kafka    KafkaConfig    `envconfig:"KAFKA"`
+	Cache    CacheConfig    `envconfig:"CACHE"`
+	Redis    RedisConfig    `envconfig:"REDIS"`
+	Outbox   OutboxConfig   `envconfig:"OUTBOX"`
+
+	Validation ValidationConfig `envconfig:"VALIDATION"`
 }
 
 type PostgresConfig struct {
@@ -19,6 +33,11 @@ type PostgresConfig struct {
 	DBName      string        `envconfig:"DB" default:"orders"`
 	SSLMode     string        `envconfig:"SSL_MODE" default:"disable"`
 	PingTimeout time.Duration `envconfig:"PING_TIMEOUT" default:"5s"`
+
+	MigrateOnStart bool `envconfig:"MIGRATE_ON_START" default:"true"`
+
+	// Storage — режим хранения заказов: jsonb (документ целиком) или normalized (отдельные таблицы)
+	Storage string `envconfig:"STORAGE" default:"jsonb"`
 }
 
 type KafkaConfig struct {
@@ -26,4 +45,118 @@ type KafkaConfig struct {
 	Topic      string   `envconfig:"TOPIC" default:"orders"`
 	GroupID    string   `envconfig:"GROUP_ID" default:"order-processor"`
 	MaxRetries int      `envconfig:"MAX_RETRIES" default:"3"`
+	Workers    int      `envconfig:"WORKERS" default:"1"`
+	DLQTopic   string   `envconfig:"DLQ_TOPIC" default:"orders.dlq"`
+
+	// OrdersMode — обработка заказов из Topic: create (повтор order_uid — дубликат)
+	// или upsert (более новый заказ заменяет сохраненный)
+	OrdersMode string `envconfig:"ORDERS_MODE" default:"create"`
+
+	// BatchSize > 1 включает пакетную обработку: до BatchSize сообщений партиции
+	// или сообщения, накопленные за BatchTimeout, сохраняются в БД вместе
+	BatchSize    int           `envconfig:"BATCH_SIZE" default:"0"`
+	BatchTimeout time.Duration `envconfig:"BATCH_TIMEOUT" default:"100ms"`
+
+	// ConsumerGrace — сколько консьюмер может быть вне consumer group (ребалансировка, старт),
+	// прежде чем /readyz сочтет его неисправным
+	ConsumerGrace time.Duration `envconfig:"CONSUMER_GRACE" default:"1m"`
+
+	StatusTopic string `envconfig:"STATUS_TOPIC" default:"order-status"`
+	EventsTopic string `envconfig:"EVENTS_TOPIC" default:"order-events"`
+
+	Retry KafkaRetryConfig `envconfig:"RETRY"`
+}
+
+type KafkaRetryConfig struct {
+	InitialDelay time.Duration `envconfig:"INITIAL_DELAY" default:"200ms"`
+	MaxDelay     time.Duration `envconfig:"MAX_DELAY" default:"10s"`
+	Multiplier   float64       `envconfig:"MULTIPLIER" default:"2"`
+	Jitter       float64       `envconfig:"JITTER" default:"0.2"`
+}
+
+type CacheConfig struct {
+	// Backend — хранилище кэша: inmem (в памяти процесса) или redis (общий для всех реплик)
+	Backend    string        `envconfig:"BACKEND" default:"inmem"`
+	Policy     string        `envconfig:"POLICY" default:"lru"`
+	MaxEntries int           `envconfig:"MAX_ENTRIES" default:"100000"`
+	MaxBytes   int64         `envconfig:"MAX_BYTES" default:"268435456"`
+	TTL        time.Duration `envconfig:"TTL" default:"0s"`
+	// NegativeTTL — сколько помнить order_uid, не найденные в БД (0 — не запоминать)
+	NegativeTTL        time.Duration `envconfig:"NEGATIVE_TTL" default:"5s"`
+	NegativeMaxEntries int           `envconfig:"NEGATIVE_MAX_ENTRIES" default:"100000"`
+	// SnapshotPath — файл снимка кэша для быстрого восстановления при старте (пусто — без снимков)
+	SnapshotPath     string        `envconfig:"SNAPSHOT_PATH" default:""`
+	SnapshotInterval time.Duration `envconfig:"SNAPSHOT_INTERVAL" default:"5m"`
+	// WarmupChunkSize — сколько заказов читать из БД за один запрос при восстановлении кэша
+	WarmupChunkSize int `envconfig:"WARMUP_CHUNK_SIZE" default:"1000"`
+
+	Invalidation CacheInvalidationConfig `envconfig:"INVALIDATION"`
+}
+
+// CacheInvalidationConfig — инвалидация кэша по уведомлениям PostgreSQL (LISTEN/NOTIFY)
+// об изменениях заказов, сделанных другими репликами.
+type CacheInvalidationConfig struct {
+	Enabled bool `envconfig:"ENABLED" default:"true"`
+	// MinReconnect и MaxReconnect ограничивают паузу между попытками переподключения
+	MinReconnect time.Duration `envconfig:"MIN_RECONNECT" default:"1s"`
+	MaxReconnect time.Duration `envconfig:"MAX_RECONNECT" default:"30s"`
+	// PingInterval — период проверки соединения, по которому обнаруживается его обрыв
+	PingInterval time.Duration `envconfig:"PING_INTERVAL" default:"30s"`
+	// ResyncRetry — пауза перед повтором неудачной сверки кэша с БД после переподключения
+	ResyncRetry time.Duration `envconfig:"RESYNC_RETRY" default:"5s"`
+}
+
+type RedisConfig struct {
+	Addr         string        `envconfig:"ADDR" default:"localhost:6379"`
+	Password     string        `envconfig:"PASSWORD" default:""`
+	DB           int           `envconfig:"DB" default:"0"`
+	PoolSize     int           `envconfig:"POOL_SIZE" default:"10"`
+	MinIdleConns int           `envconfig:"MIN_IDLE_CONNS" default:"0"`
+	DialTimeout  time.Duration `envconfig:"DIAL_TIMEOUT" default:"5s"`
+	ReadTimeout  time.Duration `envconfig:"READ_TIMEOUT" default:"1s"`
+	WriteTimeout time.Duration `envconfig:"WRITE_TIMEOUT" default:"1s"`
+	PingTimeout  time.Duration `envconfig:"PING_TIMEOUT" default:"5s"`
+
+	// KeyPrefix добавляется к ключам заказов, чтобы несколько окружений могли использовать один Redis
+	KeyPrefix string `envconfig:"KEY_PREFIX" default:"orders:"`
+}
+
+type OutboxConfig struct {
+	Enabled      bool          `envconfig:"ENABLED" default:"true"`
+	PollInterval time.Duration `envconfig:"POLL_INTERVAL" default:"1s"`
+	BatchSize    int           `envconfig:"BATCH_SIZE" default:"100"`
+
+	// Retention — сколько хранить опубликованные события, 0 — не удалять
+	Retention       time.Duration `envconfig:"RETENTION" default:"168h"`
+	CleanupInterval time.Duration `envconfig:"CLEANUP_INTERVAL" default:"1h"`
+}
+
+// ValidationConfig — режимы правил согласованности и форматов полей заказа: reject (заказ отклоняется),
+// warn (заказ принимается, нарушение пишется в лог) или off (правило не проверяется).
+type ValidationConfig struct {
+	// AmountTotal — payment.amount равен goods_total + delivery_cost + custom_fee
+	AmountTotal string `envconfig:"AMOUNT_TOTAL" default:"warn"`
+	// GoodsTotal — payment.goods_total равен сумме total_price товаров
+	GoodsTotal string `envconfig:"GOODS_TOTAL" default:"warn"`
+	// ItemTotalPrice — total_price товара равен price за вычетом скидки sale (%)
+	ItemTotalPrice string `envconfig:"ITEM_TOTAL_PRICE" default:"warn"`
+	// ItemTrackNumber — track_number товаров совпадает с track_number заказа
+	ItemTrackNumber string `envconfig:"ITEM_TRACK_NUMBER" default:"warn"`
+	// PaymentLink — payment.transaction или payment.request_id равен order_uid
+	PaymentLink string `envconfig:"PAYMENT_LINK" default:"warn"`
+	// DateCreated — date_created не в будущем; ClockSkew — допустимое расхождение часов
+	DateCreated string        `envconfig:"DATE_CREATED" default:"warn"`
+	ClockSkew   time.Duration `envconfig:"CLOCK_SKEW" default:"5m"`
+
+	// Форматы: email, телефон в E.164, почтовый индекс страны доставки, код валюты ISO 4217, локаль BCP 47
+	EmailFormat    string `envconfig:"EMAIL_FORMAT" default:"warn"`
+	PhoneFormat    string `envconfig:"PHONE_FORMAT" default:"warn"`
+	ZipFormat      string `envconfig:"ZIP_FORMAT" default:"warn"`
+	CurrencyFormat string `envconfig:"CURRENCY_FORMAT" default:"warn"`
+	LocaleFormat   string `envconfig:"LOCALE_FORMAT" default:"warn"`
+
+	// Normalize — приводить email, телефон, индекс, валюту и локаль к каноническому виду перед проверкой и сохранением
+	Normalize bool `envconfig:"NORMALIZE" default:"false"`
+	// Country — страна по умолчанию (ISO 3166-1 alpha-2) для телефонов без кода страны и почтовых индексов
+	Country string `envconfig:"COUNTRY" default:""`
 }
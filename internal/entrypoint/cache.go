@@ -0,0 +1,37 @@
+package entrypoint
+
+import (
+	"fmt"
+
+	"go.uber.org/zap"
+
+	"github.com/sunr3d/order-stream-processor/internal/config"
+	"github.com/sunr3d/order-stream-processor/internal/infra/inmem"
+	"github.com/sunr3d/order-stream-processor/internal/infra/redis"
+	"github.com/sunr3d/order-stream-processor/internal/interfaces/infra"
+)
+
+// Хранилища кэша (CACHE_BACKEND)
+const (
+	CacheBackendInmem = "inmem"
+	CacheBackendRedis = "redis"
+)
+
+func newCache(cfg *config.Config, logger *zap.Logger) (infra.Cache, error) {
+	switch cfg.Cache.Backend {
+	case CacheBackendInmem, "":
+		cache, err := inmem.New(cfg.Cache, logger)
+		if err != nil {
+			return nil, fmt.Errorf("inmem.New: %w", err)
+		}
+		return cache, nil
+	case CacheBackendRedis:
+		cache, err := redis.New(cfg.Redis, cfg.Cache, logger)
+		if err != nil {
+			return nil, fmt.Errorf("redis.New: %w", err)
+		}
+		return cache, nil
+	default:
+		return nil, fmt.Errorf("неизвестное хранилище кэша CACHE_BACKEND: %s", cfg.Cache.Backend)
+	}
+}
@@ -0,0 +1,47 @@
+package entrypoint
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"go.uber.org/zap"
+
+	"github.com/sunr3d/order-stream-processor/internal/config"
+	"github.com/sunr3d/order-stream-processor/internal/infra/kafka"
+)
+
+// ReplayDLQ переотправляет сообщения из DLQ в исходные топики и завершает работу.
+func ReplayDLQ(cfg *config.Config, logger *zap.Logger) error {
+	logger.Info("запуск переотправки сообщений из DLQ...")
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	broker, err := kafka.New(cfg.Kafka, logger)
+	if err != nil {
+		logger.Error("ошибка при подключении к Kafka", zap.Error(err))
+		return fmt.Errorf("kafka.New(): %w", err)
+	}
+	defer func() {
+		if stopper, ok := broker.(interface{ Stop() error }); ok {
+			if err := stopper.Stop(); err != nil {
+				logger.Error("ошибка при закрытии соединения с Kafka", zap.Error(err))
+			}
+		}
+	}()
+
+	replayed, err := broker.ReplayDLQ(ctx)
+	if err != nil {
+		logger.Error("ошибка при переотправке сообщений из DLQ",
+			zap.Int("replayed", replayed),
+			zap.Error(err),
+		)
+		return fmt.Errorf("broker.ReplayDLQ(): %w", err)
+	}
+
+	logger.Info("сообщения из DLQ переотправлены", zap.Int("replayed", replayed))
+	return nil
+}
@@ -13,13 +13,19 @@ import (
 	"github.com/sunr3d/order-stream-processor/internal/config"
 	http_handlers "github.com/sunr3d/order-stream-processor/internal/handlers/http"
 	kafka_handlers "github.com/sunr3d/order-stream-processor/internal/handlers/kafka"
-	"github.com/sunr3d/order-stream-processor/internal/infra/inmem"
+	"github.com/sunr3d/order-stream-processor/internal/handlers/validators"
+	"github.com/sunr3d/order-stream-processor/internal/health"
 	"github.com/sunr3d/order-stream-processor/internal/infra/kafka"
 	"github.com/sunr3d/order-stream-processor/internal/infra/postgres"
+	"github.com/sunr3d/order-stream-processor/internal/infra/snapshot"
 	"github.com/sunr3d/order-stream-processor/internal/interfaces/infra"
+	"github.com/sunr3d/order-stream-processor/internal/metrics"
 	"github.com/sunr3d/order-stream-processor/internal/middleware"
 	"github.com/sunr3d/order-stream-processor/internal/server"
+	"github.com/sunr3d/order-stream-processor/internal/services/cache_invalidator"
+	"github.com/sunr3d/order-stream-processor/internal/services/cache_warmer"
 	"github.com/sunr3d/order-stream-processor/internal/services/order_service"
+	"github.com/sunr3d/order-stream-processor/internal/services/outbox_relay"
 )
 
 func Run(cfg *config.Config, logger *zap.Logger) error {
@@ -28,7 +34,21 @@ func Run(cfg *config.Config, logger *zap.Logger) error {
 	appCtx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
 	defer stop()
 
+	// Режимы правил проверяются до подключения к БД и Kafka
+	validator, err := validators.New(cfg.Validation, logger)
+	if err != nil {
+		logger.Error("некорректные режимы правил согласованности заказов", zap.Error(err))
+		return fmt.Errorf("validators.New(): %w", err)
+	}
+
 	/// Инфра слой
+	if cfg.Postgres.MigrateOnStart {
+		if err := migrateUp(appCtx, cfg.Postgres, logger); err != nil {
+			logger.Error("ошибка при применении миграций", zap.Error(err))
+			return fmt.Errorf("migrateUp(): %w", err)
+		}
+	}
+
 	db, err := postgres.New(cfg.Postgres, logger)
 	if err != nil {
 		logger.Error("ошибка при подключении к БД", zap.Error(err))
@@ -44,7 +64,32 @@ func Run(cfg *config.Config, logger *zap.Logger) error {
 		}
 	}(db)
 
-	cache := inmem.New(logger)
+	cache, err := newCache(cfg, logger)
+	if err != nil {
+		logger.Error("ошибка при создании кэша", zap.Error(err))
+		return fmt.Errorf("newCache(): %w", err)
+	}
+	defer func(cache infra.Cache) {
+		if closer, ok := cache.(interface{ Close() error }); ok {
+			if err := closer.Close(); err != nil {
+				logger.Error("ошибка при закрытии соединения с кэшем", zap.Error(err))
+			} else {
+				logger.Info("соединение с кэшем закрыто")
+			}
+		}
+	}(cache)
+
+	/// Метрики
+	if provider, ok := db.(infra.DBStatsProvider); ok {
+		if err := metrics.RegisterDB(provider); err != nil {
+			logger.Warn("не удалось зарегистрировать метрики БД", zap.Error(err))
+		}
+	}
+	if provider, ok := cache.(infra.CacheStatsProvider); ok {
+		if err := metrics.RegisterCache(provider); err != nil {
+			logger.Warn("не удалось зарегистрировать метрики кэша", zap.Error(err))
+		}
+	}
 
 	broker, err := kafka.New(cfg.Kafka, logger)
 	if err != nil {
@@ -61,34 +106,120 @@ func Run(cfg *config.Config, logger *zap.Logger) error {
 		}
 	}(broker)
 
+	/// Проверки готовности
+	checker := health.New(cfg.HealthTimeout)
+	if hc, ok := db.(infra.HealthChecker); ok {
+		checker.Add("postgres", true, hc.HealthCheck)
+	}
+	if hc, ok := broker.(infra.HealthChecker); ok {
+		checker.Add("kafka", true, hc.HealthCheck)
+	}
+	// Без кэша заказы читаются из БД, поэтому недоступность Redis не снимает готовность
+	if hc, ok := cache.(infra.HealthChecker); ok {
+		checker.Add("cache", false, hc.HealthCheck)
+	}
+	if hc, ok := broker.(interface {
+		ConsumerHealthCheck(ctx context.Context) error
+	}); ok {
+		checker.Add("kafka_consumer", true, hc.ConsumerHealthCheck)
+	}
+	cacheWarm := health.NewFlag("восстановление кэша не завершено")
+	checker.Add("cache_warmup", true, cacheWarm.Check)
+
 	/// Сервисный слой
-	svc := order_service.New(db, cache, logger)
-	go func() {
-		logger.Info("восстановление кэша заказов...")
-		if _, err := svc.GetAllOrders(appCtx); err != nil {
-			logger.Warn("ошибка восстановления кэша", zap.Error(err))
+	svc := order_service.New(db, cache, cfg.Cache, logger)
+
+	var snapshots infra.SnapshotStore
+	if cfg.Cache.SnapshotPath != "" {
+		if _, ok := cache.(infra.CacheDumper); ok {
+			snapshots = snapshot.New(cfg.Cache.SnapshotPath, logger)
 		} else {
-			logger.Info("кэш заказов восстановлен")
+			logger.Warn("кэш не поддерживает снимки, CACHE_SNAPSHOT_PATH игнорируется",
+				zap.String("backend", cfg.Cache.Backend))
 		}
+	}
+	warmer := cache_warmer.New(db, cache, snapshots, cfg.Cache, logger)
+	warmerDone := make(chan struct{})
+	go func() {
+		defer close(warmerDone)
+
+		func() {
+			// Ошибка восстановления не блокирует готовность: заказы читаются из БД
+			defer cacheWarm.Set(true)
+
+			logger.Info("восстановление кэша заказов...")
+			if err := warmer.Warm(appCtx); err != nil {
+				logger.Warn("ошибка восстановления кэша", zap.Error(err))
+			} else {
+				logger.Info("кэш заказов восстановлен")
+			}
+		}()
+
+		warmer.Run(appCtx)
 	}()
+	// Снимок кэша при остановке должен быть записан до закрытия соединений и выхода из процесса
+	defer func() {
+		stop()
+		<-warmerDone
+	}()
+
+	/// Инвалидация кэша по изменениям на других репликах
+	if cfg.Cache.Invalidation.Enabled {
+		listener := postgres.NewListener(cfg.Postgres, cfg.Cache.Invalidation, logger)
+		invalidator := cache_invalidator.New(listener, db, svc, cfg.Cache, logger)
+		go invalidator.Run(appCtx)
+	}
+
+	/// Outbox relay
+	if cfg.Outbox.Enabled {
+		outbox, ok := db.(infra.Outbox)
+		if !ok {
+			return fmt.Errorf("хранилище не поддерживает outbox")
+		}
+		relay := outbox_relay.New(outbox, broker, cfg.Kafka.EventsTopic, cfg.Outbox, logger)
+		go relay.Run(appCtx)
+	}
 
 	/// HTTP слой
-	controller := http_handlers.New(svc, logger)
+	controller := http_handlers.New(svc, validator, logger)
 	mux := http.NewServeMux()
 	controller.RegisterOrderHandlers(mux)
+	http_handlers.NewHealth(checker, logger).RegisterHealthHandlers(mux)
+	mux.Handle("GET /metrics", metrics.Handler())
 
 	// Middleware
-	handler := middleware.Recovery(logger)(
-		middleware.ReqLogger(logger)(
-			middleware.JSONValidator(logger)(mux),
+	var routes http.Handler = mux
+	if cfg.IdempotencyTTL > 0 {
+		routes = middleware.Idempotency(middleware.NewIdempotencyStore(cfg.IdempotencyTTL, cfg.IdempotencyMaxEntries), cfg.IdempotencyMaxBody, logger)(mux)
+	}
+	handler := middleware.Metrics()(
+		middleware.Recovery(logger)(
+			middleware.ReqLogger(logger)(
+				middleware.JSONValidator(logger)(routes),
+			),
 		),
 	)
 
 	/// Kafka консьюмер
-	consumerHandler := kafka_handlers.New(svc, logger)
+	consumerHandler := kafka_handlers.New(svc, validator, logger)
+
+	var ordersSub infra.Subscription
+	switch cfg.Kafka.OrdersMode {
+	case kafka_handlers.OrdersModeCreate, "":
+		ordersSub = infra.Subscription{Topic: cfg.Kafka.Topic, Handler: consumerHandler.CreateOrder, BatchHandler: consumerHandler.CreateOrders}
+	case kafka_handlers.OrdersModeUpsert:
+		// Пакетного upsert нет, заказы обрабатываются по одному
+		ordersSub = infra.Subscription{Topic: cfg.Kafka.Topic, Handler: consumerHandler.UpsertOrder}
+	default:
+		return fmt.Errorf("неизвестный режим обработки заказов KAFKA_ORDERS_MODE: %s", cfg.Kafka.OrdersMode)
+	}
 
 	go func() {
-		if err := broker.StartConsumer(appCtx, consumerHandler.CreateOrder); err != nil {
+		subs := []infra.Subscription{
+			ordersSub,
+			{Topic: cfg.Kafka.StatusTopic, Handler: consumerHandler.ChangeStatus},
+		}
+		if err := broker.StartConsumer(appCtx, subs...); err != nil {
 			logger.Error("ошибка при запуске консьюмера Kafka", zap.Error(err))
 		}
 	}()
@@ -0,0 +1,122 @@
+package entrypoint
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"strconv"
+	"syscall"
+	"text/tabwriter"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/sunr3d/order-stream-processor/internal/config"
+	"github.com/sunr3d/order-stream-processor/internal/infra/postgres"
+	"github.com/sunr3d/order-stream-processor/migrations"
+)
+
+const migrateUsage = "использование: migrate up | down [N] | status | to <версия>"
+
+// Migrate выполняет команду управления миграциями схемы БД и завершает работу.
+func Migrate(cfg *config.Config, logger *zap.Logger, args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("не указана команда миграции, %s", migrateUsage)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	db, err := postgres.Open(cfg.Postgres)
+	if err != nil {
+		logger.Error("ошибка при подключении к БД", zap.Error(err))
+		return fmt.Errorf("postgres.Open(): %w", err)
+	}
+	defer db.Close()
+
+	migrator, err := postgres.NewMigrator(db, migrations.FS, logger)
+	if err != nil {
+		return fmt.Errorf("postgres.NewMigrator(): %w", err)
+	}
+
+	var done int
+	switch args[0] {
+	case "up":
+		done, err = migrator.Up(ctx)
+	case "down":
+		steps := 1
+		if len(args) > 1 {
+			if steps, err = strconv.Atoi(args[1]); err != nil {
+				return fmt.Errorf("некорректное число миграций %q, %s", args[1], migrateUsage)
+			}
+		}
+		done, err = migrator.Down(ctx, steps)
+	case "to":
+		if len(args) < 2 {
+			return fmt.Errorf("не указана версия, %s", migrateUsage)
+		}
+		version, parseErr := strconv.ParseInt(args[1], 10, 64)
+		if parseErr != nil {
+			return fmt.Errorf("некорректная версия %q, %s", args[1], migrateUsage)
+		}
+		done, err = migrator.To(ctx, version)
+	case "status":
+		return printMigrationStatus(ctx, migrator)
+	default:
+		return fmt.Errorf("неизвестная команда миграции %q, %s", args[0], migrateUsage)
+	}
+
+	if err != nil {
+		logger.Error("ошибка при выполнении миграций", zap.Int("done", done), zap.Error(err))
+		return fmt.Errorf("migrate %s: %w", args[0], err)
+	}
+
+	logger.Info("миграции выполнены", zap.String("command", args[0]), zap.Int("done", done))
+	return nil
+}
+
+// migrateUp применяет непримененные миграции при старте сервиса.
+func migrateUp(ctx context.Context, cfg config.PostgresConfig, logger *zap.Logger) error {
+	db, err := postgres.Open(cfg)
+	if err != nil {
+		return fmt.Errorf("postgres.Open(): %w", err)
+	}
+	defer db.Close()
+
+	migrator, err := postgres.NewMigrator(db, migrations.FS, logger)
+	if err != nil {
+		return fmt.Errorf("postgres.NewMigrator(): %w", err)
+	}
+
+	done, err := migrator.Up(ctx)
+	if err != nil {
+		return fmt.Errorf("migrator.Up(): %w", err)
+	}
+
+	logger.Info("схема БД актуальна", zap.Int64("version", migrator.Latest()), zap.Int("applied", done))
+	return nil
+}
+
+func printMigrationStatus(ctx context.Context, migrator *postgres.Migrator) error {
+	statuses, err := migrator.Status(ctx)
+	if err != nil {
+		return fmt.Errorf("migrator.Status(): %w", err)
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "VERSION\tNAME\tSTATUS\tAPPLIED AT")
+	for _, st := range statuses {
+		status, appliedAt := "pending", ""
+		if st.Applied {
+			status, appliedAt = "applied", st.AppliedAt.Format(time.RFC3339)
+		}
+		name := st.Name
+		if st.Unknown {
+			name = "(нет в бинарнике)"
+		}
+		fmt.Fprintf(w, "%d\t%s\t%s\t%s\n", st.Version, name, status, appliedAt)
+	}
+
+	return w.Flush()
+}
@@ -0,0 +1,104 @@
+package http_handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"go.uber.org/zap"
+
+	"github.com/sunr3d/order-stream-processor/internal/handlers/validators"
+	"github.com/sunr3d/order-stream-processor/internal/httpx"
+	"github.com/sunr3d/order-stream-processor/models"
+)
+
+// deleteOrder удаляет заказ. Инициатор и причина передаются параметрами requested_by и reason
+// и попадают в журнал аудита.
+func (h *httpHandler) deleteOrder(w http.ResponseWriter, r *http.Request) {
+	logger := h.logger.With(
+		zap.String("op", "handlers.deleteOrder"),
+		zap.String("order_uid", r.PathValue("order_uid")),
+	)
+
+	logger.Info("получен запрос на удаление заказа")
+
+	query := r.URL.Query()
+	req := models.DeletionRequest{
+		OrderUID:    r.PathValue("order_uid"),
+		RequestedBy: query.Get("requested_by"),
+		Reason:      query.Get("reason"),
+	}
+
+	if err := validators.ValidateDeletion(&req); err != nil {
+		logger.Error("ошибка валидации запроса", zap.Error(err))
+		writeValidationError(w, err)
+		return
+	}
+
+	if err := h.svc.DeleteOrder(r.Context(), req); err != nil {
+		logger.Error("ошибка при удалении заказа", zap.Error(err))
+		code, msg := errorResponse(err)
+		_ = httpx.HttpError(w, code, msg)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+
+	logger.Info("заказ успешно удален", zap.String("requested_by", req.RequestedBy))
+}
+
+// eraseCustomer обезличивает персональные данные получателя во всех заказах покупателя.
+func (h *httpHandler) eraseCustomer(w http.ResponseWriter, r *http.Request) {
+	logger := h.logger.With(
+		zap.String("op", "handlers.eraseCustomer"),
+		zap.String("customer_id", r.PathValue("customer_id")),
+	)
+
+	logger.Info("получен запрос на обезличивание данных покупателя")
+
+	var body eraseCustomerReq
+
+	decoder := json.NewDecoder(r.Body)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&body); err != nil {
+		logger.Error("некорректный JSON", zap.Error(err))
+		_ = httpx.HttpError(w, http.StatusBadRequest, "Некорректный JSON")
+		return
+	}
+
+	req := models.ErasureRequest{
+		CustomerID:  r.PathValue("customer_id"),
+		RequestedBy: body.RequestedBy,
+		Reason:      body.Reason,
+	}
+
+	if err := validators.ValidateErasure(&req); err != nil {
+		logger.Error("ошибка валидации запроса", zap.Error(err))
+		writeValidationError(w, err)
+		return
+	}
+
+	result, err := h.svc.EraseCustomer(r.Context(), req)
+	if err != nil {
+		logger.Error("ошибка при обезличивании данных покупателя", zap.Error(err))
+		code, msg := errorResponse(err)
+		_ = httpx.HttpError(w, code, msg)
+		return
+	}
+
+	if err := httpx.WriteJSON(w, http.StatusOK, result); err != nil {
+		switch {
+		case errors.Is(err, httpx.ErrJSONMarshal):
+			logger.Error("ошибка при отправке ответа", zap.Error(err))
+			_ = httpx.HttpError(w, http.StatusInternalServerError, "Внутреняя ошибка сервера")
+		case errors.Is(err, httpx.ErrWriteBody):
+			logger.Warn("клиент закрыл соединение, ответ не отправлен", zap.Error(err))
+		}
+		return
+	}
+
+	logger.Info("данные покупателя успешно обезличены",
+		zap.Int("orders", len(result.OrderUIDs)),
+		zap.Int64("audit_id", result.AuditID),
+	)
+}
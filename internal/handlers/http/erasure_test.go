@@ -0,0 +1,116 @@
+package http_handlers_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/sunr3d/order-stream-processor/internal/config"
+	http_handlers "github.com/sunr3d/order-stream-processor/internal/handlers/http"
+	"github.com/sunr3d/order-stream-processor/mocks"
+	"github.com/sunr3d/order-stream-processor/models"
+)
+
+func doRequest(t *testing.T, svc *mocks.OrderService, method, path, body string) *http.Response {
+	t.Helper()
+
+	mux := http.NewServeMux()
+	http_handlers.New(svc, newValidator(t, config.ValidationConfig{}), zap.NewNop()).RegisterOrderHandlers(mux)
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	req, err := http.NewRequest(method, server.URL+path, bytes.NewBufferString(body))
+	require.NoError(t, err)
+	if body != "" {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	t.Cleanup(func() { resp.Body.Close() })
+	return resp
+}
+
+func violationPaths(t *testing.T, resp *http.Response) []string {
+	t.Helper()
+
+	var body struct {
+		Violations []models.Violation `json:"violations"`
+	}
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+
+	paths := make([]string, 0, len(body.Violations))
+	for _, v := range body.Violations {
+		paths = append(paths, v.Path)
+	}
+	return paths
+}
+
+// deleteOrder Handler Tests
+func TestHandler_DeleteOrder_OK(t *testing.T) {
+	svc := &mocks.OrderService{}
+	expected := models.DeletionRequest{OrderUID: "test-123", RequestedBy: "dpo", Reason: "gdpr"}
+	svc.On("DeleteOrder", mock.Anything, expected).Return(nil)
+
+	resp := doRequest(t, svc, http.MethodDelete, "/order/test-123?requested_by=dpo&reason=gdpr", "")
+
+	assert.Equal(t, http.StatusNoContent, resp.StatusCode)
+	svc.AssertExpectations(t)
+}
+
+func TestHandler_DeleteOrder_Error_NoRequestedBy(t *testing.T) {
+	svc := &mocks.OrderService{}
+
+	resp := doRequest(t, svc, http.MethodDelete, "/order/test-123", "")
+
+	assert.Equal(t, http.StatusUnprocessableEntity, resp.StatusCode)
+	assert.Equal(t, []string{"/requested_by"}, violationPaths(t, resp))
+	svc.AssertNotCalled(t, "DeleteOrder")
+}
+
+func TestHandler_DeleteOrder_Error_NotFound(t *testing.T) {
+	svc := &mocks.OrderService{}
+	svc.On("DeleteOrder", mock.Anything, mock.AnythingOfType("models.DeletionRequest")).
+		Return(fmt.Errorf("repo.Delete: %w", models.ErrOrderNotFound))
+
+	resp := doRequest(t, svc, http.MethodDelete, "/order/test-123?requested_by=dpo", "")
+
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+}
+
+// eraseCustomer Handler Tests
+func TestHandler_EraseCustomer_OK(t *testing.T) {
+	svc := &mocks.OrderService{}
+	expected := models.ErasureRequest{CustomerID: "test", RequestedBy: "dpo", Reason: "запрос покупателя"}
+	svc.On("EraseCustomer", mock.Anything, expected).
+		Return(&models.ErasureResult{CustomerID: "test", OrderUIDs: []string{"a", "b"}, AuditID: 7}, nil)
+
+	resp := doRequest(t, svc, http.MethodPost, "/customers/test/erase",
+		`{"requested_by":"dpo","reason":"запрос покупателя"}`)
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var result models.ErasureResult
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&result))
+	assert.Equal(t, []string{"a", "b"}, result.OrderUIDs)
+	assert.Equal(t, int64(7), result.AuditID)
+	svc.AssertExpectations(t)
+}
+
+func TestHandler_EraseCustomer_Error_Validation(t *testing.T) {
+	svc := &mocks.OrderService{}
+
+	resp := doRequest(t, svc, http.MethodPost, "/customers/test/erase", `{"reason":"запрос покупателя"}`)
+
+	assert.Equal(t, http.StatusUnprocessableEntity, resp.StatusCode)
+	assert.Equal(t, []string{"/requested_by"}, violationPaths(t, resp))
+	svc.AssertNotCalled(t, "EraseCustomer")
+}
@@ -0,0 +1,46 @@
+package http_handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/sunr3d/order-stream-processor/internal/httpx"
+	"github.com/sunr3d/order-stream-processor/models"
+)
+
+// errorResponse сопоставляет доменную ошибку с HTTP статусом и текстом ответа.
+func errorResponse(err error) (int, string) {
+	switch {
+	case errors.Is(err, models.ErrValidation):
+		return http.StatusBadRequest, err.Error()
+	case errors.Is(err, models.ErrOrderNotFound):
+		return http.StatusNotFound, "Заказ не найден"
+	case errors.Is(err, models.ErrOrderAlreadyExists):
+		return http.StatusConflict, "Заказ уже существует"
+	case errors.Is(err, models.ErrOrderErased):
+		return http.StatusGone, "Заказ удален или обезличен"
+	case errors.Is(err, models.ErrVersionConflict):
+		return http.StatusPreconditionFailed, "Заказ был изменен, получите актуальную версию"
+	case errors.Is(err, models.ErrInvalidTransition):
+		return http.StatusConflict, "Недопустимая смена статуса заказа"
+	case errors.Is(err, models.ErrUnavailable):
+		return http.StatusServiceUnavailable, "Сервис временно недоступен"
+	default:
+		return http.StatusInternalServerError, "Внутреняя ошибка сервера"
+	}
+}
+
+// writeValidationError отвечает 422 со всеми нарушениями, если err содержит *models.ValidationError,
+// остальные ошибки сопоставляются через errorResponse.
+func writeValidationError(w http.ResponseWriter, err error) {
+	var verr *models.ValidationError
+	if errors.As(err, &verr) {
+		_ = httpx.WriteJSON(w, http.StatusUnprocessableEntity, validationResp{
+			Error:      "Запрос не прошел валидацию",
+			Violations: verr.Violations,
+		})
+		return
+	}
+	code, msg := errorResponse(err)
+	_ = httpx.HttpError(w, code, msg)
+}
@@ -0,0 +1,35 @@
+package http_handlers
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/sunr3d/order-stream-processor/models"
+)
+
+// etag возвращает сильный ETag заказа по его версии.
+func etag(version int64) string {
+	return strconv.Quote(strconv.FormatInt(version, 10))
+}
+
+// parseIfMatch возвращает ожидаемую версию из заголовка If-Match; "*" означает любую версию (0).
+// Поддерживается один ETag, слабый префикс W/ допускается.
+func parseIfMatch(header string) (int64, error) {
+	header = strings.TrimSpace(header)
+	if header == "*" {
+		return 0, nil
+	}
+
+	tag := strings.TrimPrefix(header, "W/")
+	unquoted, err := strconv.Unquote(tag)
+	if err != nil {
+		return 0, fmt.Errorf("%w: некорректный If-Match: %s", models.ErrValidation, header)
+	}
+
+	version, err := strconv.ParseInt(unquoted, 10, 64)
+	if err != nil || version <= 0 {
+		return 0, fmt.Errorf("%w: некорректный If-Match: %s", models.ErrValidation, header)
+	}
+	return version, nil
+}
@@ -5,21 +5,32 @@ import (
 
 	"go.uber.org/zap"
 
+	"github.com/sunr3d/order-stream-processor/internal/handlers/validators"
 	"github.com/sunr3d/order-stream-processor/internal/interfaces/services"
 )
 
 // Структура HTTP обработчика
 type httpHandler struct {
-	svc    services.OrderService
-	logger *zap.Logger
+	svc       services.OrderService
+	validator *validators.Validator
+	logger    *zap.Logger
 }
 
-func New(svc services.OrderService, logger *zap.Logger) *httpHandler {
-	return &httpHandler{svc: svc, logger: logger}
+func New(svc services.OrderService, validator *validators.Validator, logger *zap.Logger) *httpHandler {
+	return &httpHandler{svc: svc, validator: validator, logger: logger}
 }
 
 func (h *httpHandler) RegisterOrderHandlers(mux *http.ServeMux) {
 	mux.HandleFunc("POST /order", h.createOrder)
 	mux.HandleFunc("GET /order/{order_uid}", h.getOrder)
+	mux.HandleFunc("PUT /order/{order_uid}", h.updateOrder)
+	mux.HandleFunc("DELETE /order/{order_uid}", h.deleteOrder)
+	mux.HandleFunc("GET /orders", h.listOrders)
+	mux.HandleFunc("GET /orders/by-track/{track_number}", h.getOrdersByTrack)
+	mux.HandleFunc("GET /orders/by-transaction/{transaction}", h.getOrdersByTransaction)
+	mux.HandleFunc("GET /customers/{customer_id}/orders", h.getCustomerOrders)
+	mux.HandleFunc("PATCH /order/{order_uid}/status", h.changeStatus)
+	mux.HandleFunc("GET /order/{order_uid}/history", h.getStatusHistory)
+	mux.HandleFunc("POST /customers/{customer_id}/erase", h.eraseCustomer)
 	mux.HandleFunc("GET /health", h.healthCheck)
 }
@@ -3,6 +3,9 @@ package http_handlers
 import (
 	"net/http"
 
+	"go.uber.org/zap"
+
+	"github.com/sunr3d/order-stream-processor/internal/health"
 	"github.com/sunr3d/order-stream-processor/internal/httpx"
 )
 
@@ -12,3 +15,42 @@ func (h *httpHandler) healthCheck(w http.ResponseWriter, r *http.Request) {
 		"service": "order-stream-processor",
 	})
 }
+
+// Структура обработчика проб liveness/readiness
+type healthHandler struct {
+	checker *health.Checker
+	logger  *zap.Logger
+}
+
+func NewHealth(checker *health.Checker, logger *zap.Logger) *healthHandler {
+	return &healthHandler{checker: checker, logger: logger}
+}
+
+func (h *healthHandler) RegisterHealthHandlers(mux *http.ServeMux) {
+	mux.HandleFunc("GET /livez", h.livez)
+	mux.HandleFunc("GET /readyz", h.readyz)
+}
+
+// livez сообщает, что процесс жив и обслуживает запросы; зависимости не проверяются,
+// чтобы недоступность БД или Kafka не приводила к перезапуску сервиса.
+func (h *healthHandler) livez(w http.ResponseWriter, r *http.Request) {
+	httpx.WriteJSON(w, http.StatusOK, map[string]string{"status": health.StatusOK})
+}
+
+// readyz проверяет зависимости и возвращает 503, если неисправен хотя бы один критичный компонент.
+func (h *healthHandler) readyz(w http.ResponseWriter, r *http.Request) {
+	report := h.checker.Check(r.Context())
+
+	code := http.StatusOK
+	if !report.Healthy() {
+		code = http.StatusServiceUnavailable
+		h.logger.Warn("сервис не готов",
+			zap.String("op", "http.readyz"),
+			zap.Any("components", report.Components),
+		)
+	}
+
+	if err := httpx.WriteJSON(w, code, report); err != nil {
+		h.logger.Warn("не удалось записать ответ", zap.String("op", "http.readyz"), zap.Error(err))
+	}
+}
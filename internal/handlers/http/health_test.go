@@ -0,0 +1,66 @@
+package http_handlers_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+
+	http_handlers "github.com/sunr3d/order-stream-processor/internal/handlers/http"
+	"github.com/sunr3d/order-stream-processor/internal/health"
+)
+
+func serveHealth(checker *health.Checker, path string) *httptest.ResponseRecorder {
+	mux := http.NewServeMux()
+	http_handlers.NewHealth(checker, zap.NewNop()).RegisterHealthHandlers(mux)
+
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+	return rec
+}
+
+func TestHealthHandler_Readyz_OK(t *testing.T) {
+	checker := health.New(time.Second)
+	checker.Add("postgres", true, func(context.Context) error { return nil })
+
+	rec := serveHealth(checker, "/readyz")
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	var report health.Report
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &report))
+	assert.Equal(t, health.StatusOK, report.Status)
+	assert.Equal(t, health.StatusOK, report.Components["postgres"].Status)
+}
+
+func TestHealthHandler_Readyz_Unavailable(t *testing.T) {
+	checker := health.New(time.Second)
+	checker.Add("postgres", true, func(context.Context) error { return errors.New("connection refused") })
+	checker.Add("cache_warmup", true, func(context.Context) error { return nil })
+
+	rec := serveHealth(checker, "/readyz")
+
+	assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+
+	var report health.Report
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &report))
+	assert.Equal(t, health.StatusUnhealthy, report.Status)
+	assert.Equal(t, "connection refused", report.Components["postgres"].Error)
+	assert.Equal(t, health.StatusOK, report.Components["cache_warmup"].Status)
+}
+
+func TestHealthHandler_Livez_IgnoresDependencies(t *testing.T) {
+	checker := health.New(time.Second)
+	checker.Add("postgres", true, func(context.Context) error { return errors.New("connection refused") })
+
+	rec := serveHealth(checker, "/livez")
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.JSONEq(t, `{"status":"ok"}`, rec.Body.String())
+}
@@ -0,0 +1,102 @@
+package http_handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"go.uber.org/zap"
+
+	"github.com/sunr3d/order-stream-processor/internal/httpx"
+	"github.com/sunr3d/order-stream-processor/models"
+)
+
+// getOrdersByTrack возвращает заказы с заданным трек-номером, от новых к старым.
+func (h *httpHandler) getOrdersByTrack(w http.ResponseWriter, r *http.Request) {
+	h.lookupOrders(w, r, models.OrderLookup{
+		Field: models.LookupTrackNumber,
+		Value: r.PathValue("track_number"),
+		Limit: maxListLimit,
+	}, true)
+}
+
+// getOrdersByTransaction возвращает заказы с заданным идентификатором платежа, от новых к старым.
+func (h *httpHandler) getOrdersByTransaction(w http.ResponseWriter, r *http.Request) {
+	h.lookupOrders(w, r, models.OrderLookup{
+		Field: models.LookupTransaction,
+		Value: r.PathValue("transaction"),
+		Limit: maxListLimit,
+	}, true)
+}
+
+// getCustomerOrders возвращает последние заказы покупателя; количество задается параметром limit.
+// Постраничный список с фильтрами — GET /orders?customer_id=.
+func (h *httpHandler) getCustomerOrders(w http.ResponseWriter, r *http.Request) {
+	lookup := models.OrderLookup{
+		Field: models.LookupCustomer,
+		Value: r.PathValue("customer_id"),
+	}
+
+	limit, err := parseLimit(r.URL.Query())
+	if err != nil {
+		h.logger.Error("некорректные параметры запроса",
+			zap.String("op", "handlers.getCustomerOrders"),
+			zap.Error(err),
+		)
+		code, msg := errorResponse(err)
+		_ = httpx.HttpError(w, code, msg)
+		return
+	}
+	lookup.Limit = limit
+
+	h.lookupOrders(w, r, lookup, false)
+}
+
+// lookupOrders ищет заказы по вторичному ключу. Если notFoundIfEmpty, пустой результат — 404:
+// трек-номер и идентификатор платежа указывают на конкретные заказы, а у покупателя заказов может не быть.
+func (h *httpHandler) lookupOrders(w http.ResponseWriter, r *http.Request, lookup models.OrderLookup, notFoundIfEmpty bool) {
+	logger := h.logger.With(
+		zap.String("op", "handlers.lookupOrders"),
+		zap.String("field", string(lookup.Field)),
+		zap.String("value", lookup.Value),
+	)
+
+	logger.Info("получен запрос на поиск заказов")
+
+	result, err := h.svc.LookupOrders(r.Context(), lookup)
+	if err != nil {
+		logger.Error("ошибка при поиске заказов", zap.Error(err))
+		code, msg := errorResponse(err)
+		_ = httpx.HttpError(w, code, msg)
+		return
+	}
+
+	if len(result.Orders) == 0 && notFoundIfEmpty {
+		logger.Info("заказы не найдены")
+		_ = httpx.HttpError(w, http.StatusNotFound, "Заказ не найден")
+		return
+	}
+
+	resp := lookupOrdersResp{
+		Orders:    result.Orders,
+		FromCache: result.FromCache,
+	}
+	if resp.Orders == nil {
+		resp.Orders = []*models.Order{}
+	}
+
+	if err := httpx.WriteJSON(w, http.StatusOK, resp); err != nil {
+		switch {
+		case errors.Is(err, httpx.ErrJSONMarshal):
+			logger.Error("ошибка при отправке ответа", zap.Error(err))
+			_ = httpx.HttpError(w, http.StatusInternalServerError, "Внутреняя ошибка сервера")
+		case errors.Is(err, httpx.ErrWriteBody):
+			logger.Warn("клиент закрыл соединение, ответ не отправлен", zap.Error(err))
+		}
+		return
+	}
+
+	logger.Info("заказы успешно найдены",
+		zap.Int("count", len(result.Orders)),
+		zap.Bool("from_cache", result.FromCache),
+	)
+}
@@ -0,0 +1,89 @@
+package http_handlers_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sunr3d/order-stream-processor/mocks"
+	"github.com/sunr3d/order-stream-processor/models"
+)
+
+// lookupOrders Handler Tests
+func TestHandler_GetOrdersByTrack_OK(t *testing.T) {
+	svc := &mocks.OrderService{}
+	expected := models.OrderLookup{Field: models.LookupTrackNumber, Value: "TRACK-1", Limit: 100}
+	svc.On("LookupOrders", mock.Anything, expected).
+		Return(&models.LookupResult{Orders: []*models.Order{{OrderUID: "a"}}}, nil)
+
+	resp := doRequest(t, svc, http.MethodGet, "/orders/by-track/TRACK-1", "")
+
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	var body map[string]any
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+	assert.Len(t, body["orders"], 1)
+	assert.NotContains(t, body, "from_cache")
+	svc.AssertExpectations(t)
+}
+
+func TestHandler_GetOrdersByTransaction_NotFound(t *testing.T) {
+	svc := &mocks.OrderService{}
+	svc.On("LookupOrders", mock.Anything, mock.MatchedBy(func(l models.OrderLookup) bool {
+		return l.Field == models.LookupTransaction && l.Value == "tx-1"
+	})).Return(&models.LookupResult{}, nil)
+
+	resp := doRequest(t, svc, http.MethodGet, "/orders/by-transaction/tx-1", "")
+
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+}
+
+func TestHandler_GetOrdersByTrack_FromCache(t *testing.T) {
+	svc := &mocks.OrderService{}
+	svc.On("LookupOrders", mock.Anything, mock.AnythingOfType("models.OrderLookup")).
+		Return(&models.LookupResult{Orders: []*models.Order{{OrderUID: "a"}}, FromCache: true}, nil)
+
+	resp := doRequest(t, svc, http.MethodGet, "/orders/by-track/TRACK-1", "")
+
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	var body map[string]any
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+	assert.Equal(t, true, body["from_cache"])
+}
+
+func TestHandler_GetCustomerOrders_EmptyOK(t *testing.T) {
+	svc := &mocks.OrderService{}
+	expected := models.OrderLookup{Field: models.LookupCustomer, Value: "customer-1", Limit: 5}
+	svc.On("LookupOrders", mock.Anything, expected).Return(&models.LookupResult{}, nil)
+
+	resp := doRequest(t, svc, http.MethodGet, "/customers/customer-1/orders?limit=5", "")
+
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	var body map[string]any
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+	assert.Equal(t, []any{}, body["orders"])
+	svc.AssertExpectations(t)
+}
+
+func TestHandler_GetCustomerOrders_Error_InvalidLimit(t *testing.T) {
+	svc := &mocks.OrderService{}
+
+	resp := doRequest(t, svc, http.MethodGet, "/customers/customer-1/orders?limit=1000", "")
+
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	svc.AssertNotCalled(t, "LookupOrders")
+}
+
+func TestHandler_GetCustomerOrders_Error_Unavailable(t *testing.T) {
+	svc := &mocks.OrderService{}
+	svc.On("LookupOrders", mock.Anything, mock.AnythingOfType("models.OrderLookup")).
+		Return((*models.LookupResult)(nil), fmt.Errorf("repo.Lookup: %w", models.ErrUnavailable))
+
+	resp := doRequest(t, svc, http.MethodGet, "/customers/customer-1/orders", "")
+
+	assert.Equal(t, http.StatusServiceUnavailable, resp.StatusCode)
+}
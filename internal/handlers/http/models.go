@@ -6,11 +6,52 @@ import (
 
 type createOrderReq = models.Order
 
+type updateOrderReq = models.Order
+
 type createOrderResp struct {
 	OrderUID string `json:"order_uid"`
 	Message  string `json:"message"`
 }
 
+// conflictResp — ответ на создание заказа, который уже сохранен с другими данными.
+type conflictResp struct {
+	Error string             `json:"error"`
+	Diff  []models.FieldDiff `json:"diff"`
+}
+
+// validationResp — ответ на заказ, не прошедший валидацию, со всеми нарушениями.
+type validationResp struct {
+	Error      string             `json:"error"`
+	Violations []models.Violation `json:"violations"`
+}
+
 type getOrderResp struct {
 	Order *models.Order `json:"order"`
 }
+
+type listOrdersResp struct {
+	Orders     []*models.Order `json:"orders"`
+	NextCursor string          `json:"next_cursor,omitempty"`
+}
+
+// lookupOrdersResp — ответ на поиск по вторичному ключу. FromCache означает, что БД недоступна
+// и список собран из кэша: в нем могут быть не все заказы.
+type lookupOrdersResp struct {
+	Orders    []*models.Order `json:"orders"`
+	FromCache bool            `json:"from_cache,omitempty"`
+}
+
+type changeStatusReq struct {
+	Status    models.OrderStatus `json:"status"`
+	ChangedBy string             `json:"changed_by"`
+	Reason    string             `json:"reason"`
+}
+
+type statusHistoryResp struct {
+	History []models.StatusChange `json:"history"`
+}
+
+type eraseCustomerReq struct {
+	RequestedBy string `json:"requested_by"`
+	Reason      string `json:"reason"`
+}
@@ -4,12 +4,11 @@ import (
 	"encoding/json"
 	"errors"
 	"net/http"
-	"strings"
 
 	"go.uber.org/zap"
 
-	"github.com/sunr3d/order-stream-processor/internal/handlers/validators"
 	"github.com/sunr3d/order-stream-processor/internal/httpx"
+	"github.com/sunr3d/order-stream-processor/models"
 )
 
 func (h *httpHandler) createOrder(w http.ResponseWriter, r *http.Request) {
@@ -27,9 +26,10 @@ func (h *httpHandler) createOrder(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if err := validators.ValidateOrder(&req); err != nil {
+	h.validator.Normalize(&req)
+	if err := h.validator.ValidateOrder(&req); err != nil {
 		logger.Error("ошибка валидации запроса", zap.Error(err))
-		_ = httpx.HttpError(w, http.StatusBadRequest, err.Error())
+		writeValidationError(w, err)
 		return
 	}
 
@@ -37,13 +37,16 @@ func (h *httpHandler) createOrder(w http.ResponseWriter, r *http.Request) {
 
 	if err := h.svc.ProcessOrder(r.Context(), &req); err != nil {
 		logger.Error("ошибка при обработке заказа", zap.Error(err))
-
-		if strings.Contains(err.Error(), "уже существует") {
-			logger.Info("заказ уже существует в БД")
-			_ = httpx.HttpError(w, http.StatusConflict, "Заказ уже существует")
-		} else {
-			_ = httpx.HttpError(w, http.StatusInternalServerError, "Внутреняя ошибка сервера")
+		var conflict *models.ConflictError
+		if errors.As(err, &conflict) {
+			_ = httpx.WriteJSON(w, http.StatusConflict, conflictResp{
+				Error: "Заказ уже существует с другими данными",
+				Diff:  conflict.Diff,
+			})
+			return
 		}
+		code, msg := errorResponse(err)
+		_ = httpx.HttpError(w, code, msg)
 		return
 	}
 
@@ -82,12 +85,8 @@ func (h *httpHandler) getOrder(w http.ResponseWriter, r *http.Request) {
 	order, err := h.svc.GetOrder(r.Context(), orderUID)
 	if err != nil {
 		logger.Error("ошибка при получении заказа", zap.Error(err))
-
-		if strings.Contains(err.Error(), "заказ не найден") {
-			_ = httpx.HttpError(w, http.StatusNotFound, "Заказ не найден")
-		} else {
-			_ = httpx.HttpError(w, http.StatusInternalServerError, "Внутреняя ошибка сервера")
-		}
+		code, msg := errorResponse(err)
+		_ = httpx.HttpError(w, code, msg)
 		return
 	}
 
@@ -95,6 +94,7 @@ func (h *httpHandler) getOrder(w http.ResponseWriter, r *http.Request) {
 		Order: order,
 	}
 
+	w.Header().Set("ETag", etag(order.CurrentVersion()))
 	if err := httpx.WriteJSON(w, http.StatusOK, resp); err != nil {
 		switch {
 		case errors.Is(err, httpx.ErrJSONMarshal):
@@ -108,3 +108,46 @@ func (h *httpHandler) getOrder(w http.ResponseWriter, r *http.Request) {
 
 	logger.Info("заказ успешно получен")
 }
+
+func (h *httpHandler) listOrders(w http.ResponseWriter, r *http.Request) {
+	logger := h.logger.With(zap.String("op", "handlers.listOrders"))
+
+	logger.Info("получен запрос на получение списка заказов")
+
+	filter, err := parseOrderFilter(r.URL.Query())
+	if err != nil {
+		logger.Error("некорректные параметры запроса", zap.Error(err))
+		code, msg := errorResponse(err)
+		_ = httpx.HttpError(w, code, msg)
+		return
+	}
+
+	page, err := h.svc.ListOrders(r.Context(), filter)
+	if err != nil {
+		logger.Error("ошибка при получении списка заказов", zap.Error(err))
+		code, msg := errorResponse(err)
+		_ = httpx.HttpError(w, code, msg)
+		return
+	}
+
+	resp := listOrdersResp{
+		Orders:     page.Orders,
+		NextCursor: page.NextCursor,
+	}
+	if resp.Orders == nil {
+		resp.Orders = []*models.Order{}
+	}
+
+	if err := httpx.WriteJSON(w, http.StatusOK, resp); err != nil {
+		switch {
+		case errors.Is(err, httpx.ErrJSONMarshal):
+			logger.Error("ошибка при отправке ответа", zap.Error(err))
+			_ = httpx.HttpError(w, http.StatusInternalServerError, "Внутреняя ошибка сервера")
+		case errors.Is(err, httpx.ErrWriteBody):
+			logger.Warn("клиент закрыл соединение, ответ не отправлен", zap.Error(err))
+		}
+		return
+	}
+
+	logger.Info("список заказов успешно получен", zap.Int("count", len(page.Orders)))
+}
@@ -3,7 +3,7 @@ package http_handlers_test
 import (
 	"bytes"
 	"encoding/json"
-	"errors"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -11,9 +11,12 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 	"go.uber.org/zap"
 
+	"github.com/sunr3d/order-stream-processor/internal/config"
 	http_handlers "github.com/sunr3d/order-stream-processor/internal/handlers/http"
+	"github.com/sunr3d/order-stream-processor/internal/handlers/validators"
 	"github.com/sunr3d/order-stream-processor/mocks"
 	"github.com/sunr3d/order-stream-processor/models"
 )
@@ -64,11 +67,22 @@ func createValidOrder() *models.Order {
 	}
 }
 
+func newValidator(t *testing.T, cfg config.ValidationConfig) *validators.Validator {
+	t.Helper()
+	validator, err := validators.New(cfg, zap.NewNop())
+	require.NoError(t, err)
+	return validator
+}
+
+type getOrderRespJSON struct {
+	Order *models.Order `json:"order"`
+}
+
 // createOrder Handler Tests
 func TestHandler_CreateOrder_OK(t *testing.T) {
 	svc := &mocks.OrderService{}
 	logger := zap.NewNop()
-	controller := http_handlers.New(svc, logger)
+	controller := http_handlers.New(svc, newValidator(t, config.ValidationConfig{}), logger)
 
 	orderData := createValidOrder()
 	jsonData, err := json.Marshal(orderData)
@@ -97,10 +111,45 @@ func TestHandler_CreateOrder_OK(t *testing.T) {
 	svc.AssertExpectations(t)
 }
 
+func TestHandler_CreateOrder_Error_ConsistencyReject(t *testing.T) {
+	svc := &mocks.OrderService{}
+	logger := zap.NewNop()
+	validator := newValidator(t, config.ValidationConfig{AmountTotal: string(validators.ModeReject)})
+	controller := http_handlers.New(svc, validator, logger)
+
+	mux := http.NewServeMux()
+	controller.RegisterOrderHandlers(mux)
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	orderData := createValidOrder()
+	orderData.Payment.Amount = 1000
+	jsonData, err := json.Marshal(orderData)
+	assert.NoError(t, err)
+
+	resp, err := http.Post(server.URL+"/order", "application/json", bytes.NewBuffer(jsonData))
+	assert.NoError(t, err)
+	defer resp.Body.Close()
+
+	assert.Equal(t, http.StatusUnprocessableEntity, resp.StatusCode)
+
+	var respJSON struct {
+		Violations []models.Violation `json:"violations"`
+	}
+	err = json.NewDecoder(resp.Body).Decode(&respJSON)
+	assert.NoError(t, err)
+	if assert.Len(t, respJSON.Violations, 1) {
+		assert.Equal(t, "/payment/amount", respJSON.Violations[0].Path)
+		assert.Equal(t, validators.CodeAmountMismatch, respJSON.Violations[0].Code)
+	}
+
+	svc.AssertNotCalled(t, "ProcessOrder")
+}
+
 func TestHandler_CreateOrder_Error_InvalidJSON(t *testing.T) {
 	svc := &mocks.OrderService{}
 	logger := zap.NewNop()
-	controller := http_handlers.New(svc, logger)
+	controller := http_handlers.New(svc, newValidator(t, config.ValidationConfig{}), logger)
 
 	mux := http.NewServeMux()
 	controller.RegisterOrderHandlers(mux)
@@ -124,7 +173,7 @@ func TestHandler_CreateOrder_Error_InvalidJSON(t *testing.T) {
 func TestHandler_CreateOrder_Error_Validation(t *testing.T) {
 	svc := &mocks.OrderService{}
 	logger := zap.NewNop()
-	controller := http_handlers.New(svc, logger)
+	controller := http_handlers.New(svc, newValidator(t, config.ValidationConfig{}), logger)
 
 	orderData := createValidOrder()
 	orderData.OrderUID = ""
@@ -139,12 +188,18 @@ func TestHandler_CreateOrder_Error_Validation(t *testing.T) {
 	assert.NoError(t, err)
 	defer resp.Body.Close()
 
-	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	assert.Equal(t, http.StatusUnprocessableEntity, resp.StatusCode)
 
-	var respJSON map[string]string
+	var respJSON struct {
+		Error      string             `json:"error"`
+		Violations []models.Violation `json:"violations"`
+	}
 	err = json.NewDecoder(resp.Body).Decode(&respJSON)
 	assert.NoError(t, err)
-	assert.Equal(t, "order_uid не может быть пустым", respJSON["error"])
+	assert.Equal(t, "Запрос не прошел валидацию", respJSON.Error)
+	assert.Equal(t, []models.Violation{
+		{Path: "/order_uid", Code: "required", Value: "", Message: "не может быть пустым"},
+	}, respJSON.Violations)
 
 	svc.AssertNotCalled(t, "ProcessOrder")
 }
@@ -152,12 +207,12 @@ func TestHandler_CreateOrder_Error_Validation(t *testing.T) {
 func TestHandler_CreateOrder_Error_Duplicate(t *testing.T) {
 	svc := &mocks.OrderService{}
 	logger := zap.NewNop()
-	controller := http_handlers.New(svc, logger)
+	controller := http_handlers.New(svc, newValidator(t, config.ValidationConfig{}), logger)
 
 	orderData := createValidOrder()
 	jsonData, _ := json.Marshal(orderData)
 
-	svc.On("ProcessOrder", mock.Anything, mock.AnythingOfType("*models.Order")).Return(errors.New("заказ уже существует"))
+	svc.On("ProcessOrder", mock.Anything, mock.AnythingOfType("*models.Order")).Return(fmt.Errorf("repo.Create: %w", models.ErrOrderAlreadyExists))
 
 	mux := http.NewServeMux()
 	controller.RegisterOrderHandlers(mux)
@@ -178,13 +233,80 @@ func TestHandler_CreateOrder_Error_Duplicate(t *testing.T) {
 	svc.AssertExpectations(t)
 }
 
+func TestHandler_CreateOrder_Error_Erased(t *testing.T) {
+	svc := &mocks.OrderService{}
+	logger := zap.NewNop()
+	controller := http_handlers.New(svc, newValidator(t, config.ValidationConfig{}), logger)
+
+	orderData := createValidOrder()
+	jsonData, _ := json.Marshal(orderData)
+
+	svc.On("ProcessOrder", mock.Anything, mock.AnythingOfType("*models.Order")).Return(fmt.Errorf("repo.Create: %w", models.ErrOrderErased))
+
+	mux := http.NewServeMux()
+	controller.RegisterOrderHandlers(mux)
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	resp, err := http.Post(server.URL+"/order", "application/json", bytes.NewBuffer(jsonData))
+	assert.NoError(t, err)
+	defer resp.Body.Close()
+
+	assert.Equal(t, http.StatusGone, resp.StatusCode)
+
+	var respJSON map[string]string
+	err = json.NewDecoder(resp.Body).Decode(&respJSON)
+	assert.NoError(t, err)
+	assert.Equal(t, "Заказ удален или обезличен", respJSON["error"])
+
+	svc.AssertExpectations(t)
+}
+
+func TestHandler_CreateOrder_Error_Conflict(t *testing.T) {
+	svc := &mocks.OrderService{}
+	logger := zap.NewNop()
+	controller := http_handlers.New(svc, newValidator(t, config.ValidationConfig{}), logger)
+
+	orderData := createValidOrder()
+	jsonData, _ := json.Marshal(orderData)
+
+	conflict := &models.ConflictError{
+		OrderUID: "test-123",
+		Diff:     []models.FieldDiff{{Path: "/delivery/city", Stored: "Moscow", Incoming: "Test City"}},
+	}
+	svc.On("ProcessOrder", mock.Anything, mock.AnythingOfType("*models.Order")).Return(conflict)
+
+	mux := http.NewServeMux()
+	controller.RegisterOrderHandlers(mux)
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	resp, err := http.Post(server.URL+"/order", "application/json", bytes.NewBuffer(jsonData))
+	assert.NoError(t, err)
+	defer resp.Body.Close()
+
+	assert.Equal(t, http.StatusConflict, resp.StatusCode)
+
+	var respJSON struct {
+		Error string             `json:"error"`
+		Diff  []models.FieldDiff `json:"diff"`
+	}
+	err = json.NewDecoder(resp.Body).Decode(&respJSON)
+	assert.NoError(t, err)
+	assert.Equal(t, "Заказ уже существует с другими данными", respJSON.Error)
+	assert.Equal(t, conflict.Diff, respJSON.Diff)
+
+	svc.AssertExpectations(t)
+}
+
 // getOrder Handler Tests
 func TestHandler_GetOrder_OK(t *testing.T) {
 	svc := &mocks.OrderService{}
 	logger := zap.NewNop()
-	controller := http_handlers.New(svc, logger)
+	controller := http_handlers.New(svc, newValidator(t, config.ValidationConfig{}), logger)
 
 	expectedOrder := createValidOrder()
+	expectedOrder.Version = 4
 
 	svc.On("GetOrder", mock.Anything, "test-123").Return(expectedOrder, nil)
 
@@ -198,6 +320,7 @@ func TestHandler_GetOrder_OK(t *testing.T) {
 	defer resp.Body.Close()
 
 	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, `"4"`, resp.Header.Get("ETag"))
 
 	var respJSON map[string]any
 	err = json.NewDecoder(resp.Body).Decode(&respJSON)
@@ -215,9 +338,9 @@ func TestHandler_GetOrder_OK(t *testing.T) {
 func TestHandler_GetOrder_Error_NotFound(t *testing.T) {
 	svc := &mocks.OrderService{}
 	logger := zap.NewNop()
-	controller := http_handlers.New(svc, logger)
+	controller := http_handlers.New(svc, newValidator(t, config.ValidationConfig{}), logger)
 
-	svc.On("GetOrder", mock.Anything, "test-123").Return((*models.Order)(nil), errors.New("заказ не найден"))
+	svc.On("GetOrder", mock.Anything, "test-123").Return((*models.Order)(nil), fmt.Errorf("repo.Read: %w", models.ErrOrderNotFound))
 
 	mux := http.NewServeMux()
 	controller.RegisterOrderHandlers(mux)
@@ -237,3 +360,207 @@ func TestHandler_GetOrder_Error_NotFound(t *testing.T) {
 
 	svc.AssertExpectations(t)
 }
+
+func TestHandler_GetOrder_Error_Unavailable(t *testing.T) {
+	svc := &mocks.OrderService{}
+	logger := zap.NewNop()
+	controller := http_handlers.New(svc, newValidator(t, config.ValidationConfig{}), logger)
+
+	svc.On("GetOrder", mock.Anything, "test-123").Return((*models.Order)(nil), fmt.Errorf("repo.Read: %w", models.ErrUnavailable))
+
+	mux := http.NewServeMux()
+	controller.RegisterOrderHandlers(mux)
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	resp, err := http.Get(server.URL + "/order/test-123")
+	assert.NoError(t, err)
+	defer resp.Body.Close()
+
+	assert.Equal(t, http.StatusServiceUnavailable, resp.StatusCode)
+
+	svc.AssertExpectations(t)
+}
+
+func TestHandler_GetOrder_Error_Internal(t *testing.T) {
+	svc := &mocks.OrderService{}
+	logger := zap.NewNop()
+	controller := http_handlers.New(svc, newValidator(t, config.ValidationConfig{}), logger)
+
+	// Текст ошибки больше не влияет на код ответа
+	svc.On("GetOrder", mock.Anything, "test-123").Return((*models.Order)(nil), fmt.Errorf("заказ не найден: сломался диск"))
+
+	mux := http.NewServeMux()
+	controller.RegisterOrderHandlers(mux)
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	resp, err := http.Get(server.URL + "/order/test-123")
+	assert.NoError(t, err)
+	defer resp.Body.Close()
+
+	assert.Equal(t, http.StatusInternalServerError, resp.StatusCode)
+
+	svc.AssertExpectations(t)
+}
+
+// listOrders Handler Tests
+func TestHandler_ListOrders_OK(t *testing.T) {
+	svc := &mocks.OrderService{}
+	logger := zap.NewNop()
+	controller := http_handlers.New(svc, newValidator(t, config.ValidationConfig{}), logger)
+
+	expectedFilter := models.OrderFilter{
+		CustomerID:  "customer-123",
+		Brand:       "Test Brand",
+		CreatedFrom: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+		Sort:        models.SortDesc,
+		Limit:       2,
+		Cursor:      "abc",
+	}
+	page := &models.OrderPage{
+		Orders:     []*models.Order{createValidOrder()},
+		NextCursor: "next",
+	}
+
+	svc.On("ListOrders", mock.Anything, expectedFilter).Return(page, nil)
+
+	mux := http.NewServeMux()
+	controller.RegisterOrderHandlers(mux)
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	resp, err := http.Get(server.URL + "/orders?customer_id=customer-123&brand=Test+Brand&date_from=2024-01-01&sort=desc&limit=2&cursor=abc")
+	assert.NoError(t, err)
+	defer resp.Body.Close()
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var respJSON struct {
+		Orders     []*models.Order `json:"orders"`
+		NextCursor string          `json:"next_cursor"`
+	}
+	err = json.NewDecoder(resp.Body).Decode(&respJSON)
+	assert.NoError(t, err)
+	assert.Len(t, respJSON.Orders, 1)
+	assert.Equal(t, "next", respJSON.NextCursor)
+
+	svc.AssertExpectations(t)
+}
+
+func TestHandler_ListOrders_Error_InvalidLimit(t *testing.T) {
+	svc := &mocks.OrderService{}
+	logger := zap.NewNop()
+	controller := http_handlers.New(svc, newValidator(t, config.ValidationConfig{}), logger)
+
+	mux := http.NewServeMux()
+	controller.RegisterOrderHandlers(mux)
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	resp, err := http.Get(server.URL + "/orders?limit=1000")
+	assert.NoError(t, err)
+	defer resp.Body.Close()
+
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+
+	svc.AssertNotCalled(t, "ListOrders")
+}
+
+// changeStatus Handler Tests
+func TestHandler_ChangeStatus_OK(t *testing.T) {
+	svc := &mocks.OrderService{}
+	logger := zap.NewNop()
+	controller := http_handlers.New(svc, newValidator(t, config.ValidationConfig{}), logger)
+
+	updated := createValidOrder()
+	updated.Status = models.StatusPaid
+	expectedChange := models.StatusChange{
+		OrderUID:  "test-123",
+		To:        models.StatusPaid,
+		ChangedBy: "operator",
+		Reason:    "оплата получена",
+	}
+
+	svc.On("ChangeStatus", mock.Anything, expectedChange).Return(updated, nil)
+
+	mux := http.NewServeMux()
+	controller.RegisterOrderHandlers(mux)
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	body := `{"status":"paid","changed_by":"operator","reason":"оплата получена"}`
+	req, err := http.NewRequest(http.MethodPatch, server.URL+"/order/test-123/status", bytes.NewBufferString(body))
+	assert.NoError(t, err)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := http.DefaultClient.Do(req)
+	assert.NoError(t, err)
+	defer resp.Body.Close()
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var respJSON getOrderRespJSON
+	err = json.NewDecoder(resp.Body).Decode(&respJSON)
+	assert.NoError(t, err)
+	assert.Equal(t, models.StatusPaid, respJSON.Order.Status)
+
+	svc.AssertExpectations(t)
+}
+
+func TestHandler_ChangeStatus_Error_InvalidTransition(t *testing.T) {
+	svc := &mocks.OrderService{}
+	logger := zap.NewNop()
+	controller := http_handlers.New(svc, newValidator(t, config.ValidationConfig{}), logger)
+
+	svc.On("ChangeStatus", mock.Anything, mock.AnythingOfType("models.StatusChange")).
+		Return((*models.Order)(nil), fmt.Errorf("repo.UpdateStatus: %w", models.ErrInvalidTransition))
+
+	mux := http.NewServeMux()
+	controller.RegisterOrderHandlers(mux)
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	body := `{"status":"delivered","changed_by":"operator"}`
+	req, err := http.NewRequest(http.MethodPatch, server.URL+"/order/test-123/status", bytes.NewBufferString(body))
+	assert.NoError(t, err)
+
+	resp, err := http.DefaultClient.Do(req)
+	assert.NoError(t, err)
+	defer resp.Body.Close()
+
+	assert.Equal(t, http.StatusConflict, resp.StatusCode)
+
+	svc.AssertExpectations(t)
+}
+
+func TestHandler_ChangeStatus_Error_UnknownStatus(t *testing.T) {
+	svc := &mocks.OrderService{}
+	logger := zap.NewNop()
+	controller := http_handlers.New(svc, newValidator(t, config.ValidationConfig{}), logger)
+
+	mux := http.NewServeMux()
+	controller.RegisterOrderHandlers(mux)
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	body := `{"status":"lost","changed_by":"operator"}`
+	req, err := http.NewRequest(http.MethodPatch, server.URL+"/order/test-123/status", bytes.NewBufferString(body))
+	assert.NoError(t, err)
+
+	resp, err := http.DefaultClient.Do(req)
+	assert.NoError(t, err)
+	defer resp.Body.Close()
+
+	assert.Equal(t, http.StatusUnprocessableEntity, resp.StatusCode)
+
+	var respJSON struct {
+		Violations []models.Violation `json:"violations"`
+	}
+	assert.NoError(t, json.NewDecoder(resp.Body).Decode(&respJSON))
+	assert.Equal(t, []models.Violation{
+		{Path: "/status", Code: "invalid_status", Value: "lost", Message: "неизвестный статус: lost"},
+	}, respJSON.Violations)
+
+	svc.AssertNotCalled(t, "ChangeStatus")
+}
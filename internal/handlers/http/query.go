@@ -0,0 +1,77 @@
+package http_handlers
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/sunr3d/order-stream-processor/models"
+)
+
+const (
+	defaultListLimit = 20
+	maxListLimit     = 100
+)
+
+// parseOrderFilter разбирает параметры запроса GET /orders.
+func parseOrderFilter(q url.Values) (models.OrderFilter, error) {
+	filter := models.OrderFilter{
+		CustomerID:      q.Get("customer_id"),
+		DeliveryService: q.Get("delivery_service"),
+		TrackNumber:     q.Get("track_number"),
+		Currency:        q.Get("currency"),
+		Provider:        q.Get("provider"),
+		Brand:           q.Get("brand"),
+		Cursor:          q.Get("cursor"),
+		Sort:            models.SortAsc,
+		Limit:           defaultListLimit,
+	}
+
+	limit, err := parseLimit(q)
+	if err != nil {
+		return filter, err
+	}
+	filter.Limit = limit
+
+	switch sort := models.SortOrder(q.Get("sort")); sort {
+	case "":
+	case models.SortAsc, models.SortDesc:
+		filter.Sort = sort
+	default:
+		return filter, fmt.Errorf("%w: sort должен быть asc или desc", models.ErrValidation)
+	}
+
+	if filter.CreatedFrom, err = parseTime(q.Get("date_from")); err != nil {
+		return filter, fmt.Errorf("%w: некорректный date_from", models.ErrValidation)
+	}
+	if filter.CreatedTo, err = parseTime(q.Get("date_to")); err != nil {
+		return filter, fmt.Errorf("%w: некорректный date_to", models.ErrValidation)
+	}
+
+	return filter, nil
+}
+
+// parseLimit разбирает параметр limit; если он не задан, возвращается defaultListLimit.
+func parseLimit(q url.Values) (int, error) {
+	v := q.Get("limit")
+	if v == "" {
+		return defaultListLimit, nil
+	}
+	limit, err := strconv.Atoi(v)
+	if err != nil || limit <= 0 || limit > maxListLimit {
+		return 0, fmt.Errorf("%w: limit должен быть числом от 1 до %d", models.ErrValidation, maxListLimit)
+	}
+	return limit, nil
+}
+
+// parseTime принимает RFC3339 или дату в формате YYYY-MM-DD.
+func parseTime(v string) (time.Time, error) {
+	if v == "" {
+		return time.Time{}, nil
+	}
+	if t, err := time.Parse(time.RFC3339, v); err == nil {
+		return t, nil
+	}
+	return time.Parse(time.DateOnly, v)
+}
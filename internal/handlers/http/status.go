@@ -0,0 +1,105 @@
+package http_handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"go.uber.org/zap"
+
+	"github.com/sunr3d/order-stream-processor/internal/handlers/validators"
+	"github.com/sunr3d/order-stream-processor/internal/httpx"
+	"github.com/sunr3d/order-stream-processor/models"
+)
+
+func (h *httpHandler) changeStatus(w http.ResponseWriter, r *http.Request) {
+	logger := h.logger.With(
+		zap.String("op", "handlers.changeStatus"),
+		zap.String("order_uid", r.PathValue("order_uid")),
+	)
+
+	logger.Info("получен запрос на смену статуса заказа")
+
+	var req changeStatusReq
+
+	decoder := json.NewDecoder(r.Body)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&req); err != nil {
+		logger.Error("некорректный JSON", zap.Error(err))
+		_ = httpx.HttpError(w, http.StatusBadRequest, "Некорректный JSON")
+		return
+	}
+
+	change := models.StatusChange{
+		OrderUID:  r.PathValue("order_uid"),
+		To:        req.Status,
+		ChangedBy: req.ChangedBy,
+		Reason:    req.Reason,
+	}
+
+	if err := validators.ValidateStatusChange(&change); err != nil {
+		logger.Error("ошибка валидации запроса", zap.Error(err))
+		writeValidationError(w, err)
+		return
+	}
+
+	order, err := h.svc.ChangeStatus(r.Context(), change)
+	if err != nil {
+		logger.Error("ошибка при смене статуса заказа", zap.Error(err))
+		code, msg := errorResponse(err)
+		_ = httpx.HttpError(w, code, msg)
+		return
+	}
+
+	resp := getOrderResp{
+		Order: order,
+	}
+
+	w.Header().Set("ETag", etag(order.CurrentVersion()))
+	if err := httpx.WriteJSON(w, http.StatusOK, resp); err != nil {
+		switch {
+		case errors.Is(err, httpx.ErrJSONMarshal):
+			logger.Error("ошибка при отправке ответа", zap.Error(err))
+			_ = httpx.HttpError(w, http.StatusInternalServerError, "Внутреняя ошибка сервера")
+		case errors.Is(err, httpx.ErrWriteBody):
+			logger.Warn("клиент закрыл соединение, ответ не отправлен", zap.Error(err))
+		}
+		return
+	}
+
+	logger.Info("статус заказа успешно изменен", zap.String("status", string(order.Status)))
+}
+
+func (h *httpHandler) getStatusHistory(w http.ResponseWriter, r *http.Request) {
+	logger := h.logger.With(
+		zap.String("op", "handlers.getStatusHistory"),
+		zap.String("order_uid", r.PathValue("order_uid")),
+	)
+
+	logger.Info("получен запрос на получение истории статусов заказа")
+
+	history, err := h.svc.GetStatusHistory(r.Context(), r.PathValue("order_uid"))
+	if err != nil {
+		logger.Error("ошибка при получении истории статусов", zap.Error(err))
+		code, msg := errorResponse(err)
+		_ = httpx.HttpError(w, code, msg)
+		return
+	}
+
+	resp := statusHistoryResp{
+		History: history,
+	}
+
+	if err := httpx.WriteJSON(w, http.StatusOK, resp); err != nil {
+		switch {
+		case errors.Is(err, httpx.ErrJSONMarshal):
+			logger.Error("ошибка при отправке ответа", zap.Error(err))
+			_ = httpx.HttpError(w, http.StatusInternalServerError, "Внутреняя ошибка сервера")
+		case errors.Is(err, httpx.ErrWriteBody):
+			logger.Warn("клиент закрыл соединение, ответ не отправлен", zap.Error(err))
+		}
+		return
+	}
+
+	logger.Info("история статусов успешно получена", zap.Int("count", len(history)))
+}
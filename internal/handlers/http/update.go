@@ -0,0 +1,87 @@
+package http_handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"go.uber.org/zap"
+
+	"github.com/sunr3d/order-stream-processor/internal/httpx"
+)
+
+// updateOrder заменяет заказ целиком. Требует If-Match с ETag текущей версии
+// (или "*" для безусловной замены), чтобы не затереть чужое изменение.
+func (h *httpHandler) updateOrder(w http.ResponseWriter, r *http.Request) {
+	logger := h.logger.With(
+		zap.String("op", "handlers.updateOrder"),
+		zap.String("order_uid", r.PathValue("order_uid")),
+	)
+
+	logger.Info("получен запрос на обновление заказа")
+
+	ifMatch := r.Header.Get("If-Match")
+	if ifMatch == "" {
+		logger.Info("запрос без If-Match")
+		_ = httpx.HttpError(w, http.StatusPreconditionRequired, "Требуется заголовок If-Match")
+		return
+	}
+	expectedVersion, err := parseIfMatch(ifMatch)
+	if err != nil {
+		logger.Error("некорректный If-Match", zap.Error(err))
+		code, msg := errorResponse(err)
+		_ = httpx.HttpError(w, code, msg)
+		return
+	}
+
+	var req updateOrderReq
+
+	decoder := json.NewDecoder(r.Body)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&req); err != nil {
+		logger.Error("некорректный JSON", zap.Error(err))
+		_ = httpx.HttpError(w, http.StatusBadRequest, "Некорректный JSON")
+		return
+	}
+
+	if req.OrderUID == "" {
+		req.OrderUID = r.PathValue("order_uid")
+	}
+	if req.OrderUID != r.PathValue("order_uid") {
+		logger.Error("order_uid в теле не совпадает с путем", zap.String("body_order_uid", req.OrderUID))
+		_ = httpx.HttpError(w, http.StatusBadRequest, "order_uid в теле не совпадает с путем")
+		return
+	}
+
+	// Статус меняется только через /status и при обновлении берется из сохраненного заказа,
+	// поэтому статус из тела (например, из ответа GET) не проверяется
+	req.Status = ""
+
+	h.validator.Normalize(&req)
+	if err := h.validator.ValidateOrder(&req); err != nil {
+		logger.Error("ошибка валидации запроса", zap.Error(err))
+		writeValidationError(w, err)
+		return
+	}
+
+	if err := h.svc.UpdateOrder(r.Context(), &req, expectedVersion); err != nil {
+		logger.Error("ошибка при обновлении заказа", zap.Error(err))
+		code, msg := errorResponse(err)
+		_ = httpx.HttpError(w, code, msg)
+		return
+	}
+
+	w.Header().Set("ETag", etag(req.Version))
+	if err := httpx.WriteJSON(w, http.StatusOK, getOrderResp{Order: &req}); err != nil {
+		switch {
+		case errors.Is(err, httpx.ErrJSONMarshal):
+			logger.Error("ошибка при отправке ответа", zap.Error(err))
+			_ = httpx.HttpError(w, http.StatusInternalServerError, "Внутреняя ошибка сервера")
+		case errors.Is(err, httpx.ErrWriteBody):
+			logger.Warn("клиент закрыл соединение, ответ не отправлен", zap.Error(err))
+		}
+		return
+	}
+
+	logger.Info("заказ успешно обновлен", zap.Int64("version", req.Version))
+}
@@ -0,0 +1,132 @@
+package http_handlers_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/sunr3d/order-stream-processor/internal/config"
+	http_handlers "github.com/sunr3d/order-stream-processor/internal/handlers/http"
+	"github.com/sunr3d/order-stream-processor/mocks"
+	"github.com/sunr3d/order-stream-processor/models"
+)
+
+func putOrder(t *testing.T, svc *mocks.OrderService, path, ifMatch string, order *models.Order) *http.Response {
+	t.Helper()
+
+	mux := http.NewServeMux()
+	http_handlers.New(svc, newValidator(t, config.ValidationConfig{}), zap.NewNop()).RegisterOrderHandlers(mux)
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	jsonData, err := json.Marshal(order)
+	require.NoError(t, err)
+
+	req, err := http.NewRequest(http.MethodPut, server.URL+path, bytes.NewBuffer(jsonData))
+	require.NoError(t, err)
+	req.Header.Set("Content-Type", "application/json")
+	if ifMatch != "" {
+		req.Header.Set("If-Match", ifMatch)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	t.Cleanup(func() { resp.Body.Close() })
+	return resp
+}
+
+// updateOrder Handler Tests
+func TestHandler_UpdateOrder_OK(t *testing.T) {
+	svc := &mocks.OrderService{}
+	svc.On("UpdateOrder", mock.Anything, mock.AnythingOfType("*models.Order"), int64(2)).Run(func(args mock.Arguments) {
+		args.Get(1).(*models.Order).Version = 3
+	}).Return(nil)
+
+	resp := putOrder(t, svc, "/order/test-123", `"2"`, createValidOrder())
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, `"3"`, resp.Header.Get("ETag"))
+	svc.AssertExpectations(t)
+}
+
+func TestHandler_UpdateOrder_RoundTripNotCreated(t *testing.T) {
+	svc := &mocks.OrderService{}
+	stored := createValidOrder()
+	stored.Status = models.StatusShipped
+	stored.Version = 4
+	svc.On("GetOrder", mock.Anything, "test-123").Return(stored, nil)
+	svc.On("UpdateOrder", mock.Anything, mock.AnythingOfType("*models.Order"), int64(4)).Run(func(args mock.Arguments) {
+		order := args.Get(1).(*models.Order)
+		order.Status = models.StatusShipped
+		order.Version = 5
+	}).Return(nil)
+
+	getResp := doRequest(t, svc, http.MethodGet, "/order/test-123", "")
+	require.Equal(t, http.StatusOK, getResp.StatusCode)
+	var body struct {
+		Order *models.Order `json:"order"`
+	}
+	require.NoError(t, json.NewDecoder(getResp.Body).Decode(&body))
+	require.Equal(t, models.StatusShipped, body.Order.Status)
+
+	body.Order.Delivery.Address = "New Address"
+	resp := putOrder(t, svc, "/order/test-123", getResp.Header.Get("ETag"), body.Order)
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, `"5"`, resp.Header.Get("ETag"))
+	svc.AssertExpectations(t)
+}
+
+func TestHandler_UpdateOrder_Wildcard(t *testing.T) {
+	svc := &mocks.OrderService{}
+	svc.On("UpdateOrder", mock.Anything, mock.AnythingOfType("*models.Order"), int64(0)).Return(nil)
+
+	resp := putOrder(t, svc, "/order/test-123", "*", createValidOrder())
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	svc.AssertExpectations(t)
+}
+
+func TestHandler_UpdateOrder_Error_NoIfMatch(t *testing.T) {
+	svc := &mocks.OrderService{}
+
+	resp := putOrder(t, svc, "/order/test-123", "", createValidOrder())
+
+	assert.Equal(t, http.StatusPreconditionRequired, resp.StatusCode)
+	svc.AssertNotCalled(t, "UpdateOrder")
+}
+
+func TestHandler_UpdateOrder_Error_InvalidIfMatch(t *testing.T) {
+	svc := &mocks.OrderService{}
+
+	resp := putOrder(t, svc, "/order/test-123", "2", createValidOrder())
+
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	svc.AssertNotCalled(t, "UpdateOrder")
+}
+
+func TestHandler_UpdateOrder_Error_VersionConflict(t *testing.T) {
+	svc := &mocks.OrderService{}
+	svc.On("UpdateOrder", mock.Anything, mock.AnythingOfType("*models.Order"), int64(1)).Return(models.ErrVersionConflict)
+
+	resp := putOrder(t, svc, "/order/test-123", `W/"1"`, createValidOrder())
+
+	assert.Equal(t, http.StatusPreconditionFailed, resp.StatusCode)
+	svc.AssertExpectations(t)
+}
+
+func TestHandler_UpdateOrder_Error_UIDMismatch(t *testing.T) {
+	svc := &mocks.OrderService{}
+
+	resp := putOrder(t, svc, "/order/other", `"1"`, createValidOrder())
+
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	svc.AssertNotCalled(t, "UpdateOrder")
+}
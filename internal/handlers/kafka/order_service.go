@@ -3,53 +3,175 @@ package kafka_handlers
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 
 	"go.uber.org/zap"
 
 	"github.com/sunr3d/order-stream-processor/internal/handlers/validators"
+	"github.com/sunr3d/order-stream-processor/internal/interfaces/infra"
 	"github.com/sunr3d/order-stream-processor/internal/interfaces/services"
 	"github.com/sunr3d/order-stream-processor/models"
 )
 
+// Режимы обработки заказов из топика заказов (KAFKA_ORDERS_MODE)
+const (
+	OrdersModeCreate = "create"
+	OrdersModeUpsert = "upsert"
+)
+
 type kafkaHandler struct {
-	svc    services.OrderService
-	logger *zap.Logger
+	svc       services.OrderService
+	validator *validators.Validator
+	logger    *zap.Logger
 }
 
-func New(svc services.OrderService, logger *zap.Logger) *kafkaHandler {
-	return &kafkaHandler{svc: svc, logger: logger}
+func New(svc services.OrderService, validator *validators.Validator, logger *zap.Logger) *kafkaHandler {
+	return &kafkaHandler{svc: svc, validator: validator, logger: logger}
 }
 
 func (h *kafkaHandler) CreateOrder(ctx context.Context, msg []byte) error {
 	logger := h.logger.With(zap.String("op", "kafka_handlers.createOrder"))
 
-	var order models.Order
-	if err := json.Unmarshal(msg, &order); err != nil {
-		logger.Error("ошибка при разборе заказа из Kafka",
+	order, err := h.parseOrder(logger, msg, false)
+	if err != nil {
+		return err
+	}
+
+	logger = logger.With(zap.String("order_uid", order.OrderUID))
+
+	if err := h.svc.ProcessOrder(ctx, order); err != nil {
+		if errors.Is(err, models.ErrOrderErased) {
+			logger.Warn("заказ был удален или обезличен, сообщение пропущено", zap.Error(err))
+			return fmt.Errorf("%w: order_service.ProcessOrder(): %w", infra.ErrPermanent, err)
+		}
+		logger.Error("ошибка при обработке заказа из Kafka",
 			zap.Error(err),
 			zap.String("order_uid", order.OrderUID),
 		)
-		return fmt.Errorf("ошибка при разборе заказа из Kafka: %w", err)
+		if isPermanent(err) {
+			return fmt.Errorf("%w: order_service.ProcessOrder(): %w", infra.ErrPermanent, err)
+		}
+		return fmt.Errorf("order_service.ProcessOrder(): %w", err)
 	}
 
-	if err := validators.ValidateOrder(&order); err != nil {
-		logger.Error("ошибка валидации заказа из Kafka",
-			zap.Error(err),
-		)
-		return fmt.Errorf("ошибка валидации заказа из Kafka: %w", err)
+	logger.Info("заказ из Kafka успешно обработан")
+	return nil
+}
+
+// UpsertOrder сохраняет заказ или заменяет сохраненный, если присланный новее
+// (выше версия или, без версии, позже date_created). Устаревший заказ пропускается без ошибки.
+func (h *kafkaHandler) UpsertOrder(ctx context.Context, msg []byte) error {
+	logger := h.logger.With(zap.String("op", "kafka_handlers.upsertOrder"))
+
+	order, err := h.parseOrder(logger, msg, true)
+	if err != nil {
+		return err
 	}
 
 	logger = logger.With(zap.String("order_uid", order.OrderUID))
 
-	if err := h.svc.ProcessOrder(ctx, &order); err != nil {
+	result, err := h.svc.UpsertOrder(ctx, order)
+	if err != nil {
+		if errors.Is(err, models.ErrOrderErased) {
+			logger.Warn("заказ был удален или обезличен, сообщение пропущено", zap.Error(err))
+			return fmt.Errorf("%w: order_service.UpsertOrder(): %w", infra.ErrPermanent, err)
+		}
+		logger.Error("ошибка при upsert заказа из Kafka", zap.Error(err))
+		if isPermanent(err) {
+			return fmt.Errorf("%w: order_service.UpsertOrder(): %w", infra.ErrPermanent, err)
+		}
+		return fmt.Errorf("order_service.UpsertOrder(): %w", err)
+	}
+
+	logger.Info("заказ из Kafka успешно обработан", zap.String("result", string(result)))
+	return nil
+}
+
+// CreateOrders обрабатывает пачку сообщений с заказами. Ошибки разбора и валидации
+// относятся только к своему сообщению, остальные заказы сохраняются одной пачкой.
+func (h *kafkaHandler) CreateOrders(ctx context.Context, msgs [][]byte) []error {
+	logger := h.logger.With(zap.String("op", "kafka_handlers.createOrders"))
+
+	results := make([]error, len(msgs))
+	orders := make([]*models.Order, 0, len(msgs))
+	positions := make([]int, 0, len(msgs))
+	for i, msg := range msgs {
+		order, err := h.parseOrder(logger, msg, false)
+		if err != nil {
+			results[i] = err
+			continue
+		}
+		orders = append(orders, order)
+		positions = append(positions, i)
+	}
+
+	if len(orders) == 0 {
+		return results
+	}
+
+	for k, err := range h.svc.ProcessOrders(ctx, orders) {
+		if err == nil {
+			continue
+		}
+
+		if errors.Is(err, models.ErrOrderErased) {
+			logger.Warn("заказ был удален или обезличен, сообщение пропущено",
+				zap.Error(err),
+				zap.String("order_uid", orders[k].OrderUID),
+			)
+			results[positions[k]] = fmt.Errorf("%w: order_service.ProcessOrders(): %w", infra.ErrPermanent, err)
+			continue
+		}
+
 		logger.Error("ошибка при обработке заказа из Kafka",
+			zap.Error(err),
+			zap.String("order_uid", orders[k].OrderUID),
+		)
+		if isPermanent(err) {
+			results[positions[k]] = fmt.Errorf("%w: order_service.ProcessOrders(): %w", infra.ErrPermanent, err)
+		} else {
+			results[positions[k]] = fmt.Errorf("order_service.ProcessOrders(): %w", err)
+		}
+	}
+
+	logger.Info("пачка заказов из Kafka обработана", zap.Int("count", len(msgs)))
+	return results
+}
+
+// parseOrder разбирает, нормализует и валидирует заказ из сообщения, ошибки неустранимы.
+// При upsert статус из сообщения отбрасывается: он меняется только через топик статусов,
+// а повторно отправленный или исправленный заказ может содержать уже сменившийся статус.
+func (h *kafkaHandler) parseOrder(logger *zap.Logger, msg []byte, upsert bool) (*models.Order, error) {
+	var order models.Order
+	if err := json.Unmarshal(msg, &order); err != nil {
+		logger.Error("ошибка при разборе заказа из Kafka",
 			zap.Error(err),
 			zap.String("order_uid", order.OrderUID),
 		)
-		return fmt.Errorf("order_service.ProcessOrder(): %w", err)
+		return nil, fmt.Errorf("%w: ошибка при разборе заказа из Kafka: %w", infra.ErrPermanent, err)
 	}
 
-	logger.Info("заказ из Kafka успешно обработан")
-	return nil
+	if upsert {
+		order.Status = ""
+	}
+
+	h.validator.Normalize(&order)
+	if err := h.validator.ValidateOrder(&order); err != nil {
+		fields := []zap.Field{zap.Error(err), zap.String("order_uid", order.OrderUID)}
+		var verr *models.ValidationError
+		if errors.As(err, &verr) {
+			fields = append(fields, zap.Any("violations", verr.Violations))
+		}
+		logger.Error("ошибка валидации заказа из Kafka", fields...)
+		return nil, fmt.Errorf("%w: ошибка валидации заказа из Kafka: %w", infra.ErrPermanent, err)
+	}
+
+	return &order, nil
+}
+
+// isPermanent определяет ошибки сервиса, которые не исправятся повторной обработкой сообщения.
+func isPermanent(err error) bool {
+	return errors.Is(err, models.ErrValidation) || errors.Is(err, models.ErrOrderAlreadyExists) ||
+		errors.Is(err, models.ErrOrderErased)
 }
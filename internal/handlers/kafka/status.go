@@ -0,0 +1,67 @@
+package kafka_handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"go.uber.org/zap"
+
+	"github.com/sunr3d/order-stream-processor/internal/handlers/validators"
+	"github.com/sunr3d/order-stream-processor/internal/interfaces/infra"
+	"github.com/sunr3d/order-stream-processor/models"
+)
+
+// defaultChangedBy используется, если продюсер команды не указал инициатора.
+const defaultChangedBy = "kafka"
+
+// changeStatusCmd — команда смены статуса заказа из топика KAFKA_STATUS_TOPIC.
+type changeStatusCmd struct {
+	OrderUID  string             `json:"order_uid"`
+	Status    models.OrderStatus `json:"status"`
+	ChangedBy string             `json:"changed_by"`
+	Reason    string             `json:"reason"`
+}
+
+func (h *kafkaHandler) ChangeStatus(ctx context.Context, msg []byte) error {
+	logger := h.logger.With(zap.String("op", "kafka_handlers.changeStatus"))
+
+	var cmd changeStatusCmd
+	if err := json.Unmarshal(msg, &cmd); err != nil {
+		logger.Error("ошибка при разборе команды смены статуса из Kafka", zap.Error(err))
+		return fmt.Errorf("%w: ошибка при разборе команды смены статуса из Kafka: %w", infra.ErrPermanent, err)
+	}
+
+	change := models.StatusChange{
+		OrderUID:  cmd.OrderUID,
+		To:        cmd.Status,
+		ChangedBy: cmd.ChangedBy,
+		Reason:    cmd.Reason,
+	}
+	if change.ChangedBy == "" {
+		change.ChangedBy = defaultChangedBy
+	}
+
+	if err := validators.ValidateStatusChange(&change); err != nil {
+		logger.Error("ошибка валидации команды смены статуса из Kafka", zap.Error(err))
+		return fmt.Errorf("%w: ошибка валидации команды смены статуса из Kafka: %w", infra.ErrPermanent, err)
+	}
+
+	logger = logger.With(
+		zap.String("order_uid", change.OrderUID),
+		zap.String("to", string(change.To)),
+	)
+
+	// ErrOrderNotFound не считается неустранимой: команда могла опередить сам заказ
+	if _, err := h.svc.ChangeStatus(ctx, change); err != nil {
+		logger.Error("ошибка при смене статуса заказа из Kafka", zap.Error(err))
+		if errors.Is(err, models.ErrInvalidTransition) || errors.Is(err, models.ErrValidation) {
+			return fmt.Errorf("%w: order_service.ChangeStatus(): %w", infra.ErrPermanent, err)
+		}
+		return fmt.Errorf("order_service.ChangeStatus(): %w", err)
+	}
+
+	logger.Info("статус заказа из Kafka успешно изменен")
+	return nil
+}
@@ -0,0 +1,93 @@
+package validators
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/sunr3d/order-stream-processor/models"
+)
+
+// Правила согласованности заказа, имена используются в логах и метриках.
+const (
+	RuleAmountTotal     = "amount_total"
+	RuleGoodsTotal      = "goods_total"
+	RuleItemTotalPrice  = "item_total_price"
+	RuleItemTrackNumber = "item_track_number"
+	RulePaymentLink     = "payment_link"
+	RuleDateCreated     = "date_created"
+)
+
+// Коды нарушений правил согласованности
+const (
+	CodeAmountMismatch      = "amount_mismatch"
+	CodeGoodsTotalMismatch  = "goods_total_mismatch"
+	CodeTotalPriceMismatch  = "total_price_mismatch"
+	CodeSaleOutOfRange      = "sale_out_of_range"
+	CodeTrackNumberMismatch = "track_number_mismatch"
+	CodePaymentNotLinked    = "payment_not_linked"
+	CodeDateInFuture        = "date_in_future"
+)
+
+func checkAmountTotal(v *violations, order *models.Order) {
+	p := order.Payment
+	if expected := p.GoodsTotal + p.DeliveryCost + p.CustomFee; p.Amount != expected {
+		v.add("/payment/amount", CodeAmountMismatch, p.Amount,
+			"не равно goods_total + delivery_cost + custom_fee (%d)", expected)
+	}
+}
+
+func checkGoodsTotal(v *violations, order *models.Order) {
+	sum := 0
+	for _, item := range order.Items {
+		sum += item.TotalPrice
+	}
+	if sum != order.Payment.GoodsTotal {
+		v.add("/payment/goods_total", CodeGoodsTotalMismatch, order.Payment.GoodsTotal,
+			"не равно сумме total_price товаров (%d)", sum)
+	}
+}
+
+// checkItemTotalPrice сверяет total_price с ценой за вычетом скидки sale в процентах.
+// Допускается расхождение на единицу: отправители по-разному округляют копейки.
+func checkItemTotalPrice(v *violations, order *models.Order) {
+	for i, item := range order.Items {
+		if item.Sale > 100 {
+			v.add(fmt.Sprintf("/items/%d/sale", i), CodeSaleOutOfRange, item.Sale, "не может быть больше 100")
+			continue
+		}
+		expected := item.Price * (100 - item.Sale) / 100
+		if diff := item.TotalPrice - expected; diff < 0 || diff > 1 {
+			v.add(fmt.Sprintf("/items/%d/total_price", i), CodeTotalPriceMismatch, item.TotalPrice,
+				"не равно price за вычетом sale%% (%d)", expected)
+		}
+	}
+}
+
+// checkItemTrackNumber проверяет, что товары отправлены с трек-номером заказа; пустой трек-номер товара допустим.
+func checkItemTrackNumber(v *violations, order *models.Order) {
+	for i, item := range order.Items {
+		if item.TrackNumber != "" && item.TrackNumber != order.TrackNumber {
+			v.add(fmt.Sprintf("/items/%d/track_number", i), CodeTrackNumberMismatch, item.TrackNumber,
+				"не совпадает с track_number заказа (%s)", order.TrackNumber)
+		}
+	}
+}
+
+// checkPaymentLink проверяет, что платеж относится к заказу: transaction равен order_uid,
+// либо, если transaction — идентификатор платежа у провайдера, на заказ ссылается request_id.
+func checkPaymentLink(v *violations, order *models.Order) {
+	p := order.Payment
+	if p.Transaction != order.OrderUID && p.RequestID != order.OrderUID {
+		v.add("/payment/transaction", CodePaymentNotLinked, p.Transaction,
+			"ни transaction, ни request_id (%s) не совпадают с order_uid (%s)", p.RequestID, order.OrderUID)
+	}
+}
+
+// dateNotInFuture проверяет, что date_created не позже текущего времени с учетом расхождения часов skew.
+func dateNotInFuture(skew time.Duration) func(v *violations, order *models.Order) {
+	return func(v *violations, order *models.Order) {
+		if limit := time.Now().Add(skew); order.DateCreated.After(limit) {
+			v.add("/date_created", CodeDateInFuture, order.DateCreated, "не может быть в будущем")
+		}
+	}
+}
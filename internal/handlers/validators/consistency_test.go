@@ -0,0 +1,174 @@
+package validators_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/sunr3d/order-stream-processor/internal/config"
+	"github.com/sunr3d/order-stream-processor/internal/handlers/validators"
+	"github.com/sunr3d/order-stream-processor/models"
+)
+
+// consistentOrder возвращает заказ, согласованный по всем правилам (как data/model.json).
+func consistentOrder() *models.Order {
+	return &models.Order{
+		OrderUID:        "b563feb7b2b84b6test",
+		CustomerID:      "test",
+		TrackNumber:     "WBILMTESTTRACK",
+		DeliveryService: "meest",
+		DateCreated:     time.Date(2021, 11, 26, 6, 22, 19, 0, time.UTC),
+		Delivery: models.Delivery{
+			Name:    "Test Testov",
+			Phone:   "+9720000000",
+			Email:   "test@gmail.com",
+			City:    "Kiryat Mozkin",
+			Address: "Ploshad Mira 15",
+		},
+		Payment: models.Payment{
+			Transaction:  "b563feb7b2b84b6test",
+			Provider:     "wbpay",
+			Amount:       1817,
+			PaymentDT:    1637907727,
+			DeliveryCost: 1500,
+			GoodsTotal:   317,
+		},
+		Items: []models.Item{{
+			ChrtID:      9934930,
+			TrackNumber: "WBILMTESTTRACK",
+			Price:       453,
+			Name:        "Mascaras",
+			Sale:        30,
+			Size:        "0",
+			TotalPrice:  317,
+			Brand:       "Vivienne Sabo",
+		}},
+	}
+}
+
+func allRules(mode validators.Mode) config.ValidationConfig {
+	m := string(mode)
+	return config.ValidationConfig{
+		AmountTotal:     m,
+		GoodsTotal:      m,
+		ItemTotalPrice:  m,
+		ItemTrackNumber: m,
+		PaymentLink:     m,
+		DateCreated:     m,
+		ClockSkew:       time.Minute,
+	}
+}
+
+func TestValidator_ConsistentOrder(t *testing.T) {
+	v, err := validators.New(allRules(validators.ModeReject), zap.NewNop())
+	require.NoError(t, err)
+
+	assert.NoError(t, v.ValidateOrder(consistentOrder()))
+}
+
+func TestValidator_Reject(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(o *models.Order)
+		path   string
+		code   string
+	}{
+		{"amount", func(o *models.Order) { o.Payment.Amount = 1818 }, "/payment/amount", validators.CodeAmountMismatch},
+		{"goods_total", func(o *models.Order) { o.Payment.GoodsTotal, o.Payment.Amount = 300, 1800 }, "/payment/goods_total", validators.CodeGoodsTotalMismatch},
+		{"item_total_price", func(o *models.Order) { o.Items[0].Sale = 50 }, "/items/0/total_price", validators.CodeTotalPriceMismatch},
+		{"item_sale", func(o *models.Order) { o.Items[0].Sale = 120 }, "/items/0/sale", validators.CodeSaleOutOfRange},
+		{"item_track_number", func(o *models.Order) { o.Items[0].TrackNumber = "OTHER" }, "/items/0/track_number", validators.CodeTrackNumberMismatch},
+		{"payment_link", func(o *models.Order) { o.Payment.Transaction = "provider-tx" }, "/payment/transaction", validators.CodePaymentNotLinked},
+		{"date_created", func(o *models.Order) { o.DateCreated = time.Now().Add(time.Hour) }, "/date_created", validators.CodeDateInFuture},
+	}
+
+	v, err := validators.New(allRules(validators.ModeReject), zap.NewNop())
+	require.NoError(t, err)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			order := consistentOrder()
+			tt.modify(order)
+
+			err := v.ValidateOrder(order)
+			assert.ErrorIs(t, err, models.ErrValidation)
+
+			var verr *models.ValidationError
+			require.ErrorAs(t, err, &verr)
+			require.Len(t, verr.Violations, 1)
+			assert.Equal(t, tt.path, verr.Violations[0].Path)
+			assert.Equal(t, tt.code, verr.Violations[0].Code)
+		})
+	}
+}
+
+func TestValidator_CollectsAllViolations(t *testing.T) {
+	v, err := validators.New(config.ValidationConfig{AmountTotal: string(validators.ModeReject)}, zap.NewNop())
+	require.NoError(t, err)
+
+	order := consistentOrder()
+	order.OrderUID = ""
+	order.Items = append(order.Items, order.Items[0], order.Items[0])
+	order.Items[2].Price = 0
+	order.Payment.Amount = 1
+
+	var verr *models.ValidationError
+	require.ErrorAs(t, v.ValidateOrder(order), &verr)
+
+	assert.Equal(t, []models.Violation{
+		{Path: "/order_uid", Code: validators.CodeRequired, Value: "", Message: "не может быть пустым"},
+		{Path: "/items/2/price", Code: validators.CodePositive, Value: int64(0), Message: "должно быть больше 0"},
+		{Path: "/payment/amount", Code: validators.CodeAmountMismatch, Value: 1, Message: "не равно goods_total + delivery_cost + custom_fee (1817)"},
+	}, verr.Violations)
+}
+
+func TestValidator_AllowedDeviations(t *testing.T) {
+	v, err := validators.New(allRules(validators.ModeReject), zap.NewNop())
+	require.NoError(t, err)
+
+	order := consistentOrder()
+	order.Items[0].TotalPrice = 318
+	order.Payment.GoodsTotal, order.Payment.Amount = 318, 1818
+	assert.NoError(t, v.ValidateOrder(order), "округление total_price вверх")
+
+	order = consistentOrder()
+	order.Items[0].TrackNumber = ""
+	assert.NoError(t, v.ValidateOrder(order), "пустой трек-номер товара")
+
+	order = consistentOrder()
+	order.Payment.Transaction, order.Payment.RequestID = "provider-tx", order.OrderUID
+	assert.NoError(t, v.ValidateOrder(order), "платеж ссылается на заказ через request_id")
+
+	order = consistentOrder()
+	order.DateCreated = time.Now().Add(30 * time.Second)
+	assert.NoError(t, v.ValidateOrder(order), "расхождение часов в пределах ClockSkew")
+}
+
+func TestValidator_WarnAndOff(t *testing.T) {
+	order := consistentOrder()
+	order.Payment.Amount = 1
+
+	for _, mode := range []validators.Mode{validators.ModeWarn, validators.ModeOff, ""} {
+		v, err := validators.New(allRules(mode), zap.NewNop())
+		require.NoError(t, err)
+
+		assert.NoError(t, v.ValidateOrder(order), mode)
+	}
+}
+
+func TestValidator_RequiredFieldsAlwaysChecked(t *testing.T) {
+	v, err := validators.New(allRules(validators.ModeOff), zap.NewNop())
+	require.NoError(t, err)
+
+	order := consistentOrder()
+	order.OrderUID = ""
+	assert.ErrorIs(t, v.ValidateOrder(order), models.ErrValidation)
+}
+
+func TestNew_UnknownMode(t *testing.T) {
+	_, err := validators.New(config.ValidationConfig{GoodsTotal: "strict"}, zap.NewNop())
+	assert.ErrorContains(t, err, validators.RuleGoodsTotal)
+}
@@ -0,0 +1,23 @@
+package validators
+
+import (
+	"github.com/sunr3d/order-stream-processor/models"
+)
+
+// ValidateDeletion проверяет запрос на удаление заказа и возвращает *models.ValidationError
+// со всеми найденными нарушениями.
+func ValidateDeletion(req *models.DeletionRequest) error {
+	var v violations
+	v.required("/order_uid", req.OrderUID)
+	v.required("/requested_by", req.RequestedBy)
+	return v.err()
+}
+
+// ValidateErasure проверяет запрос на обезличивание данных покупателя и возвращает
+// *models.ValidationError со всеми найденными нарушениями.
+func ValidateErasure(req *models.ErasureRequest) error {
+	var v violations
+	v.required("/customer_id", req.CustomerID)
+	v.required("/requested_by", req.RequestedBy)
+	return v.err()
+}
@@ -0,0 +1,200 @@
+package validators
+
+import (
+	"net/mail"
+	"regexp"
+	"strings"
+
+	"golang.org/x/text/currency"
+	"golang.org/x/text/language"
+
+	"github.com/sunr3d/order-stream-processor/models"
+)
+
+// Правила форматов полей
+const (
+	RuleEmailFormat    = "email_format"
+	RulePhoneFormat    = "phone_format"
+	RuleZipFormat      = "zip_format"
+	RuleCurrencyFormat = "currency_format"
+	RuleLocaleFormat   = "locale_format"
+)
+
+// Коды нарушений форматов полей
+const (
+	CodeInvalidEmail    = "invalid_email"
+	CodeInvalidPhone    = "invalid_phone"
+	CodeInvalidZip      = "invalid_zip"
+	CodeInvalidCurrency = "invalid_currency"
+	CodeInvalidLocale   = "invalid_locale"
+)
+
+var (
+	// e164 — номер в международном формате: + и до 15 цифр с кодом страны
+	e164 = regexp.MustCompile(`^\+[1-9]\d{6,14}$`)
+	// phoneSeparators — символы, которыми отправители разделяют цифры номера
+	phoneSeparators = strings.NewReplacer(" ", "", "-", "", "(", "", ")", "", ".", "")
+	// anyZip проверяет индекс, если страна доставки неизвестна
+	anyZip      = regexp.MustCompile(`(?i)^[A-Z0-9][A-Z0-9 -]{1,8}[A-Z0-9]$`)
+	isoCurrency = regexp.MustCompile(`^[A-Z]{3}$`)
+)
+
+// country — правила страны для телефонов и почтовых индексов.
+type country struct {
+	callingCode string
+	// trunkPrefix — префикс внутреннего набора, заменяемый кодом страны при нормализации;
+	// пустой, если номер внутри страны набирается без префикса
+	trunkPrefix string
+	zip         *regexp.Regexp
+}
+
+// countries — страны по ISO 3166-1 alpha-2. Страна доставки определяется по коду страны телефона,
+// поэтому страны с общим кодом (США и Канада) различаются только через VALIDATION_COUNTRY.
+var countries = map[string]country{
+	"RU": {"7", "8", regexp.MustCompile(`^\d{6}$`)},
+	"IL": {"972", "0", regexp.MustCompile(`^\d{7}$`)},
+	"US": {"1", "1", regexp.MustCompile(`^\d{5}(-\d{4})?$`)},
+	"CA": {"1", "1", regexp.MustCompile(`(?i)^[A-Z]\d[A-Z] ?\d[A-Z]\d$`)},
+	"GB": {"44", "0", regexp.MustCompile(`(?i)^[A-Z]{1,2}\d[A-Z\d]? ?\d[A-Z]{2}$`)},
+	"DE": {"49", "0", regexp.MustCompile(`^\d{5}$`)},
+	"FR": {"33", "0", regexp.MustCompile(`^\d{5}$`)},
+	"IT": {"39", "", regexp.MustCompile(`^\d{5}$`)},
+	"ES": {"34", "", regexp.MustCompile(`^\d{5}$`)},
+	"NL": {"31", "0", regexp.MustCompile(`(?i)^\d{4} ?[A-Z]{2}$`)},
+	"PL": {"48", "", regexp.MustCompile(`^\d{2}-\d{3}$`)},
+	"TR": {"90", "0", regexp.MustCompile(`^\d{5}$`)},
+	"CN": {"86", "0", regexp.MustCompile(`^\d{6}$`)},
+	"IN": {"91", "0", regexp.MustCompile(`^\d{6}$`)},
+	"JP": {"81", "0", regexp.MustCompile(`^\d{3}-?\d{4}$`)},
+}
+
+// countryByPhone определяет страну по коду страны номера в формате E.164.
+// Возвращает false, если код неизвестен или принадлежит нескольким странам.
+func countryByPhone(phone string) (country, bool) {
+	if !e164.MatchString(phone) {
+		return country{}, false
+	}
+	var (
+		found country
+		count int
+	)
+	// Коды стран не являются префиксами друг друга, поэтому совпасть могут только страны с общим кодом
+	for _, c := range countries {
+		if strings.HasPrefix(phone[1:], c.callingCode) {
+			found = c
+			count++
+		}
+	}
+	return found, count == 1
+}
+
+func validEmail(s string) bool {
+	addr, err := mail.ParseAddress(s)
+	if err != nil || addr.Name != "" || addr.Address != s {
+		return false
+	}
+	domain := s[strings.LastIndexByte(s, '@')+1:]
+	return strings.Contains(domain, ".") && !strings.HasPrefix(domain, ".") && !strings.HasSuffix(domain, ".")
+}
+
+func validCurrency(s string) bool {
+	if !isoCurrency.MatchString(s) {
+		return false
+	}
+	_, err := currency.ParseISO(s)
+	return err == nil
+}
+
+func validLocale(s string) bool {
+	_, err := language.Parse(s)
+	return err == nil
+}
+
+func checkEmailFormat(v *violations, order *models.Order) {
+	if email := order.Delivery.Email; email != "" && !validEmail(email) {
+		v.add("/delivery/email", CodeInvalidEmail, email, "некорректный email")
+	}
+}
+
+func checkPhoneFormat(v *violations, order *models.Order) {
+	if phone := order.Delivery.Phone; phone != "" && !e164.MatchString(phone) {
+		v.add("/delivery/phone", CodeInvalidPhone, phone, "номер должен быть в формате E.164, например +79991234567")
+	}
+}
+
+func checkCurrencyFormat(v *violations, order *models.Order) {
+	if cur := order.Payment.Currency; cur != "" && !validCurrency(cur) {
+		v.add("/payment/currency", CodeInvalidCurrency, cur, "неизвестный код валюты ISO 4217")
+	}
+}
+
+func checkLocaleFormat(v *violations, order *models.Order) {
+	if locale := order.Locale; locale != "" && !validLocale(locale) {
+		v.add("/locale", CodeInvalidLocale, locale, "некорректный тег языка BCP 47")
+	}
+}
+
+// zipFormat проверяет индекс по правилам страны, определенной по телефону, иначе страны по умолчанию.
+// Если страна неизвестна, проверяется только общий вид индекса.
+func zipFormat(def *country) func(v *violations, order *models.Order) {
+	return func(v *violations, order *models.Order) {
+		zip := order.Delivery.Zip
+		if zip == "" {
+			return
+		}
+		pattern := anyZip
+		if c, ok := countryByPhone(order.Delivery.Phone); ok {
+			pattern = c.zip
+		} else if def != nil {
+			pattern = def.zip
+		}
+		if !pattern.MatchString(zip) {
+			v.add("/delivery/zip", CodeInvalidZip, zip, "некорректный почтовый индекс")
+		}
+	}
+}
+
+// normalizePhone убирает разделители цифр и приводит номер к E.164: 00 заменяется на +,
+// у номера без кода страны префикс внутреннего набора страны def заменяется ее кодом.
+// Номер, который не удалось привести к E.164, возвращается без изменений, кроме пробелов по краям.
+func normalizePhone(phone string, def *country) string {
+	phone = strings.TrimSpace(phone)
+	s := phoneSeparators.Replace(phone)
+	switch {
+	case strings.HasPrefix(s, "+"):
+	case strings.HasPrefix(s, "00"):
+		s = "+" + s[2:]
+	case def != nil && def.trunkPrefix != "" && strings.HasPrefix(s, def.trunkPrefix):
+		s = "+" + def.callingCode + s[len(def.trunkPrefix):]
+	case def != nil:
+		s = "+" + def.callingCode + s
+	}
+	if !e164.MatchString(s) {
+		return phone
+	}
+	return s
+}
+
+// normalizeEmail приводит домен к нижнему регистру; локальная часть адреса чувствительна к регистру.
+func normalizeEmail(email string) string {
+	email = strings.TrimSpace(email)
+	at := strings.LastIndexByte(email, '@')
+	if at < 0 {
+		return email
+	}
+	return email[:at+1] + strings.ToLower(email[at+1:])
+}
+
+// normalizeLocale приводит тег языка к каноническому виду BCP 47 (en_us → en-US).
+func normalizeLocale(locale string) string {
+	locale = strings.TrimSpace(locale)
+	tag, err := language.Parse(locale)
+	if err != nil {
+		return locale
+	}
+	return tag.String()
+}
+
+func normalizeZip(zip string) string {
+	return strings.ToUpper(strings.Join(strings.Fields(zip), " "))
+}
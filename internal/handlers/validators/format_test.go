@@ -0,0 +1,168 @@
+package validators_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/sunr3d/order-stream-processor/internal/config"
+	"github.com/sunr3d/order-stream-processor/internal/handlers/validators"
+	"github.com/sunr3d/order-stream-processor/models"
+)
+
+func formatRules(mode validators.Mode) config.ValidationConfig {
+	m := string(mode)
+	return config.ValidationConfig{
+		EmailFormat:    m,
+		PhoneFormat:    m,
+		ZipFormat:      m,
+		CurrencyFormat: m,
+		LocaleFormat:   m,
+	}
+}
+
+// formatViolations возвращает коды нарушений заказа по путям.
+func formatViolations(t *testing.T, v *validators.Validator, order *models.Order) map[string]string {
+	t.Helper()
+
+	err := v.ValidateOrder(order)
+	if err == nil {
+		return nil
+	}
+	var verr *models.ValidationError
+	require.ErrorAs(t, err, &verr)
+
+	codes := make(map[string]string, len(verr.Violations))
+	for _, violation := range verr.Violations {
+		codes[violation.Path] = violation.Code
+	}
+	return codes
+}
+
+func TestValidator_Formats_Valid(t *testing.T) {
+	v, err := validators.New(formatRules(validators.ModeReject), zap.NewNop())
+	require.NoError(t, err)
+
+	order := consistentOrder()
+	order.Delivery.Zip = "2639809"
+	order.Payment.Currency = "USD"
+	order.Locale = "en"
+	assert.NoError(t, v.ValidateOrder(order))
+
+	order.Locale = "zh-Hant-TW"
+	order.Delivery.Email = "first.last+tag@mail.example.co.uk"
+	assert.NoError(t, v.ValidateOrder(order))
+}
+
+func TestValidator_Formats_Invalid(t *testing.T) {
+	v, err := validators.New(formatRules(validators.ModeReject), zap.NewNop())
+	require.NoError(t, err)
+
+	order := consistentOrder()
+	order.Delivery.Email = "test@localhost"
+	order.Delivery.Phone = "8 (999) 123-45-67"
+	order.Delivery.Zip = "#123"
+	order.Payment.Currency = "ABC"
+	order.Locale = "english"
+
+	assert.Equal(t, map[string]string{
+		"/delivery/email":   validators.CodeInvalidEmail,
+		"/delivery/phone":   validators.CodeInvalidPhone,
+		"/delivery/zip":     validators.CodeInvalidZip,
+		"/payment/currency": validators.CodeInvalidCurrency,
+		"/locale":           validators.CodeInvalidLocale,
+	}, formatViolations(t, v, order))
+}
+
+func TestValidator_ZipByCountry(t *testing.T) {
+	v, err := validators.New(formatRules(validators.ModeReject), zap.NewNop())
+	require.NoError(t, err)
+
+	order := consistentOrder()
+	order.Delivery.Phone = "+79991234567"
+	order.Delivery.Zip = "2639809"
+	assert.Equal(t, map[string]string{"/delivery/zip": validators.CodeInvalidZip}, formatViolations(t, v, order),
+		"индекс проверяется по стране телефона")
+
+	order.Delivery.Zip = "119991"
+	assert.NoError(t, v.ValidateOrder(order))
+
+	// +1 — общий код США и Канады: без страны по умолчанию проверяется только общий вид индекса
+	order.Delivery.Phone = "+14155550123"
+	order.Delivery.Zip = "K1A 0B1"
+	assert.NoError(t, v.ValidateOrder(order))
+
+	cfg := formatRules(validators.ModeReject)
+	cfg.Country = "us"
+	v, err = validators.New(cfg, zap.NewNop())
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"/delivery/zip": validators.CodeInvalidZip}, formatViolations(t, v, order))
+}
+
+func TestValidator_Normalize(t *testing.T) {
+	cfg := formatRules(validators.ModeReject)
+	cfg.Normalize = true
+	cfg.Country = "RU"
+	v, err := validators.New(cfg, zap.NewNop())
+	require.NoError(t, err)
+
+	order := consistentOrder()
+	order.Delivery.Email = " Test.User@GMAIL.com "
+	order.Delivery.Phone = "8 (999) 123-45-67"
+	order.Delivery.Zip = " 119991 "
+	order.Payment.Currency = "rub"
+	order.Locale = "ru_ru"
+
+	v.Normalize(order)
+
+	assert.Equal(t, "Test.User@gmail.com", order.Delivery.Email)
+	assert.Equal(t, "+79991234567", order.Delivery.Phone)
+	assert.Equal(t, "119991", order.Delivery.Zip)
+	assert.Equal(t, "RUB", order.Payment.Currency)
+	assert.Equal(t, "ru-RU", order.Locale)
+	assert.NoError(t, v.ValidateOrder(order))
+}
+
+func TestValidator_NormalizePhone(t *testing.T) {
+	tests := []struct {
+		country string
+		phone   string
+		want    string
+	}{
+		{"RU", "9991234567", "+79991234567"},
+		{"RU", "+7 999 123-45-67", "+79991234567"},
+		{"RU", "0049 30 123456", "+4930123456"},
+		{"IL", "02-000-0000", "+97220000000"},
+		{"", "8 (999) 123-45-67", "8 (999) 123-45-67"},
+		{"RU", "не номер", "не номер"},
+	}
+
+	for _, tt := range tests {
+		v, err := validators.New(config.ValidationConfig{Normalize: true, Country: tt.country}, zap.NewNop())
+		require.NoError(t, err)
+
+		order := consistentOrder()
+		order.Delivery.Phone = tt.phone
+		v.Normalize(order)
+
+		assert.Equal(t, tt.want, order.Delivery.Phone, "%s %s", tt.country, tt.phone)
+	}
+}
+
+func TestValidator_NormalizeDisabled(t *testing.T) {
+	v, err := validators.New(config.ValidationConfig{Country: "RU"}, zap.NewNop())
+	require.NoError(t, err)
+
+	order := consistentOrder()
+	order.Payment.Currency = "usd"
+	v.Normalize(order)
+
+	assert.Equal(t, "usd", order.Payment.Currency)
+}
+
+func TestNew_UnknownCountry(t *testing.T) {
+	_, err := validators.New(config.ValidationConfig{Country: "XX"}, zap.NewNop())
+	assert.Error(t, err)
+}
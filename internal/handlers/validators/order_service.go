@@ -7,98 +7,93 @@ import (
 	"github.com/sunr3d/order-stream-processor/models"
 )
 
-func ValidateOrder(order *models.Order) error {
-	// Основные поля
-	if strings.TrimSpace(order.OrderUID) == "" {
-		return fmt.Errorf("order_uid не может быть пустым")
+// Коды нарушений обязательных полей
+const (
+	CodeRequired      = "required"
+	CodePositive      = "must_be_positive"
+	CodeNonNegative   = "must_be_non_negative"
+	CodeInvalidStatus = "invalid_status"
+)
+
+// violations собирает нарушения валидации.
+type violations []models.Violation
+
+func (v *violations) add(path, code string, value any, format string, args ...any) {
+	*v = append(*v, models.Violation{Path: path, Code: code, Value: value, Message: fmt.Sprintf(format, args...)})
+}
+
+func (v *violations) required(path, value string) {
+	if strings.TrimSpace(value) == "" {
+		v.add(path, CodeRequired, value, "не может быть пустым")
 	}
-	if strings.TrimSpace(order.CustomerID) == "" {
-		return fmt.Errorf("customer_id не может быть пустым")
+}
+
+func (v *violations) positive(path string, value int64) {
+	if value <= 0 {
+		v.add(path, CodePositive, value, "должно быть больше 0")
 	}
-	if strings.TrimSpace(order.TrackNumber) == "" {
-		return fmt.Errorf("track_number не может быть пустым")
+}
+
+func (v *violations) nonNegative(path string, value int64) {
+	if value < 0 {
+		v.add(path, CodeNonNegative, value, "не может быть меньше 0")
 	}
-	if strings.TrimSpace(order.DeliveryService) == "" {
-		return fmt.Errorf("delivery_service не может быть пустым")
+}
+
+// err возвращает *models.ValidationError со всеми нарушениями или nil, если их нет.
+func (v violations) err() error {
+	if len(v) == 0 {
+		return nil
 	}
+	return &models.ValidationError{Violations: v}
+}
+
+func validateOrder(v *violations, order *models.Order) {
+	// Основные поля
+	v.required("/order_uid", order.OrderUID)
+	v.required("/customer_id", order.CustomerID)
+	v.required("/track_number", order.TrackNumber)
+	v.required("/delivery_service", order.DeliveryService)
 	if order.DateCreated.IsZero() {
-		return fmt.Errorf("date_created не может быть пустым")
+		v.add("/date_created", CodeRequired, nil, "не может быть пустым")
+	}
+	if order.Status != "" && order.Status != models.StatusCreated {
+		v.add("/status", CodeInvalidStatus, order.Status, "статус нового заказа может быть только %s", models.StatusCreated)
 	}
 
 	// Поля доставки
-	if strings.TrimSpace(order.Delivery.Name) == "" {
-		return fmt.Errorf("delivery.name не может быть пустым")
-	}
-	if strings.TrimSpace(order.Delivery.Phone) == "" {
-		return fmt.Errorf("delivery.phone не может быть пустым")
-	}
-	if strings.TrimSpace(order.Delivery.Email) == "" {
-		return fmt.Errorf("delivery.email не может быть пустым")
-	}
-	if strings.TrimSpace(order.Delivery.City) == "" {
-		return fmt.Errorf("delivery.city не может быть пустым")
-	}
-	if strings.TrimSpace(order.Delivery.Address) == "" {
-		return fmt.Errorf("delivery.address не может быть пустым")
-	}
+	v.required("/delivery/name", order.Delivery.Name)
+	v.required("/delivery/phone", order.Delivery.Phone)
+	v.required("/delivery/email", order.Delivery.Email)
+	v.required("/delivery/city", order.Delivery.City)
+	v.required("/delivery/address", order.Delivery.Address)
 
 	// Поля платежа
-	if strings.TrimSpace(order.Payment.Transaction) == "" {
-		return fmt.Errorf("payment.transaction не может быть пустым")
-	}
-	if strings.TrimSpace(order.Payment.Provider) == "" {
-		return fmt.Errorf("payment.provider не может быть пустым")
-	}
-	if order.Payment.GoodsTotal <= 0 {
-		return fmt.Errorf("payment.goods_total не может быть меньше или равно 0")
-	}
-	if order.Payment.DeliveryCost < 0 {
-		return fmt.Errorf("payment.delivery_cost не может быть меньше 0")
-	}
-	if order.Payment.CustomFee < 0 {
-		return fmt.Errorf("payment.custom_fee не может быть меньше 0")
-	}
-	if order.Payment.Amount <= 0 {
-		return fmt.Errorf("payment.amount не может быть меньше или равно 0")
-	}
-	if order.Payment.PaymentDT <= 0 {
-		return fmt.Errorf("payment.payment_dt не может быть меньше или равно 0")
-	}
-
-	// Проверяем товары
-	if err := validateItems(order.Items); err != nil {
-		return fmt.Errorf("validateItems: %w", err)
-	}
+	v.required("/payment/transaction", order.Payment.Transaction)
+	v.required("/payment/provider", order.Payment.Provider)
+	v.positive("/payment/goods_total", int64(order.Payment.GoodsTotal))
+	v.nonNegative("/payment/delivery_cost", int64(order.Payment.DeliveryCost))
+	v.nonNegative("/payment/custom_fee", int64(order.Payment.CustomFee))
+	v.positive("/payment/amount", int64(order.Payment.Amount))
+	v.positive("/payment/payment_dt", order.Payment.PaymentDT)
 
-	return nil
+	// Товары
+	validateItems(v, order.Items)
 }
 
-func validateItems(items []models.Item) error {
+func validateItems(v *violations, items []models.Item) {
 	if len(items) == 0 {
-		return fmt.Errorf("items не может быть пустым")
+		v.add("/items", CodeRequired, items, "не может быть пустым")
+		return
 	}
 	for i, item := range items {
-		if item.ChrtID <= 0 {
-			return fmt.Errorf("items[%d].chrt_id не может быть меньше или равно 0", i)
-		}
-		if strings.TrimSpace(item.Name) == "" {
-			return fmt.Errorf("items[%d].name не может быть пустым", i)
-		}
-		if strings.TrimSpace(item.Brand) == "" {
-			return fmt.Errorf("items[%d].brand не может быть пустым", i)
-		}
-		if strings.TrimSpace(item.Size) == "" {
-			return fmt.Errorf("items[%d].size не может быть пустым", i)
-		}
-		if item.Price <= 0 {
-			return fmt.Errorf("items[%d].price не может быть меньше или равно 0", i)
-		}
-		if item.Sale < 0 {
-			return fmt.Errorf("items[%d].sale не может быть меньше 0", i)
-		}
-		if item.TotalPrice <= 0 {
-			return fmt.Errorf("items[%d].total_price не может быть меньше или равно 0", i)
-		}
+		path := fmt.Sprintf("/items/%d", i)
+		v.positive(path+"/chrt_id", int64(item.ChrtID))
+		v.required(path+"/name", item.Name)
+		v.required(path+"/brand", item.Brand)
+		v.required(path+"/size", item.Size)
+		v.positive(path+"/price", int64(item.Price))
+		v.nonNegative(path+"/sale", int64(item.Sale))
+		v.positive(path+"/total_price", int64(item.TotalPrice))
 	}
-	return nil
 }
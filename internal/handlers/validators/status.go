@@ -0,0 +1,17 @@
+package validators
+
+import (
+	"github.com/sunr3d/order-stream-processor/models"
+)
+
+// ValidateStatusChange проверяет команду смены статуса и возвращает *models.ValidationError
+// со всеми найденными нарушениями.
+func ValidateStatusChange(change *models.StatusChange) error {
+	var v violations
+	v.required("/order_uid", change.OrderUID)
+	if !change.To.Valid() {
+		v.add("/status", CodeInvalidStatus, change.To, "неизвестный статус: %s", change.To)
+	}
+	v.required("/changed_by", change.ChangedBy)
+	return v.err()
+}
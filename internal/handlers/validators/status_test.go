@@ -0,0 +1,33 @@
+package validators_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sunr3d/order-stream-processor/internal/handlers/validators"
+	"github.com/sunr3d/order-stream-processor/models"
+)
+
+func TestValidateStatusChange(t *testing.T) {
+	assert.NoError(t, validators.ValidateStatusChange(&models.StatusChange{OrderUID: "a", To: models.StatusPaid, ChangedBy: "operator"}))
+
+	var verr *models.ValidationError
+	require.ErrorAs(t, validators.ValidateStatusChange(&models.StatusChange{To: "lost"}), &verr)
+	assert.Equal(t, []models.Violation{
+		{Path: "/order_uid", Code: validators.CodeRequired, Value: "", Message: "не может быть пустым"},
+		{Path: "/status", Code: validators.CodeInvalidStatus, Value: models.OrderStatus("lost"), Message: "неизвестный статус: lost"},
+		{Path: "/changed_by", Code: validators.CodeRequired, Value: "", Message: "не может быть пустым"},
+	}, verr.Violations)
+}
+
+func TestValidateErasure(t *testing.T) {
+	assert.NoError(t, validators.ValidateDeletion(&models.DeletionRequest{OrderUID: "a", RequestedBy: "dpo"}))
+	assert.NoError(t, validators.ValidateErasure(&models.ErasureRequest{CustomerID: "c", RequestedBy: "dpo"}))
+
+	var verr *models.ValidationError
+	require.ErrorAs(t, validators.ValidateDeletion(&models.DeletionRequest{OrderUID: "a", RequestedBy: " "}), &verr)
+	assert.Equal(t, "/requested_by", verr.Violations[0].Path)
+	assert.ErrorIs(t, validators.ValidateErasure(&models.ErasureRequest{RequestedBy: "dpo"}), models.ErrValidation)
+}
@@ -0,0 +1,151 @@
+package validators
+
+import (
+	"fmt"
+	"strings"
+
+	"go.uber.org/zap"
+
+	"github.com/sunr3d/order-stream-processor/internal/config"
+	"github.com/sunr3d/order-stream-processor/internal/metrics"
+	"github.com/sunr3d/order-stream-processor/models"
+)
+
+// Mode — реакция на нарушение правила валидации заказа.
+type Mode string
+
+const (
+	// ModeReject — заказ отклоняется как невалидный
+	ModeReject Mode = "reject"
+	// ModeWarn — заказ принимается, нарушение записывается в лог и метрики
+	ModeWarn Mode = "warn"
+	// ModeOff — правило не проверяется
+	ModeOff Mode = "off"
+)
+
+// rule проверяет поля заказа и добавляет найденные нарушения.
+type rule struct {
+	name  string
+	mode  Mode
+	check func(v *violations, order *models.Order)
+}
+
+// Validator проверяет заказ: обязательные поля (ValidateOrder) всегда, правила согласованности
+// и форматов полей — в режимах, заданных в config.ValidationConfig.
+type Validator struct {
+	rules     []rule
+	normalize bool
+	// country — страна по умолчанию для телефонов без кода страны и индексов, nil — не задана
+	country *country
+	logger  *zap.Logger
+}
+
+// New создает Validator; пустой режим правила означает ModeWarn.
+func New(cfg config.ValidationConfig, logger *zap.Logger) (*Validator, error) {
+	v := &Validator{normalize: cfg.Normalize, logger: logger}
+
+	if cfg.Country != "" {
+		c, ok := countries[strings.ToUpper(cfg.Country)]
+		if !ok {
+			return nil, fmt.Errorf("неизвестная страна по умолчанию %q", cfg.Country)
+		}
+		v.country = &c
+	}
+
+	checks := []struct {
+		name  string
+		mode  string
+		check func(v *violations, order *models.Order)
+	}{
+		{RuleAmountTotal, cfg.AmountTotal, checkAmountTotal},
+		{RuleGoodsTotal, cfg.GoodsTotal, checkGoodsTotal},
+		{RuleItemTotalPrice, cfg.ItemTotalPrice, checkItemTotalPrice},
+		{RuleItemTrackNumber, cfg.ItemTrackNumber, checkItemTrackNumber},
+		{RulePaymentLink, cfg.PaymentLink, checkPaymentLink},
+		{RuleDateCreated, cfg.DateCreated, dateNotInFuture(cfg.ClockSkew)},
+		{RuleEmailFormat, cfg.EmailFormat, checkEmailFormat},
+		{RulePhoneFormat, cfg.PhoneFormat, checkPhoneFormat},
+		{RuleZipFormat, cfg.ZipFormat, zipFormat(v.country)},
+		{RuleCurrencyFormat, cfg.CurrencyFormat, checkCurrencyFormat},
+		{RuleLocaleFormat, cfg.LocaleFormat, checkLocaleFormat},
+	}
+	for _, c := range checks {
+		mode, err := parseMode(c.mode)
+		if err != nil {
+			return nil, fmt.Errorf("правило %s: %w", c.name, err)
+		}
+		if mode == ModeOff {
+			continue
+		}
+		v.rules = append(v.rules, rule{name: c.name, mode: mode, check: c.check})
+	}
+
+	return v, nil
+}
+
+func parseMode(s string) (Mode, error) {
+	switch mode := Mode(s); mode {
+	case "":
+		return ModeWarn, nil
+	case ModeReject, ModeWarn, ModeOff:
+		return mode, nil
+	default:
+		return "", fmt.Errorf("неизвестный режим %q, допустимы %s, %s и %s", s, ModeReject, ModeWarn, ModeOff)
+	}
+}
+
+// ValidateOrder проверяет обязательные поля заказа и правила и возвращает *models.ValidationError
+// со всеми нарушениями обязательных полей и правил в режиме ModeReject.
+// Нарушения правил в режиме ModeWarn записываются в лог и не мешают принять заказ.
+func (v *Validator) ValidateOrder(order *models.Order) error {
+	var rejected violations
+	validateOrder(&rejected, order)
+
+	for _, r := range v.rules {
+		var found violations
+		r.check(&found, order)
+		if len(found) == 0 {
+			continue
+		}
+
+		metrics.OrderRuleViolation(r.name, string(r.mode))
+		if r.mode == ModeReject {
+			rejected = append(rejected, found...)
+			continue
+		}
+		v.logger.Warn("заказ принят с нарушением правила валидации",
+			zap.String("op", "validators.ValidateOrder"),
+			zap.String("order_uid", order.OrderUID),
+			zap.String("rule", r.name),
+			zap.Any("violations", found),
+		)
+	}
+
+	return rejected.err()
+}
+
+// Normalize приводит email, телефон, почтовый индекс, валюту и локаль заказа к каноническому виду,
+// если включена нормализация (VALIDATION_NORMALIZE). Вызывается до ValidateOrder, чтобы проверялись
+// и сохранялись уже приведенные значения. Значения, которые не удалось привести, не меняются.
+func (v *Validator) Normalize(order *models.Order) {
+	if !v.normalize {
+		return
+	}
+
+	d := &order.Delivery
+	if d.Email != "" {
+		d.Email = normalizeEmail(d.Email)
+	}
+	if d.Phone != "" {
+		d.Phone = normalizePhone(d.Phone, v.country)
+	}
+	if d.Zip != "" {
+		d.Zip = normalizeZip(d.Zip)
+	}
+	if order.Payment.Currency != "" {
+		order.Payment.Currency = strings.ToUpper(strings.TrimSpace(order.Payment.Currency))
+	}
+	if order.Locale != "" {
+		order.Locale = normalizeLocale(order.Locale)
+	}
+}
@@ -0,0 +1,29 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+)
+
+// Flag — проверка по флагу, который выставляет сам сервис (например, по завершении прогрева кэша).
+type Flag struct {
+	ready  atomic.Bool
+	reason error
+}
+
+// NewFlag создает невыставленный флаг; reason возвращается проверкой, пока флаг не выставлен.
+func NewFlag(reason string) *Flag {
+	return &Flag{reason: errors.New(reason)}
+}
+
+func (f *Flag) Set(ready bool) {
+	f.ready.Store(ready)
+}
+
+func (f *Flag) Check(ctx context.Context) error {
+	if f.ready.Load() {
+		return nil
+	}
+	return f.reason
+}
@@ -0,0 +1,135 @@
+// Package health агрегирует проверки состояния компонентов сервиса для readiness-пробы.
+package health
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+const (
+	StatusOK        = "ok"
+	StatusDegraded  = "degraded"
+	StatusUnhealthy = "unhealthy"
+)
+
+// CheckFunc проверяет состояние компонента; nil означает, что компонент исправен.
+type CheckFunc func(ctx context.Context) error
+
+type component struct {
+	name     string
+	critical bool
+	check    CheckFunc
+}
+
+// ComponentStatus — результат проверки одного компонента.
+type ComponentStatus struct {
+	Status     string `json:"status"`
+	Critical   bool   `json:"critical"`
+	Error      string `json:"error,omitempty"`
+	DurationMs int64  `json:"duration_ms"`
+}
+
+// Report — сводный результат проверки.
+// Status равен StatusUnhealthy, если неисправен хотя бы один критичный компонент,
+// и StatusDegraded, если неисправны только некритичные.
+type Report struct {
+	Status     string                     `json:"status"`
+	Components map[string]ComponentStatus `json:"components"`
+}
+
+// Healthy сообщает, готов ли сервис принимать трафик.
+func (r Report) Healthy() bool {
+	return r.Status != StatusUnhealthy
+}
+
+// Checker выполняет зарегистрированные проверки параллельно, каждую — с таймаутом.
+type Checker struct {
+	timeout    time.Duration
+	components []component
+	mu         sync.RWMutex
+}
+
+func New(timeout time.Duration) *Checker {
+	return &Checker{timeout: timeout}
+}
+
+// Add регистрирует проверку компонента. Неисправность критичного компонента делает сервис неготовым.
+func (c *Checker) Add(name string, critical bool, check CheckFunc) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.components = append(c.components, component{name: name, critical: critical, check: check})
+}
+
+func (c *Checker) Check(ctx context.Context) Report {
+	c.mu.RLock()
+	components := append([]component(nil), c.components...)
+	c.mu.RUnlock()
+
+	results := make([]ComponentStatus, len(components))
+
+	var wg sync.WaitGroup
+	for i, comp := range components {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i] = c.run(ctx, comp)
+		}()
+	}
+	wg.Wait()
+
+	report := Report{Status: StatusOK, Components: make(map[string]ComponentStatus, len(components))}
+	for i, comp := range components {
+		res := results[i]
+		report.Components[comp.name] = res
+
+		if res.Status == StatusOK {
+			continue
+		}
+		if comp.critical {
+			report.Status = StatusUnhealthy
+		} else if report.Status == StatusOK {
+			report.Status = StatusDegraded
+		}
+	}
+
+	return report
+}
+
+func (c *Checker) run(ctx context.Context, comp component) ComponentStatus {
+	if c.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.timeout)
+		defer cancel()
+	}
+
+	start := time.Now()
+	err := runCheck(ctx, comp.check)
+
+	res := ComponentStatus{
+		Status:     StatusOK,
+		Critical:   comp.critical,
+		DurationMs: time.Since(start).Milliseconds(),
+	}
+	if err != nil {
+		res.Status = StatusUnhealthy
+		res.Error = err.Error()
+	}
+	return res
+}
+
+// runCheck не дает зависшей проверке, игнорирующей контекст, задержать ответ дольше таймаута.
+func runCheck(ctx context.Context, check CheckFunc) error {
+	done := make(chan error, 1)
+	go func() {
+		done <- check(ctx)
+	}()
+
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
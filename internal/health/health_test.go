@@ -0,0 +1,79 @@
+package health_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/sunr3d/order-stream-processor/internal/health"
+)
+
+func ok(context.Context) error { return nil }
+
+func failing(context.Context) error { return errors.New("нет соединения") }
+
+func TestChecker_AllHealthy(t *testing.T) {
+	checker := health.New(time.Second)
+	checker.Add("postgres", true, ok)
+	checker.Add("kafka", true, ok)
+
+	report := checker.Check(context.Background())
+
+	assert.True(t, report.Healthy())
+	assert.Equal(t, health.StatusOK, report.Status)
+	assert.Len(t, report.Components, 2)
+	assert.Equal(t, health.StatusOK, report.Components["postgres"].Status)
+}
+
+func TestChecker_CriticalFailure(t *testing.T) {
+	checker := health.New(time.Second)
+	checker.Add("postgres", true, failing)
+	checker.Add("kafka", true, ok)
+
+	report := checker.Check(context.Background())
+
+	assert.False(t, report.Healthy())
+	assert.Equal(t, health.StatusUnhealthy, report.Status)
+	assert.Equal(t, health.StatusUnhealthy, report.Components["postgres"].Status)
+	assert.Equal(t, "нет соединения", report.Components["postgres"].Error)
+	assert.Equal(t, health.StatusOK, report.Components["kafka"].Status)
+}
+
+func TestChecker_NonCriticalFailure(t *testing.T) {
+	checker := health.New(time.Second)
+	checker.Add("postgres", true, ok)
+	checker.Add("optional", false, failing)
+
+	report := checker.Check(context.Background())
+
+	assert.True(t, report.Healthy())
+	assert.Equal(t, health.StatusDegraded, report.Status)
+}
+
+func TestChecker_Timeout(t *testing.T) {
+	checker := health.New(20 * time.Millisecond)
+	checker.Add("hanging", true, func(context.Context) error {
+		time.Sleep(time.Second)
+		return nil
+	})
+
+	start := time.Now()
+	report := checker.Check(context.Background())
+
+	assert.Less(t, time.Since(start), 500*time.Millisecond)
+	assert.False(t, report.Healthy())
+	assert.Contains(t, report.Components["hanging"].Error, context.DeadlineExceeded.Error())
+}
+
+func TestFlag(t *testing.T) {
+	flag := health.NewFlag("прогрев не завершен")
+	ctx := context.Background()
+
+	assert.EqualError(t, flag.Check(ctx), "прогрев не завершен")
+
+	flag.Set(true)
+	assert.NoError(t, flag.Check(ctx))
+}
@@ -1,29 +1,73 @@
 package inmem
 
 import (
+	"container/list"
 	"context"
 	"fmt"
+	"slices"
 	"sync"
+	"time"
 
 	"go.uber.org/zap"
 
+	"github.com/sunr3d/order-stream-processor/internal/config"
 	"github.com/sunr3d/order-stream-processor/internal/interfaces/infra"
 	"github.com/sunr3d/order-stream-processor/models"
 )
 
 var _ infra.Cache = (*inmemCache)(nil)
+var _ infra.CacheStatsProvider = (*inmemCache)(nil)
+var _ infra.CacheDumper = (*inmemCache)(nil)
+var _ infra.CacheIndex = (*inmemCache)(nil)
 
+type entry struct {
+	key       string
+	order     *models.Order
+	size      int64
+	expiresAt time.Time
+
+	// Служебные поля политик вытеснения
+	elem  *list.Element
+	freq  uint64
+	tick  uint64
+	index int
+}
+
+// inmemCache — ограниченный кэш заказов: лимит по числу записей и приблизительному объему,
+// TTL записей и вытеснение по выбранной политике (LRU/LFU).
+// Просроченные записи удаляются лениво — при обращении или вытеснении.
 type inmemCache struct {
-	data   map[string]*models.Order
-	mu     sync.RWMutex
+	data map[string]*entry
+	// index — вторичные индексы: ключ поиска -> значение ключа -> order_uid
+	index  map[models.LookupField]map[string]map[string]struct{}
+	policy evictionPolicy
+	bytes  int64
+	stats  infra.CacheStats
+	cfg    config.CacheConfig
+	now    func() time.Time
+	mu     sync.Mutex
 	logger *zap.Logger
 }
 
-func New(log *zap.Logger) infra.Cache {
+func New(cfg config.CacheConfig, log *zap.Logger) (infra.Cache, error) {
+	policy, err := newPolicy(cfg.Policy)
+	if err != nil {
+		return nil, fmt.Errorf("newPolicy: %w", err)
+	}
+
+	index := make(map[models.LookupField]map[string]map[string]struct{}, len(models.LookupFields))
+	for _, field := range models.LookupFields {
+		index[field] = make(map[string]map[string]struct{})
+	}
+
 	return &inmemCache{
-		data:   make(map[string]*models.Order),
+		data:   make(map[string]*entry),
+		index:  index,
+		policy: policy,
+		cfg:    cfg,
+		now:    time.Now,
 		logger: log,
-	}
+	}, nil
 }
 
 func (c *inmemCache) Set(ctx context.Context, orderUID string, order *models.Order) error {
@@ -37,9 +81,19 @@ func (c *inmemCache) Set(ctx context.Context, orderUID string, order *models.Ord
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
-	c.data[orderUID] = order
+	// Конкурентные обновления могут записывать заказ в кэш не в порядке фиксации в БД:
+	// более старая версия не должна затереть новую
+	if e, ok := c.data[orderUID]; ok && !c.expired(e) && e.order.Version > order.Version {
+		logger.Info("в кэше более новая версия заказа, запись пропущена",
+			zap.Int64("cached_version", e.order.Version),
+			zap.Int64("version", order.Version),
+		)
+		return nil
+	}
+
+	evicted := c.put(orderUID, order)
 
-	logger.Info("заказ успешно сохранен в кэше")
+	logger.Info("заказ успешно сохранен в кэше", zap.Int("evicted", evicted))
 	return nil
 }
 
@@ -51,19 +105,30 @@ func (c *inmemCache) Get(ctx context.Context, orderUID string) (*models.Order, e
 
 	logger.Info("поиск заказа в кэше...")
 
-	c.mu.RLock()
-	defer c.mu.RUnlock()
+	c.mu.Lock()
+	defer c.mu.Unlock()
 
-	order, exists := c.data[orderUID]
+	e, exists := c.data[orderUID]
+	if exists && c.expired(e) {
+		c.remove(e)
+		c.stats.Expirations++
+		exists = false
+	}
 	if !exists {
+		c.stats.Misses++
 		logger.Info("заказ не найден в кэше")
-		return nil, fmt.Errorf("заказ не найден в кэше: %s", orderUID)
+		return nil, fmt.Errorf("%w в кэше: %s", models.ErrOrderNotFound, orderUID)
 	}
 
+	c.policy.touch(e)
+	c.stats.Hits++
+
 	logger.Info("заказ успешно найден в кэше")
-	return order, nil
+	return e.order, nil
 }
 
+// Restore добавляет заказы в кэш, не очищая его: заказы, записанные через Set во время
+// восстановления, сохраняются, а более новая версия заказа в кэше не заменяется.
 func (c *inmemCache) Restore(ctx context.Context, orders []*models.Order) error {
 	logger := c.logger.With(
 		zap.String("op", "inmem.Restore"),
@@ -75,12 +140,191 @@ func (c *inmemCache) Restore(ctx context.Context, orders []*models.Order) error
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
-	c.data = make(map[string]*models.Order)
+	restored, skipped, evicted := 0, 0, 0
 	for _, order := range orders {
-		c.data[order.OrderUID] = order
+		if e, ok := c.data[order.OrderUID]; ok && !c.expired(e) && e.order.Version > order.Version {
+			skipped++
+			continue
+		}
+		evicted += c.put(order.OrderUID, order)
+		restored++
 	}
 
-	logger.Info("все заказы успешно восстановлены", zap.Int("restored_count", len(c.data)))
+	logger.Info("заказы восстановлены в кэш",
+		zap.Int("restored_count", restored),
+		zap.Int("skipped", skipped),
+		zap.Int("evicted", evicted),
+		zap.Int("entries", len(c.data)),
+	)
 
 	return nil
 }
+
+func (c *inmemCache) Delete(ctx context.Context, orderUID string) error {
+	logger := c.logger.With(
+		zap.String("op", "inmem.Delete"),
+		zap.String("order_uid", orderUID),
+	)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if e, ok := c.data[orderUID]; ok {
+		c.remove(e)
+		logger.Info("заказ удален из кэша")
+	}
+
+	return nil
+}
+
+// Lookup ищет заказы по вторичному ключу среди неистекших записей кэша.
+func (c *inmemCache) Lookup(ctx context.Context, lookup models.OrderLookup) ([]*models.Order, error) {
+	logger := c.logger.With(
+		zap.String("op", "inmem.Lookup"),
+		zap.String("field", string(lookup.Field)),
+		zap.String("value", lookup.Value),
+	)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	byValue, ok := c.index[lookup.Field]
+	if !ok {
+		return nil, fmt.Errorf("%w: неизвестный ключ поиска %q", models.ErrValidation, lookup.Field)
+	}
+
+	orders := make([]*models.Order, 0, len(byValue[lookup.Value]))
+	for uid := range byValue[lookup.Value] {
+		if e := c.data[uid]; !c.expired(e) {
+			orders = append(orders, e.order)
+		}
+	}
+	slices.SortFunc(orders, models.CompareNewestFirst)
+	if lookup.Limit > 0 && len(orders) > lookup.Limit {
+		orders = orders[:lookup.Limit]
+	}
+
+	logger.Info("поиск заказов в кэше выполнен", zap.Int("count", len(orders)))
+	return orders, nil
+}
+
+// Dump возвращает все неистекшие заказы из кэша.
+func (c *inmemCache) Dump() []*models.Order {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	orders := make([]*models.Order, 0, len(c.data))
+	for _, e := range c.data {
+		if !c.expired(e) {
+			orders = append(orders, e.order)
+		}
+	}
+	return orders
+}
+
+// Stats возвращает счетчики попаданий, промахов и вытеснений, а также текущий размер кэша.
+func (c *inmemCache) Stats() infra.CacheStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	stats := c.stats
+	stats.Entries = len(c.data)
+	stats.Bytes = c.bytes
+	return stats
+}
+
+// put добавляет или обновляет запись, предварительно освобождая место под нее;
+// возвращает число вытесненных записей.
+func (c *inmemCache) put(key string, order *models.Order) int {
+	size := approxSize(key, order)
+
+	e, exists := c.data[key]
+	if exists {
+		c.remove(e)
+	} else {
+		e = &entry{key: key}
+	}
+
+	evicted := c.makeRoom(size)
+
+	e.order = order
+	e.size = size
+	e.expiresAt = time.Time{}
+	if c.cfg.TTL > 0 {
+		e.expiresAt = c.now().Add(c.cfg.TTL)
+	}
+
+	c.data[key] = e
+	c.bytes += size
+	c.policy.add(e)
+	c.addToIndex(e)
+
+	return evicted
+}
+
+func (c *inmemCache) remove(e *entry) {
+	c.policy.remove(e)
+	c.removeFromIndex(e)
+	delete(c.data, e.key)
+	c.bytes -= e.size
+}
+
+func (c *inmemCache) addToIndex(e *entry) {
+	for field, byValue := range c.index {
+		value := e.order.LookupKey(field)
+		if value == "" {
+			continue
+		}
+		uids, ok := byValue[value]
+		if !ok {
+			uids = make(map[string]struct{}, 1)
+			byValue[value] = uids
+		}
+		uids[e.key] = struct{}{}
+	}
+}
+
+func (c *inmemCache) removeFromIndex(e *entry) {
+	for field, byValue := range c.index {
+		value := e.order.LookupKey(field)
+		uids, ok := byValue[value]
+		if !ok {
+			continue
+		}
+		delete(uids, e.key)
+		if len(uids) == 0 {
+			delete(byValue, value)
+		}
+	}
+}
+
+// makeRoom вытесняет записи, пока новая запись размером size не укладывается в лимиты.
+func (c *inmemCache) makeRoom(size int64) int {
+	evicted := 0
+	for len(c.data) > 0 && c.exceedsLimits(size) {
+		victim := c.policy.victim()
+		if victim == nil {
+			break
+		}
+
+		c.remove(victim)
+		if c.expired(victim) {
+			c.stats.Expirations++
+		} else {
+			c.stats.Evictions++
+		}
+		evicted++
+	}
+	return evicted
+}
+
+func (c *inmemCache) exceedsLimits(size int64) bool {
+	if c.cfg.MaxEntries > 0 && len(c.data)+1 > c.cfg.MaxEntries {
+		return true
+	}
+	return c.cfg.MaxBytes > 0 && c.bytes+size > c.cfg.MaxBytes
+}
+
+func (c *inmemCache) expired(e *entry) bool {
+	return !e.expiresAt.IsZero() && !c.now().Before(e.expiresAt)
+}
@@ -0,0 +1,248 @@
+package inmem
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/sunr3d/order-stream-processor/internal/config"
+	"github.com/sunr3d/order-stream-processor/models"
+)
+
+func newTestCache(t *testing.T, cfg config.CacheConfig) *inmemCache {
+	t.Helper()
+	c, err := New(cfg, zap.NewNop())
+	require.NoError(t, err)
+	return c.(*inmemCache)
+}
+
+func order(uid string) *models.Order {
+	return &models.Order{OrderUID: uid, CustomerID: "customer-" + uid}
+}
+
+func TestInmemCache_LRU_EvictsLeastRecentlyUsed(t *testing.T) {
+	c := newTestCache(t, config.CacheConfig{Policy: PolicyLRU, MaxEntries: 2})
+	ctx := context.Background()
+
+	require.NoError(t, c.Set(ctx, "a", order("a")))
+	require.NoError(t, c.Set(ctx, "b", order("b")))
+	_, err := c.Get(ctx, "a")
+	require.NoError(t, err)
+	require.NoError(t, c.Set(ctx, "c", order("c")))
+
+	_, err = c.Get(ctx, "b")
+	assert.ErrorIs(t, err, models.ErrOrderNotFound)
+	_, err = c.Get(ctx, "a")
+	assert.NoError(t, err)
+	_, err = c.Get(ctx, "c")
+	assert.NoError(t, err)
+
+	stats := c.Stats()
+	assert.Equal(t, uint64(1), stats.Evictions)
+	assert.Equal(t, 2, stats.Entries)
+}
+
+func TestInmemCache_LFU_EvictsLeastFrequentlyUsed(t *testing.T) {
+	c := newTestCache(t, config.CacheConfig{Policy: PolicyLFU, MaxEntries: 2})
+	ctx := context.Background()
+
+	require.NoError(t, c.Set(ctx, "a", order("a")))
+	require.NoError(t, c.Set(ctx, "b", order("b")))
+	for range 3 {
+		_, err := c.Get(ctx, "a")
+		require.NoError(t, err)
+	}
+	_, err := c.Get(ctx, "b")
+	require.NoError(t, err)
+	require.NoError(t, c.Set(ctx, "c", order("c")))
+
+	_, err = c.Get(ctx, "b")
+	assert.ErrorIs(t, err, models.ErrOrderNotFound)
+	_, err = c.Get(ctx, "a")
+	assert.NoError(t, err)
+}
+
+func TestInmemCache_MaxBytes(t *testing.T) {
+	size := approxSize("a", order("a"))
+	c := newTestCache(t, config.CacheConfig{MaxBytes: size * 2})
+	ctx := context.Background()
+
+	for _, uid := range []string{"a", "b", "c"} {
+		require.NoError(t, c.Set(ctx, uid, order(uid)))
+	}
+
+	stats := c.Stats()
+	assert.Equal(t, 2, stats.Entries)
+	assert.LessOrEqual(t, stats.Bytes, size*2)
+	assert.Equal(t, uint64(1), stats.Evictions)
+}
+
+func TestInmemCache_TTL(t *testing.T) {
+	c := newTestCache(t, config.CacheConfig{TTL: time.Minute})
+	ctx := context.Background()
+
+	now := time.Now()
+	c.now = func() time.Time { return now }
+	require.NoError(t, c.Set(ctx, "a", order("a")))
+
+	_, err := c.Get(ctx, "a")
+	assert.NoError(t, err)
+
+	now = now.Add(2 * time.Minute)
+	_, err = c.Get(ctx, "a")
+	assert.ErrorIs(t, err, models.ErrOrderNotFound)
+
+	stats := c.Stats()
+	assert.Equal(t, uint64(1), stats.Hits)
+	assert.Equal(t, uint64(1), stats.Misses)
+	assert.Equal(t, uint64(1), stats.Expirations)
+	assert.Zero(t, stats.Entries)
+	assert.Zero(t, stats.Bytes)
+}
+
+func TestInmemCache_Restore_RespectsLimits(t *testing.T) {
+	c := newTestCache(t, config.CacheConfig{MaxEntries: 2})
+	ctx := context.Background()
+
+	require.NoError(t, c.Set(ctx, "old", order("old")))
+	require.NoError(t, c.Restore(ctx, []*models.Order{order("a"), order("b"), order("c")}))
+
+	stats := c.Stats()
+	assert.Equal(t, 2, stats.Entries)
+	_, err := c.Get(ctx, "old")
+	assert.ErrorIs(t, err, models.ErrOrderNotFound)
+	_, err = c.Get(ctx, "c")
+	assert.NoError(t, err)
+}
+
+func TestInmemCache_Restore_MergesWithExisting(t *testing.T) {
+	c := newTestCache(t, config.CacheConfig{})
+	ctx := context.Background()
+
+	fresh, newer, stale := order("fresh"), order("a"), order("a")
+	newer.Version, stale.Version = 3, 2
+
+	require.NoError(t, c.Set(ctx, "fresh", fresh))
+	require.NoError(t, c.Set(ctx, "a", newer))
+	require.NoError(t, c.Restore(ctx, []*models.Order{stale, order("b")}))
+	require.NoError(t, c.Restore(ctx, []*models.Order{order("c")}))
+
+	assert.Equal(t, 4, c.Stats().Entries, "восстановление не удаляет записанные ранее заказы")
+	got, err := c.Get(ctx, "a")
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), got.Version, "восстановление не затирает более новую версию")
+}
+
+func TestNew_UnknownPolicy(t *testing.T) {
+	_, err := New(config.CacheConfig{Policy: "fifo"}, zap.NewNop())
+	assert.Error(t, err)
+}
+
+func TestInmemCache_KeepsNewerVersion(t *testing.T) {
+	c := newTestCache(t, config.CacheConfig{})
+	ctx := context.Background()
+
+	newer, older := order("a"), order("a")
+	newer.Version, older.Version = 3, 2
+
+	require.NoError(t, c.Set(ctx, "a", newer))
+	require.NoError(t, c.Set(ctx, "a", older))
+
+	got, err := c.Get(ctx, "a")
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), got.Version, "старая версия не затирает новую")
+
+	newest := order("a")
+	newest.Version = 4
+	require.NoError(t, c.Set(ctx, "a", newest))
+	got, err = c.Get(ctx, "a")
+	require.NoError(t, err)
+	assert.Equal(t, int64(4), got.Version)
+}
+
+func TestInmemCache_Delete(t *testing.T) {
+	c := newTestCache(t, config.CacheConfig{})
+	ctx := context.Background()
+
+	require.NoError(t, c.Set(ctx, "a", order("a")))
+	require.NoError(t, c.Delete(ctx, "a"))
+	require.NoError(t, c.Delete(ctx, "missing"), "отсутствующий заказ не ошибка")
+
+	_, err := c.Get(ctx, "a")
+	assert.ErrorIs(t, err, models.ErrOrderNotFound)
+	assert.Zero(t, c.Stats().Bytes)
+}
+
+func TestInmemCache_DumpSkipsExpired(t *testing.T) {
+	c := newTestCache(t, config.CacheConfig{TTL: time.Minute})
+	ctx := context.Background()
+
+	now := time.Now()
+	c.now = func() time.Time { return now }
+	require.NoError(t, c.Set(ctx, "a", order("a")))
+	now = now.Add(30 * time.Second)
+	require.NoError(t, c.Set(ctx, "b", order("b")))
+	now = now.Add(45 * time.Second)
+
+	dump := c.Dump()
+
+	require.Len(t, dump, 1)
+	assert.Equal(t, "b", dump[0].OrderUID)
+}
+
+func TestInmemCache_Lookup(t *testing.T) {
+	c := newTestCache(t, config.CacheConfig{MaxEntries: 3, TTL: time.Minute})
+	ctx := context.Background()
+
+	now := time.Now()
+	c.now = func() time.Time { return now }
+
+	newOrder := func(uid, track, customer string, created time.Time) *models.Order {
+		o := order(uid)
+		o.TrackNumber, o.CustomerID, o.DateCreated = track, customer, created
+		o.Payment.Transaction = "tx-" + uid
+		return o
+	}
+
+	require.NoError(t, c.Set(ctx, "a", newOrder("a", "TRACK-1", "alice", now.Add(-2*time.Hour))))
+	require.NoError(t, c.Set(ctx, "b", newOrder("b", "TRACK-2", "alice", now.Add(-time.Hour))))
+	require.NoError(t, c.Set(ctx, "c", newOrder("c", "TRACK-3", "bob", now)))
+
+	uids := func(lookup models.OrderLookup) []string {
+		t.Helper()
+		orders, err := c.Lookup(ctx, lookup)
+		require.NoError(t, err)
+		out := make([]string, 0, len(orders))
+		for _, o := range orders {
+			out = append(out, o.OrderUID)
+		}
+		return out
+	}
+
+	assert.Equal(t, []string{"b", "a"}, uids(models.OrderLookup{Field: models.LookupCustomer, Value: "alice"}), "от новых к старым")
+	assert.Equal(t, []string{"b"}, uids(models.OrderLookup{Field: models.LookupCustomer, Value: "alice", Limit: 1}))
+	assert.Equal(t, []string{"c"}, uids(models.OrderLookup{Field: models.LookupTransaction, Value: "tx-c"}))
+
+	// Замена заказа переносит его в индексе
+	require.NoError(t, c.Set(ctx, "a", newOrder("a", "TRACK-9", "bob", now.Add(-2*time.Hour))))
+	assert.Empty(t, uids(models.OrderLookup{Field: models.LookupTrackNumber, Value: "TRACK-1"}))
+	assert.Equal(t, []string{"c", "a"}, uids(models.OrderLookup{Field: models.LookupCustomer, Value: "bob"}))
+
+	// Удаленные и вытесненные заказы пропадают из индекса
+	require.NoError(t, c.Delete(ctx, "c"))
+	require.NoError(t, c.Set(ctx, "d", newOrder("d", "TRACK-4", "carol", now)))
+	require.NoError(t, c.Set(ctx, "e", newOrder("e", "TRACK-5", "carol", now)))
+	assert.Empty(t, uids(models.OrderLookup{Field: models.LookupCustomer, Value: "alice"}))
+	assert.Equal(t, []string{"a"}, uids(models.OrderLookup{Field: models.LookupCustomer, Value: "bob"}))
+
+	// Истекшие записи не возвращаются
+	now = now.Add(2 * time.Minute)
+	assert.Empty(t, uids(models.OrderLookup{Field: models.LookupCustomer, Value: "carol"}))
+
+	_, err := c.Lookup(ctx, models.OrderLookup{Field: "email", Value: "x"})
+	assert.ErrorIs(t, err, models.ErrValidation)
+}
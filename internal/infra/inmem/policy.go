@@ -0,0 +1,124 @@
+package inmem
+
+import (
+	"container/heap"
+	"container/list"
+	"fmt"
+)
+
+const (
+	PolicyLRU = "lru"
+	PolicyLFU = "lfu"
+)
+
+// evictionPolicy определяет порядок вытеснения записей при превышении лимитов кэша.
+type evictionPolicy interface {
+	add(e *entry)
+	touch(e *entry)
+	remove(e *entry)
+	victim() *entry
+}
+
+func newPolicy(name string) (evictionPolicy, error) {
+	switch name {
+	case PolicyLRU, "":
+		return &lruPolicy{order: list.New()}, nil
+	case PolicyLFU:
+		return &lfuPolicy{}, nil
+	default:
+		return nil, fmt.Errorf("неизвестная политика вытеснения: %s", name)
+	}
+}
+
+// lruPolicy вытесняет давно не использованные записи.
+type lruPolicy struct {
+	order *list.List
+}
+
+func (p *lruPolicy) add(e *entry) {
+	e.elem = p.order.PushFront(e)
+}
+
+func (p *lruPolicy) touch(e *entry) {
+	p.order.MoveToFront(e.elem)
+}
+
+func (p *lruPolicy) remove(e *entry) {
+	p.order.Remove(e.elem)
+	e.elem = nil
+}
+
+func (p *lruPolicy) victim() *entry {
+	back := p.order.Back()
+	if back == nil {
+		return nil
+	}
+	return back.Value.(*entry)
+}
+
+// lfuPolicy вытесняет редко используемые записи, при равной частоте — давно не использованные.
+type lfuPolicy struct {
+	entries lfuHeap
+	tick    uint64
+}
+
+// add сохраняет накопленную частоту обращений, если запись обновляется.
+func (p *lfuPolicy) add(e *entry) {
+	p.tick++
+	if e.freq == 0 {
+		e.freq = 1
+	}
+	e.tick = p.tick
+	heap.Push(&p.entries, e)
+}
+
+func (p *lfuPolicy) touch(e *entry) {
+	p.tick++
+	e.freq++
+	e.tick = p.tick
+	heap.Fix(&p.entries, e.index)
+}
+
+func (p *lfuPolicy) remove(e *entry) {
+	heap.Remove(&p.entries, e.index)
+}
+
+func (p *lfuPolicy) victim() *entry {
+	if len(p.entries) == 0 {
+		return nil
+	}
+	return p.entries[0]
+}
+
+type lfuHeap []*entry
+
+func (h lfuHeap) Len() int { return len(h) }
+
+func (h lfuHeap) Less(i, j int) bool {
+	if h[i].freq != h[j].freq {
+		return h[i].freq < h[j].freq
+	}
+	return h[i].tick < h[j].tick
+}
+
+func (h lfuHeap) Swap(i, j int) {
+	h[i], h[j] = h[j], h[i]
+	h[i].index = i
+	h[j].index = j
+}
+
+func (h *lfuHeap) Push(x any) {
+	e := x.(*entry)
+	e.index = len(*h)
+	*h = append(*h, e)
+}
+
+func (h *lfuHeap) Pop() any {
+	old := *h
+	n := len(old)
+	e := old[n-1]
+	old[n-1] = nil
+	e.index = -1
+	*h = old[:n-1]
+	return e
+}
@@ -0,0 +1,32 @@
+package inmem
+
+import (
+	"unsafe"
+
+	"github.com/sunr3d/order-stream-processor/models"
+)
+
+var (
+	orderOverhead = int64(unsafe.Sizeof(models.Order{})) + int64(unsafe.Sizeof(entry{}))
+	itemOverhead  = int64(unsafe.Sizeof(models.Item{}))
+)
+
+// approxSize приблизительно оценивает объем памяти, занимаемый заказом в кэше.
+func approxSize(key string, o *models.Order) int64 {
+	size := orderOverhead + int64(len(key))
+
+	size += int64(len(o.OrderUID) + len(o.TrackNumber) + len(o.Entry) + len(o.Locale) +
+		len(o.InternalSignature) + len(o.CustomerID) + len(o.DeliveryService) + len(o.ShardKey) + len(o.OofShard))
+
+	d := o.Delivery
+	size += int64(len(d.Name) + len(d.Phone) + len(d.Zip) + len(d.City) + len(d.Address) + len(d.Region) + len(d.Email))
+
+	p := o.Payment
+	size += int64(len(p.Transaction) + len(p.RequestID) + len(p.Currency) + len(p.Provider) + len(p.Bank))
+
+	for _, it := range o.Items {
+		size += itemOverhead + int64(len(it.TrackNumber)+len(it.RID)+len(it.Name)+len(it.Size)+len(it.Brand))
+	}
+
+	return size
+}
@@ -0,0 +1,197 @@
+package kafka
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/IBM/sarama"
+	"go.uber.org/zap"
+
+	"github.com/sunr3d/order-stream-processor/internal/interfaces/infra"
+	"github.com/sunr3d/order-stream-processor/internal/metrics"
+)
+
+// defaultBatchTimeout используется, если KAFKA_BATCH_TIMEOUT не задан.
+const defaultBatchTimeout = 100 * time.Millisecond
+
+// consumeBatches копит сообщения партиции до KAFKA_BATCH_SIZE штук или KAFKA_BATCH_TIMEOUT
+// с момента первого сообщения пачки и передает их обработчику одним вызовом.
+// Оффсет последнего сообщения пачки коммитится только после того, как у каждого сообщения
+// есть итог: оно сохранено или записано в DLQ.
+func (b *kafkaBroker) consumeBatches(session sarama.ConsumerGroupSession, claim sarama.ConsumerGroupClaim, handler infra.BatchHandler) error {
+	logger := b.logger.With(
+		zap.String("op", "kafka.consumeBatches"),
+		zap.String("topic", claim.Topic()),
+		zap.Int32("partition", claim.Partition()),
+	)
+
+	ctx := session.Context()
+	size := b.config.BatchSize
+	timeout := b.config.BatchTimeout
+	if timeout <= 0 {
+		timeout = defaultBatchTimeout
+	}
+
+	batch := make([]*sarama.ConsumerMessage, 0, size)
+	timer := time.NewTimer(timeout)
+	timer.Stop()
+	defer timer.Stop()
+	var deadline <-chan time.Time
+
+	flush := func() error {
+		timer.Stop()
+		deadline = nil
+		if len(batch) == 0 {
+			return nil
+		}
+
+		last := batch[len(batch)-1]
+		err := b.processBatch(ctx, handler, batch)
+		batch = batch[:0]
+
+		// Сессия завершается (ребалансировка или остановка): оффсет не коммитим,
+		// пачка будет прочитана повторно
+		if ctx.Err() != nil {
+			logger.Info("обработка пачки прервана завершением сессии")
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		session.MarkMessage(last, "")
+		return nil
+	}
+
+	for {
+		select {
+		case msg, ok := <-claim.Messages():
+			if !ok {
+				return flush()
+			}
+
+			logger.Info("получено сообщение из Kafka",
+				zap.Int64("offset", msg.Offset),
+				zap.String("key (order_uid)", string(msg.Key)),
+			)
+			metrics.KafkaMessageConsumed(msg.Topic, msg.Partition, msg.Offset, claim.HighWaterMarkOffset())
+
+			batch = append(batch, msg)
+			if len(batch) == 1 {
+				timer.Reset(timeout)
+				deadline = timer.C
+			}
+			if len(batch) >= size {
+				if err := flush(); err != nil {
+					return err
+				}
+			}
+		case <-deadline:
+			if err := flush(); err != nil {
+				return err
+			}
+		case <-ctx.Done():
+			return nil
+		}
+	}
+}
+
+// processBatch вызывает обработчик пачки и повторяет только сообщения с устранимыми ошибками
+// (с экспоненциальной паузой, как processMessage). Сообщения, так и не обработанные успешно,
+// отправляются в DLQ; ошибка возвращается, только если DLQ недоступна.
+func (b *kafkaBroker) processBatch(ctx context.Context, handler infra.BatchHandler, msgs []*sarama.ConsumerMessage) error {
+	logger := b.logger.With(
+		zap.String("op", "kafka.processBatch"),
+		zap.String("topic", msgs[0].Topic),
+		zap.Int32("partition", msgs[0].Partition),
+		zap.Int64("first_offset", msgs[0].Offset),
+		zap.Int("count", len(msgs)),
+	)
+
+	maxAttempts := max(b.config.MaxRetries, 1)
+	start := time.Now()
+
+	errs := make([]error, len(msgs))
+	attempts := make([]int, len(msgs))
+	pending := make([]int, len(msgs))
+	for i := range pending {
+		pending[i] = i
+	}
+
+	for attempt := 1; ; attempt++ {
+		values := make([][]byte, len(pending))
+		for k, i := range pending {
+			values[k] = msgs[i].Value
+		}
+
+		results := handler(ctx, values)
+		if len(results) != len(values) {
+			err := fmt.Errorf("обработчик вернул %d результатов на %d сообщений", len(results), len(values))
+			results = make([]error, len(values))
+			for k := range results {
+				results[k] = err
+			}
+		}
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		var retry []int
+		for k, i := range pending {
+			errs[i], attempts[i] = results[k], attempt
+			if results[k] != nil && !errors.Is(results[k], infra.ErrPermanent) {
+				retry = append(retry, i)
+			}
+		}
+
+		if len(retry) == 0 {
+			break
+		}
+		logger.Error("ошибка при обработке сообщений пачки",
+			zap.Int("attempt", attempt),
+			zap.Int("max_retries", maxAttempts),
+			zap.Int("failed", len(retry)),
+			zap.Error(errs[retry[0]]),
+		)
+		if attempt == maxAttempts {
+			logger.Warn("превышено количество попыток обработки сообщений пачки",
+				zap.Int("max_retries", maxAttempts),
+			)
+			break
+		}
+
+		delay := b.retry.delay(attempt)
+		logger.Info("повторная попытка обработки сообщений пачки",
+			zap.Int("next_attempt", attempt+1),
+			zap.Duration("delay", delay),
+		)
+		if err := sleep(ctx, delay); err != nil {
+			return err
+		}
+		pending = retry
+	}
+
+	elapsed := time.Since(start)
+	failed := 0
+	for i, msg := range msgs {
+		metrics.KafkaMessageProcessed(msg.Topic, msg.Partition, elapsed, errs[i])
+		if errs[i] == nil {
+			continue
+		}
+
+		failed++
+		// Без успешной записи в DLQ оффсет пачки не коммитим, пачка будет прочитана повторно
+		if err := b.sendToDLQ(msg, attempts[i], errs[i]); err != nil {
+			logger.Error("не удалось отправить сообщение в DLQ",
+				zap.Int64("offset", msg.Offset),
+				zap.Error(err),
+			)
+			return fmt.Errorf("sendToDLQ: %w", err)
+		}
+	}
+
+	logger.Info("пачка сообщений обработана", zap.Int("failed", failed))
+	return nil
+}
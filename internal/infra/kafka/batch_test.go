@@ -0,0 +1,183 @@
+package kafka
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/IBM/sarama"
+	"github.com/IBM/sarama/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/sunr3d/order-stream-processor/internal/config"
+	"github.com/sunr3d/order-stream-processor/internal/interfaces/infra"
+)
+
+func newBatchTestBroker(size int, handler infra.BatchHandler) *kafkaBroker {
+	return &kafkaBroker{
+		subs:  map[string]infra.Subscription{"orders": {Topic: "orders", BatchHandler: handler}},
+		retry: newRetryPolicy(config.KafkaRetryConfig{}),
+		config: config.KafkaConfig{
+			MaxRetries:   3,
+			BatchSize:    size,
+			BatchTimeout: time.Hour,
+		},
+		logger: zap.NewNop(),
+	}
+}
+
+// batchRecorder запоминает значения сообщений каждого вызова обработчика.
+type batchRecorder struct {
+	mu    sync.Mutex
+	calls [][]string
+}
+
+func (r *batchRecorder) record(msgs [][]byte) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	call := make([]string, len(msgs))
+	for i, msg := range msgs {
+		call[i] = string(msg)
+	}
+	r.calls = append(r.calls, call)
+}
+
+func TestConsumeBatches_FlushBySize(t *testing.T) {
+	rec := &batchRecorder{}
+	handler := func(ctx context.Context, msgs [][]byte) []error {
+		rec.record(msgs)
+		return make([]error, len(msgs))
+	}
+
+	broker := newBatchTestBroker(2, handler)
+	session := &fakeSession{ctx: context.Background()}
+
+	err := broker.ConsumeClaim(session, newTestClaim("a", "b", "c", "d", "e"))
+
+	require.NoError(t, err)
+	assert.Equal(t, [][]string{{"a", "b"}, {"c", "d"}, {"e"}}, rec.calls)
+	assert.Equal(t, []int64{1, 3, 4}, session.marked)
+}
+
+func TestConsumeBatches_FlushByTimeout(t *testing.T) {
+	rec := &batchRecorder{}
+	handler := func(ctx context.Context, msgs [][]byte) []error {
+		rec.record(msgs)
+		return make([]error, len(msgs))
+	}
+
+	broker := newBatchTestBroker(10, handler)
+	broker.config.BatchTimeout = 10 * time.Millisecond
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	session := &fakeSession{ctx: ctx}
+	claim := &fakeClaim{messages: make(chan *sarama.ConsumerMessage, 1)}
+	claim.messages <- &sarama.ConsumerMessage{Topic: "orders", Offset: 7, Value: []byte("a")}
+
+	result := make(chan error, 1)
+	go func() { result <- broker.ConsumeClaim(session, claim) }()
+
+	assert.Eventually(t, func() bool { return session.lastMarked() == 7 }, time.Second, 5*time.Millisecond)
+	cancel()
+	require.NoError(t, <-result)
+	assert.Equal(t, [][]string{{"a"}}, rec.calls)
+}
+
+func TestConsumeBatches_RetriesOnlyTransient(t *testing.T) {
+	rec := &batchRecorder{}
+	var failedOnce bool
+	handler := func(ctx context.Context, msgs [][]byte) []error {
+		rec.record(msgs)
+		results := make([]error, len(msgs))
+		for i, msg := range msgs {
+			switch string(msg) {
+			case "perm":
+				results[i] = fmt.Errorf("%w: дубликат", infra.ErrPermanent)
+			case "flaky":
+				if !failedOnce {
+					failedOnce = true
+					results[i] = errors.New("БД недоступна")
+				}
+			}
+		}
+		return results
+	}
+
+	broker := newBatchTestBroker(3, handler)
+	session := &fakeSession{ctx: context.Background()}
+
+	err := broker.ConsumeClaim(session, newTestClaim("ok", "perm", "flaky"))
+
+	require.NoError(t, err)
+	assert.Equal(t, [][]string{{"ok", "perm", "flaky"}, {"flaky"}}, rec.calls)
+	assert.Equal(t, []int64{2}, session.marked)
+}
+
+func TestConsumeBatches_DLQFailureKeepsOffset(t *testing.T) {
+	handler := func(ctx context.Context, msgs [][]byte) []error {
+		results := make([]error, len(msgs))
+		results[1] = fmt.Errorf("%w: невалидный заказ", infra.ErrPermanent)
+		return results
+	}
+
+	producer := mocks.NewSyncProducer(t, nil)
+	producer.ExpectSendMessageAndFail(errors.New("брокер недоступен"))
+
+	broker := newBatchTestBroker(2, handler)
+	broker.producer = producer
+	broker.config.DLQTopic = "orders.dlq"
+	session := &fakeSession{ctx: context.Background()}
+
+	err := broker.ConsumeClaim(session, newTestClaim("a", "b"))
+
+	require.Error(t, err)
+	assert.Empty(t, session.marked, "без записи в DLQ оффсет пачки не коммитится")
+}
+
+func TestConsumeBatches_SessionEnd(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	handler := func(hctx context.Context, msgs [][]byte) []error {
+		cancel()
+		results := make([]error, len(msgs))
+		for i := range results {
+			results[i] = hctx.Err()
+		}
+		return results
+	}
+
+	broker := newBatchTestBroker(2, handler)
+	session := &fakeSession{ctx: ctx}
+
+	err := broker.ConsumeClaim(session, newTestClaim("a", "b", "c"))
+
+	require.NoError(t, err)
+	assert.Empty(t, session.marked, "прерванная пачка не коммитится")
+}
+
+func TestConsumeClaim_BatchDisabledUsesHandler(t *testing.T) {
+	var handled []string
+	broker := newBatchTestBroker(1, func(ctx context.Context, msgs [][]byte) []error {
+		t.Fatal("пакетный обработчик не должен вызываться при KAFKA_BATCH_SIZE <= 1")
+		return nil
+	})
+	broker.subs["orders"] = infra.Subscription{
+		Topic: "orders",
+		Handler: func(ctx context.Context, msg []byte) error {
+			handled = append(handled, string(msg))
+			return nil
+		},
+		BatchHandler: broker.subs["orders"].BatchHandler,
+	}
+	session := &fakeSession{ctx: context.Background()}
+
+	err := broker.ConsumeClaim(session, newTestClaim("a", "b"))
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{"a", "b"}, handled)
+}
@@ -0,0 +1,215 @@
+package kafka
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/IBM/sarama"
+	"go.uber.org/zap"
+
+	"github.com/sunr3d/order-stream-processor/internal/metrics"
+	"github.com/sunr3d/order-stream-processor/models"
+)
+
+// Заголовки, которыми сопровождается сообщение в DLQ
+const (
+	headerDLQTopic     = "dlq-original-topic"
+	headerDLQPartition = "dlq-original-partition"
+	headerDLQOffset    = "dlq-original-offset"
+	headerDLQKey       = "dlq-original-key"
+	headerDLQAttempts  = "dlq-attempts"
+	headerDLQError     = "dlq-error"
+	// headerDLQErrorDetails — нарушения валидации заказа в JSON, если сообщение отклонено валидацией
+	headerDLQErrorDetails = "dlq-error-details"
+	headerDLQFailedAt     = "dlq-failed-at"
+	headerDLQReplayed     = "dlq-replayed-at"
+)
+
+const dlqReplayGroupSuffix = "-dlq-replay"
+
+func newDLQMessage(dlqTopic string, msg *sarama.ConsumerMessage, attempts int, cause error) *sarama.ProducerMessage {
+	headers := []sarama.RecordHeader{
+		{Key: []byte(headerDLQTopic), Value: []byte(msg.Topic)},
+		{Key: []byte(headerDLQPartition), Value: []byte(strconv.FormatInt(int64(msg.Partition), 10))},
+		{Key: []byte(headerDLQOffset), Value: []byte(strconv.FormatInt(msg.Offset, 10))},
+		{Key: []byte(headerDLQKey), Value: msg.Key},
+		{Key: []byte(headerDLQAttempts), Value: []byte(strconv.Itoa(attempts))},
+		{Key: []byte(headerDLQError), Value: []byte(cause.Error())},
+		{Key: []byte(headerDLQFailedAt), Value: []byte(time.Now().UTC().Format(time.RFC3339Nano))},
+	}
+	var verr *models.ValidationError
+	if errors.As(cause, &verr) {
+		if details, err := json.Marshal(verr.Violations); err == nil {
+			headers = append(headers, sarama.RecordHeader{Key: []byte(headerDLQErrorDetails), Value: details})
+		}
+	}
+
+	pm := &sarama.ProducerMessage{
+		Topic:   dlqTopic,
+		Value:   sarama.ByteEncoder(msg.Value),
+		Headers: headers,
+	}
+	if msg.Key != nil {
+		pm.Key = sarama.ByteEncoder(msg.Key)
+	}
+
+	return pm
+}
+
+func newReplayMessage(defaultTopic string, msg *sarama.ConsumerMessage) *sarama.ProducerMessage {
+	topic := defaultTopic
+	for _, h := range msg.Headers {
+		if h != nil && string(h.Key) == headerDLQTopic && len(h.Value) > 0 {
+			topic = string(h.Value)
+		}
+	}
+
+	pm := &sarama.ProducerMessage{
+		Topic: topic,
+		Value: sarama.ByteEncoder(msg.Value),
+		Headers: []sarama.RecordHeader{
+			{Key: []byte(headerDLQReplayed), Value: []byte(time.Now().UTC().Format(time.RFC3339Nano))},
+		},
+	}
+	if msg.Key != nil {
+		pm.Key = sarama.ByteEncoder(msg.Key)
+	}
+
+	return pm
+}
+
+func (b *kafkaBroker) sendToDLQ(msg *sarama.ConsumerMessage, attempts int, cause error) error {
+	logger := b.logger.With(
+		zap.String("op", "kafka.sendToDLQ"),
+		zap.String("topic", msg.Topic),
+		zap.Int32("partition", msg.Partition),
+		zap.Int64("offset", msg.Offset),
+		zap.String("key (order_uid)", string(msg.Key)),
+	)
+
+	if b.config.DLQTopic == "" {
+		logger.Warn("DLQ не настроена, сообщение пропущено", zap.Error(cause))
+		metrics.KafkaMessageSkipped(msg.Topic, msg.Partition)
+		return nil
+	}
+
+	partition, offset, err := b.producer.SendMessage(newDLQMessage(b.config.DLQTopic, msg, attempts, cause))
+	if err != nil {
+		return fmt.Errorf("producer.SendMessage: %w", err)
+	}
+
+	logger.Warn("сообщение отправлено в DLQ",
+		zap.String("dlq_topic", b.config.DLQTopic),
+		zap.Int32("dlq_partition", partition),
+		zap.Int64("dlq_offset", offset),
+		zap.Int("attempts", attempts),
+		zap.Error(cause),
+	)
+	return nil
+}
+
+// ReplayDLQ переотправляет накопленные в DLQ сообщения в исходные топики.
+// Прогресс фиксируется отдельной consumer group, поэтому повторный запуск
+// не дублирует уже переотправленные сообщения.
+func (b *kafkaBroker) ReplayDLQ(ctx context.Context) (int, error) {
+	logger := b.logger.With(
+		zap.String("op", "kafka.ReplayDLQ"),
+		zap.String("dlq_topic", b.config.DLQTopic),
+	)
+
+	if b.config.DLQTopic == "" {
+		return 0, fmt.Errorf("DLQ топик не настроен")
+	}
+
+	partitions, err := b.client.Partitions(b.config.DLQTopic)
+	if err != nil {
+		return 0, fmt.Errorf("client.Partitions: %w", err)
+	}
+
+	consumer, err := sarama.NewConsumerFromClient(b.client)
+	if err != nil {
+		return 0, fmt.Errorf("sarama.NewConsumerFromClient: %w", err)
+	}
+	defer consumer.Close()
+
+	offsets, err := sarama.NewOffsetManagerFromClient(b.config.GroupID+dlqReplayGroupSuffix, b.client)
+	if err != nil {
+		return 0, fmt.Errorf("sarama.NewOffsetManagerFromClient: %w", err)
+	}
+	defer offsets.Close()
+
+	logger.Info("переотправка сообщений из DLQ...", zap.Int("partitions", len(partitions)))
+
+	replayed := 0
+	for _, partition := range partitions {
+		n, err := b.replayPartition(ctx, consumer, offsets, partition)
+		replayed += n
+		if err != nil {
+			offsets.Commit()
+			return replayed, fmt.Errorf("replayPartition(%d): %w", partition, err)
+		}
+	}
+	offsets.Commit()
+
+	logger.Info("переотправка сообщений из DLQ завершена", zap.Int("replayed", replayed))
+	return replayed, nil
+}
+
+func (b *kafkaBroker) replayPartition(ctx context.Context, consumer sarama.Consumer, offsets sarama.OffsetManager, partition int32) (int, error) {
+	topic := b.config.DLQTopic
+
+	oldest, err := b.client.GetOffset(topic, partition, sarama.OffsetOldest)
+	if err != nil {
+		return 0, fmt.Errorf("client.GetOffset(oldest): %w", err)
+	}
+	newest, err := b.client.GetOffset(topic, partition, sarama.OffsetNewest)
+	if err != nil {
+		return 0, fmt.Errorf("client.GetOffset(newest): %w", err)
+	}
+
+	pom, err := offsets.ManagePartition(topic, partition)
+	if err != nil {
+		return 0, fmt.Errorf("offsets.ManagePartition: %w", err)
+	}
+	defer pom.Close()
+
+	start, _ := pom.NextOffset()
+	if start < oldest {
+		start = oldest
+	}
+	if start >= newest {
+		return 0, nil
+	}
+
+	pc, err := consumer.ConsumePartition(topic, partition, start)
+	if err != nil {
+		return 0, fmt.Errorf("consumer.ConsumePartition: %w", err)
+	}
+	defer pc.Close()
+
+	replayed := 0
+	for {
+		select {
+		case <-ctx.Done():
+			return replayed, ctx.Err()
+		case msg, ok := <-pc.Messages():
+			if !ok {
+				return replayed, fmt.Errorf("partition consumer закрыт")
+			}
+
+			if _, _, err := b.producer.SendMessage(newReplayMessage(b.config.Topic, msg)); err != nil {
+				return replayed, fmt.Errorf("producer.SendMessage: %w", err)
+			}
+			pom.MarkOffset(msg.Offset+1, "")
+			replayed++
+
+			if msg.Offset+1 >= newest {
+				return replayed, nil
+			}
+		}
+	}
+}
@@ -0,0 +1,87 @@
+package kafka
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/IBM/sarama"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/sunr3d/order-stream-processor/internal/interfaces/infra"
+	"github.com/sunr3d/order-stream-processor/models"
+)
+
+func headersMap(headers []sarama.RecordHeader) map[string]string {
+	m := make(map[string]string, len(headers))
+	for _, h := range headers {
+		m[string(h.Key)] = string(h.Value)
+	}
+	return m
+}
+
+func TestNewDLQMessage(t *testing.T) {
+	msg := &sarama.ConsumerMessage{
+		Topic:     "orders",
+		Partition: 2,
+		Offset:    42,
+		Key:       []byte("test-123"),
+		Value:     []byte(`{"order_uid":"test-123"}`),
+	}
+
+	pm := newDLQMessage("orders.dlq", msg, 3, errors.New("ошибка БД"))
+
+	assert.Equal(t, "orders.dlq", pm.Topic)
+	assert.Equal(t, sarama.ByteEncoder(msg.Value), pm.Value)
+	assert.Equal(t, sarama.ByteEncoder(msg.Key), pm.Key)
+
+	h := headersMap(pm.Headers)
+	assert.Equal(t, "orders", h[headerDLQTopic])
+	assert.Equal(t, "2", h[headerDLQPartition])
+	assert.Equal(t, "42", h[headerDLQOffset])
+	assert.Equal(t, "test-123", h[headerDLQKey])
+	assert.Equal(t, "3", h[headerDLQAttempts])
+	assert.Equal(t, "ошибка БД", h[headerDLQError])
+	assert.NotEmpty(t, h[headerDLQFailedAt])
+	assert.NotContains(t, h, headerDLQErrorDetails)
+}
+
+func TestNewDLQMessage_ValidationDetails(t *testing.T) {
+	msg := &sarama.ConsumerMessage{Topic: "orders", Value: []byte(`{}`)}
+	cause := fmt.Errorf("%w: ошибка валидации заказа из Kafka: %w", infra.ErrPermanent, &models.ValidationError{
+		Violations: []models.Violation{{Path: "/items/2/price", Code: "must_be_positive", Value: 0, Message: "должно быть больше 0"}},
+	})
+
+	pm := newDLQMessage("orders.dlq", msg, 1, cause)
+
+	assert.JSONEq(t,
+		`[{"path":"/items/2/price","code":"must_be_positive","value":0,"message":"должно быть больше 0"}]`,
+		headersMap(pm.Headers)[headerDLQErrorDetails],
+	)
+}
+
+func TestNewReplayMessage_OriginalTopic(t *testing.T) {
+	msg := &sarama.ConsumerMessage{
+		Topic: "orders.dlq",
+		Key:   []byte("test-123"),
+		Value: []byte(`{}`),
+		Headers: []*sarama.RecordHeader{
+			{Key: []byte(headerDLQTopic), Value: []byte("order-status")},
+		},
+	}
+
+	pm := newReplayMessage("orders", msg)
+
+	assert.Equal(t, "order-status", pm.Topic)
+	assert.Equal(t, sarama.ByteEncoder(msg.Key), pm.Key)
+	assert.Contains(t, headersMap(pm.Headers), headerDLQReplayed)
+}
+
+func TestNewReplayMessage_DefaultTopic(t *testing.T) {
+	msg := &sarama.ConsumerMessage{Topic: "orders.dlq", Value: []byte(`{}`)}
+
+	pm := newReplayMessage("orders", msg)
+
+	assert.Equal(t, "orders", pm.Topic)
+	assert.Nil(t, pm.Key)
+}
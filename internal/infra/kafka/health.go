@@ -0,0 +1,53 @@
+package kafka
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// HealthCheck проверяет, что клиент не закрыт и хотя бы один брокер доступен.
+// Если живых соединений нет, выполняется обновление метаданных, которое переподключается к кластеру.
+func (b *kafkaBroker) HealthCheck(ctx context.Context) error {
+	if b.client.Closed() {
+		return errors.New("клиент Kafka закрыт")
+	}
+
+	for _, broker := range b.client.Brokers() {
+		if connected, _ := broker.Connected(); connected {
+			return nil
+		}
+	}
+
+	// RefreshMetadata не принимает контекст, поэтому ожидание ограничивается здесь
+	done := make(chan error, 1)
+	go func() {
+		done <- b.client.RefreshMetadata()
+	}()
+
+	select {
+	case err := <-done:
+		if err != nil {
+			return fmt.Errorf("client.RefreshMetadata: %w", err)
+		}
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("нет соединения с брокерами Kafka: %w", ctx.Err())
+	}
+}
+
+// ConsumerHealthCheck проверяет, что консьюмер состоит в consumer group (сессия группы активна).
+// Проверка не проходит, только если консьюмер вне группы дольше KAFKA_CONSUMER_GRACE: ребалансировка
+// затрагивает все реплики сразу и не должна одновременно снимать их с балансировки.
+func (b *kafkaBroker) ConsumerHealthCheck(ctx context.Context) error {
+	if b.member.Load() {
+		return nil
+	}
+
+	left := time.Since(time.Unix(0, b.leftAt.Load()))
+	if left < b.config.ConsumerGrace {
+		return nil
+	}
+	return fmt.Errorf("консьюмер не состоит в группе %s уже %s", b.config.GroupID, left.Round(time.Second))
+}
@@ -2,29 +2,41 @@ package kafka
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"strings"
+	"sync/atomic"
+	"time"
 
 	"github.com/IBM/sarama"
 	"go.uber.org/zap"
 
 	"github.com/sunr3d/order-stream-processor/internal/config"
 	"github.com/sunr3d/order-stream-processor/internal/interfaces/infra"
+	"github.com/sunr3d/order-stream-processor/internal/metrics"
 )
 
 var _ infra.Broker = (*kafkaBroker)(nil)
+var _ infra.HealthChecker = (*kafkaBroker)(nil)
 
 type kafkaBroker struct {
 	client    sarama.Client
 	consumers sarama.ConsumerGroup
-	handler   func(context.Context, []byte) error
-	config    config.KafkaConfig
-	logger    *zap.Logger
+	producer  sarama.SyncProducer
+	subs      map[string]infra.Subscription
+	retry     retryPolicy
+	member    atomic.Bool
+	// leftAt — момент (UnixNano), с которого консьюмер не состоит в группе
+	leftAt atomic.Int64
+	config config.KafkaConfig
+	logger *zap.Logger
 }
 
 func New(cfg config.KafkaConfig, logger *zap.Logger) (infra.Broker, error) {
 	config := sarama.NewConfig()
 	config.Consumer.Offsets.Initial = sarama.OffsetOldest
+	config.Producer.RequiredAcks = sarama.WaitForAll
+	config.Producer.Return.Successes = true
 
 	client, err := sarama.NewClient(cfg.Brokers, config)
 	if err != nil {
@@ -38,16 +50,32 @@ func New(cfg config.KafkaConfig, logger *zap.Logger) (infra.Broker, error) {
 		return nil, fmt.Errorf("не удалось создать consumer group: %w", err)
 	}
 
-	return &kafkaBroker{
+	producer, err := sarama.NewSyncProducerFromClient(client)
+	if err != nil {
+		consumers.Close()
+		client.Close()
+		return nil, fmt.Errorf("не удалось создать producer: %w", err)
+	}
+
+	b := &kafkaBroker{
 		client:    client,
 		consumers: consumers,
+		producer:  producer,
+		retry:     newRetryPolicy(cfg.Retry),
 		config:    cfg,
 		logger:    logger,
-	}, nil
+	}
+	b.leftAt.Store(time.Now().UnixNano())
+	return b, nil
 }
 
-func (b *kafkaBroker) StartConsumer(ctx context.Context, handler func(context.Context, []byte) error) error {
-	b.handler = handler
+func (b *kafkaBroker) StartConsumer(ctx context.Context, subs ...infra.Subscription) error {
+	b.subs = make(map[string]infra.Subscription, len(subs))
+	topics := make([]string, 0, len(subs))
+	for _, sub := range subs {
+		b.subs[sub.Topic] = sub
+		topics = append(topics, sub.Topic)
+	}
 
 	logger := b.logger.With(
 		zap.String("op", "kafka.Start"),
@@ -55,12 +83,12 @@ func (b *kafkaBroker) StartConsumer(ctx context.Context, handler func(context.Co
 
 	logger.Info("запуск Kafka consumers group",
 		zap.String("group_id", b.config.GroupID),
-		zap.String("topic", b.config.Topic),
+		zap.Strings("topics", topics),
 		zap.String("brokers", strings.Join(b.config.Brokers, ", ")),
 	)
 
 	for {
-		err := b.consumers.Consume(ctx, []string{b.config.Topic}, b)
+		err := b.consumers.Consume(ctx, topics, b)
 		if err != nil {
 			logger.Error("ошибка при чтении сообщений из Kafka", zap.Error(err))
 		}
@@ -68,7 +96,7 @@ func (b *kafkaBroker) StartConsumer(ctx context.Context, handler func(context.Co
 		if ctx.Err() != nil {
 			logger.Info("остановка Kafka consumers group по причине контекста",
 				zap.String("group_id", b.config.GroupID),
-				zap.String("topic", b.config.Topic),
+				zap.Strings("topics", topics),
 				zap.String("brokers", strings.Join(b.config.Brokers, ", ")),
 			)
 			break
@@ -90,71 +118,164 @@ func (b *kafkaBroker) Stop() error {
 	)
 
 	b.consumers.Close()
+	b.producer.Close()
 	return b.client.Close()
 }
 
+// ConsumeClaim читает сообщения партиции и раздает их пулу воркеров (KAFKA_WORKERS).
+// Оффсет коммитится только до последнего сообщения, перед которым все сообщения
+// партиции уже обработаны, поэтому при падении ни одно незавершенное сообщение не теряется.
+// Если у подписки есть BatchHandler и задан KAFKA_BATCH_SIZE, сообщения обрабатываются пачками.
 func (b *kafkaBroker) ConsumeClaim(session sarama.ConsumerGroupSession, claim sarama.ConsumerGroupClaim) error {
 	logger := b.logger.With(
 		zap.String("op", "kafka.ConsumeClaim"),
+		zap.String("topic", claim.Topic()),
+		zap.Int32("partition", claim.Partition()),
 	)
 
-	for msg := range claim.Messages() {
-		logger.Info("получено сообщение из Kafka",
-			zap.Int32("partition", msg.Partition),
-			zap.Int64("offset", msg.Offset),
-			zap.String("key (order_uid)", string(msg.Key)),
-		)
+	sub, ok := b.subs[claim.Topic()]
+	if !ok {
+		return fmt.Errorf("не найден обработчик для топика %s", claim.Topic())
+	}
+	if sub.BatchHandler != nil && b.config.BatchSize > 1 {
+		return b.consumeBatches(session, claim, sub.BatchHandler)
+	}
+	handler := sub.Handler
+	if handler == nil {
+		return fmt.Errorf("не найден обработчик для топика %s", claim.Topic())
+	}
+
+	ctx, cancel := context.WithCancel(session.Context())
+	defer cancel()
+
+	workers := max(b.config.Workers, 1)
+	maxInFlight := workers * inFlightPerWorker
+	pool := b.newWorkerPool(ctx, handler, workers)
+	tracker := newOffsetTracker()
+	inFlight := 0
 
-		var processingErr error
-		for attempt := 1; attempt <= b.config.MaxRetries; attempt++ {
-			if err := b.handler(session.Context(), msg.Value); err != nil {
-				logger.Error("ошибка при обработке сообщения",
-					zap.Int32("partition", msg.Partition),
-					zap.Int64("offset", msg.Offset),
-					zap.String("key (order_uid)", string(msg.Key)),
-					zap.Int("attempt", attempt),
-					zap.Int("max_retries", b.config.MaxRetries),
-					zap.Error(err),
-				)
-
-				processingErr = err
-
-				if attempt == b.config.MaxRetries {
-					session.MarkMessage(msg, "")
-					logger.Warn("превышено количество попыток обработки сообщения",
-						zap.Int32("partition", msg.Partition),
-						zap.Int64("offset", msg.Offset),
-						zap.String("key (order_uid)", string(msg.Key)),
-					)
-				}
-
-				continue
-			} else {
-
-				processingErr = nil
-				break
+	var failure error
+	handle := func(res workerResult) {
+		inFlight--
+		switch {
+		case res.err != nil:
+			if failure == nil {
+				failure = res.err
+				// Остальные сообщения дорабатывать незачем: оффсет дальше этого сообщения не сдвинется
+				cancel()
+			}
+		case res.interrupted:
+		default:
+			if last := tracker.complete(res.msg.Offset); last != nil {
+				session.MarkMessage(last, "")
 			}
 		}
+	}
 
-		session.MarkMessage(msg, "")
+consume:
+	for failure == nil {
+		select {
+		case msg, ok := <-claim.Messages():
+			if !ok {
+				break consume
+			}
 
-		if processingErr != nil {
-			logger.Info("сообщение пропущено",
-				zap.Int32("partition", msg.Partition),
-				zap.Int64("offset", msg.Offset),
-				zap.Error(processingErr),
-			)
-		} else {
-			logger.Info("сообщение обработано успешно",
-				zap.Int32("partition", msg.Partition),
+			logger.Info("получено сообщение из Kafka",
 				zap.Int64("offset", msg.Offset),
 				zap.String("key (order_uid)", string(msg.Key)),
 			)
+			metrics.KafkaMessageConsumed(msg.Topic, msg.Partition, msg.Offset, claim.HighWaterMarkOffset())
+
+			// Сообщения без результата после отмены контекста (прерванные) остаются в трекере,
+			// поэтому ждем только пока есть сообщения в работе
+			for tracker.len() >= maxInFlight && inFlight > 0 && failure == nil {
+				handle(<-pool.results)
+			}
+			if failure != nil || ctx.Err() != nil {
+				break consume
+			}
+
+			tracker.add(msg)
+			inFlight++
+			pool.submit(msg)
+		case res := <-pool.results:
+			handle(res)
+		case <-ctx.Done():
+			break consume
 		}
 	}
 
+	// Дожидаемся сообщений, уже отданных воркерам, чтобы закоммитить все, что успело обработаться
+	pool.close()
+	for inFlight > 0 {
+		handle(<-pool.results)
+	}
+	pool.wg.Wait()
+
+	return failure
+}
+
+// processMessage вызывает обработчик с повторами и экспоненциальной паузой между ними.
+// Неустранимые ошибки (infra.ErrPermanent) не повторяются.
+func (b *kafkaBroker) processMessage(ctx context.Context, handler infra.MessageHandler, msg *sarama.ConsumerMessage) (int, error) {
+	logger := b.logger.With(
+		zap.String("op", "kafka.processMessage"),
+		zap.String("topic", msg.Topic),
+		zap.Int32("partition", msg.Partition),
+		zap.Int64("offset", msg.Offset),
+		zap.String("key (order_uid)", string(msg.Key)),
+	)
+
+	maxAttempts := max(b.config.MaxRetries, 1)
+
+	var err error
+	for attempt := 1; attempt <= maxAttempts; attempt++ {
+		if err = handler(ctx, msg.Value); err == nil {
+			return attempt, nil
+		}
+
+		if errors.Is(err, infra.ErrPermanent) {
+			logger.Error("неустранимая ошибка при обработке сообщения, повторы не выполняются",
+				zap.Int("attempt", attempt),
+				zap.Error(err),
+			)
+			return attempt, err
+		}
+
+		logger.Error("ошибка при обработке сообщения",
+			zap.Int("attempt", attempt),
+			zap.Int("max_retries", maxAttempts),
+			zap.Error(err),
+		)
+
+		if attempt == maxAttempts {
+			break
+		}
+
+		delay := b.retry.delay(attempt)
+		logger.Info("повторная попытка обработки сообщения",
+			zap.Int("next_attempt", attempt+1),
+			zap.Duration("delay", delay),
+		)
+		if ctxErr := sleep(ctx, delay); ctxErr != nil {
+			return attempt, ctxErr
+		}
+	}
+
+	logger.Warn("превышено количество попыток обработки сообщения",
+		zap.Int("max_retries", maxAttempts),
+	)
+	return maxAttempts, err
+}
+
+// Setup и Cleanup отмечают участие в consumer group для readiness-проверки.
+func (b *kafkaBroker) Setup(sarama.ConsumerGroupSession) error {
+	b.member.Store(true)
 	return nil
 }
 
-func (b *kafkaBroker) Setup(sarama.ConsumerGroupSession) error   { return nil }
-func (b *kafkaBroker) Cleanup(sarama.ConsumerGroupSession) error { return nil }
+func (b *kafkaBroker) Cleanup(sarama.ConsumerGroupSession) error {
+	b.leftAt.Store(time.Now().UnixNano())
+	b.member.Store(false)
+	return nil
+}
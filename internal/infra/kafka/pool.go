@@ -0,0 +1,171 @@
+package kafka
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"sync"
+	"time"
+
+	"github.com/IBM/sarama"
+	"go.uber.org/zap"
+
+	"github.com/sunr3d/order-stream-processor/internal/interfaces/infra"
+	"github.com/sunr3d/order-stream-processor/internal/metrics"
+)
+
+// inFlightPerWorker ограничивает число прочитанных, но еще не закоммиченных сообщений партиции
+// (из расчета на одного воркера), чтобы одно зависшее сообщение не копило бесконечную очередь.
+const inFlightPerWorker = 16
+
+// offsetTracker отслеживает сообщения партиции в порядке чтения и определяет
+// последнее сообщение, до которого включительно все сообщения обработаны.
+// Оффсеты в партиции могут идти с пропусками (компакция, транзакционные маркеры),
+// поэтому непрерывность определяется порядком чтения, а не оффсетом+1.
+type offsetTracker struct {
+	pending []*trackedMessage
+	byOff   map[int64]*trackedMessage
+}
+
+type trackedMessage struct {
+	msg  *sarama.ConsumerMessage
+	done bool
+}
+
+func newOffsetTracker() *offsetTracker {
+	return &offsetTracker{byOff: make(map[int64]*trackedMessage)}
+}
+
+func (t *offsetTracker) add(msg *sarama.ConsumerMessage) {
+	tm := &trackedMessage{msg: msg}
+	t.pending = append(t.pending, tm)
+	t.byOff[msg.Offset] = tm
+}
+
+// complete отмечает сообщение обработанным и возвращает последнее сообщение непрерывного
+// обработанного префикса, если он продвинулся, иначе nil.
+func (t *offsetTracker) complete(offset int64) *sarama.ConsumerMessage {
+	tm, ok := t.byOff[offset]
+	if !ok {
+		return nil
+	}
+	tm.done = true
+
+	var last *sarama.ConsumerMessage
+	for len(t.pending) > 0 && t.pending[0].done {
+		last = t.pending[0].msg
+		delete(t.byOff, last.Offset)
+		t.pending[0] = nil
+		t.pending = t.pending[1:]
+	}
+	return last
+}
+
+// len возвращает число сообщений, оффсет которых еще нельзя закоммитить.
+func (t *offsetTracker) len() int {
+	return len(t.pending)
+}
+
+// workerResult — итог обработки сообщения воркером.
+// err — ошибка, из-за которой оффсет коммитить нельзя (например, не удалось записать в DLQ);
+// interrupted — обработка прервана завершением сессии.
+type workerResult struct {
+	msg         *sarama.ConsumerMessage
+	err         error
+	interrupted bool
+}
+
+// workerPool обрабатывает сообщения одной партиции параллельно.
+// Сообщения с одинаковым ключом (order_uid) попадают к одному воркеру и обрабатываются по порядку,
+// сообщения без ключа — к первому воркеру.
+type workerPool struct {
+	jobs    []chan *sarama.ConsumerMessage
+	results chan workerResult
+	wg      sync.WaitGroup
+}
+
+func (b *kafkaBroker) newWorkerPool(ctx context.Context, handler infra.MessageHandler, workers int) *workerPool {
+	workers = max(workers, 1)
+	maxInFlight := workers * inFlightPerWorker
+
+	p := &workerPool{
+		jobs: make([]chan *sarama.ConsumerMessage, workers),
+		// Каждое отправленное воркеру сообщение дает ровно один результат, а сообщений в работе
+		// не больше maxInFlight, поэтому воркеры никогда не блокируются на отправке результата
+		results: make(chan workerResult, maxInFlight),
+	}
+
+	for i := range p.jobs {
+		p.jobs[i] = make(chan *sarama.ConsumerMessage, maxInFlight)
+		p.wg.Add(1)
+		go func(jobs <-chan *sarama.ConsumerMessage) {
+			defer p.wg.Done()
+			for msg := range jobs {
+				p.results <- b.handleMessage(ctx, handler, msg)
+			}
+		}(p.jobs[i])
+	}
+
+	return p
+}
+
+func (p *workerPool) submit(msg *sarama.ConsumerMessage) {
+	p.jobs[workerIndex(msg.Key, len(p.jobs))] <- msg
+}
+
+func workerIndex(key []byte, workers int) int {
+	if len(key) == 0 || workers <= 1 {
+		return 0
+	}
+	h := fnv.New32a()
+	h.Write(key)
+	return int(h.Sum32() % uint32(workers))
+}
+
+// close перестает принимать сообщения; уже отправленные воркерам будут обработаны.
+func (p *workerPool) close() {
+	for _, jobs := range p.jobs {
+		close(jobs)
+	}
+}
+
+// handleMessage обрабатывает сообщение с повторами и при неудаче отправляет его в DLQ.
+func (b *kafkaBroker) handleMessage(ctx context.Context, handler infra.MessageHandler, msg *sarama.ConsumerMessage) workerResult {
+	logger := b.logger.With(
+		zap.String("op", "kafka.handleMessage"),
+		zap.String("topic", msg.Topic),
+		zap.Int32("partition", msg.Partition),
+		zap.Int64("offset", msg.Offset),
+		zap.String("key (order_uid)", string(msg.Key)),
+	)
+
+	if ctx.Err() != nil {
+		return workerResult{msg: msg, interrupted: true}
+	}
+
+	start := time.Now()
+	attempts, processingErr := b.processMessage(ctx, handler, msg)
+
+	// Сессия завершается (ребалансировка или остановка): оффсет не коммитим,
+	// сообщение будет прочитано повторно
+	if ctx.Err() != nil {
+		logger.Info("обработка сообщения прервана завершением сессии")
+		return workerResult{msg: msg, interrupted: true}
+	}
+	metrics.KafkaMessageProcessed(msg.Topic, msg.Partition, time.Since(start), processingErr)
+
+	if processingErr != nil {
+		// Без успешной записи в DLQ оффсет не коммитим, сообщение будет прочитано повторно
+		if err := b.sendToDLQ(msg, attempts, processingErr); err != nil {
+			logger.Error("не удалось отправить сообщение в DLQ", zap.Error(err))
+			return workerResult{
+				msg: msg,
+				err: fmt.Errorf("processing failed, DLQ unavailable: %w; sendToDLQ: %w", processingErr, err),
+			}
+		}
+		return workerResult{msg: msg}
+	}
+
+	logger.Info("сообщение обработано успешно")
+	return workerResult{msg: msg}
+}
@@ -0,0 +1,236 @@
+package kafka
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/IBM/sarama"
+	"github.com/IBM/sarama/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/sunr3d/order-stream-processor/internal/config"
+	"github.com/sunr3d/order-stream-processor/internal/interfaces/infra"
+)
+
+type fakeSession struct {
+	ctx    context.Context
+	mu     sync.Mutex
+	marked []int64
+}
+
+func (s *fakeSession) Claims() map[string][]int32               { return nil }
+func (s *fakeSession) MemberID() string                         { return "test" }
+func (s *fakeSession) GenerationID() int32                      { return 1 }
+func (s *fakeSession) MarkOffset(string, int32, int64, string)  {}
+func (s *fakeSession) Commit()                                  {}
+func (s *fakeSession) ResetOffset(string, int32, int64, string) {}
+func (s *fakeSession) Context() context.Context                 { return s.ctx }
+func (s *fakeSession) MarkMessage(msg *sarama.ConsumerMessage, _ string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.marked = append(s.marked, msg.Offset)
+}
+
+func (s *fakeSession) lastMarked() int64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.marked) == 0 {
+		return -1
+	}
+	return s.marked[len(s.marked)-1]
+}
+
+type fakeClaim struct {
+	messages chan *sarama.ConsumerMessage
+}
+
+func (c *fakeClaim) Topic() string                            { return "orders" }
+func (c *fakeClaim) Partition() int32                         { return 0 }
+func (c *fakeClaim) InitialOffset() int64                     { return 0 }
+func (c *fakeClaim) HighWaterMarkOffset() int64               { return int64(cap(c.messages)) }
+func (c *fakeClaim) Messages() <-chan *sarama.ConsumerMessage { return c.messages }
+
+func newTestClaim(keys ...string) *fakeClaim {
+	claim := &fakeClaim{messages: make(chan *sarama.ConsumerMessage, len(keys))}
+	for i, key := range keys {
+		msg := &sarama.ConsumerMessage{Topic: "orders", Offset: int64(i), Value: []byte(key)}
+		if key != "" {
+			msg.Key = []byte(key)
+		}
+		claim.messages <- msg
+	}
+	close(claim.messages)
+	return claim
+}
+
+func newTestBroker(workers int, handler infra.MessageHandler) *kafkaBroker {
+	return &kafkaBroker{
+		subs:   map[string]infra.Subscription{"orders": {Topic: "orders", Handler: handler}},
+		retry:  newRetryPolicy(config.KafkaRetryConfig{}),
+		config: config.KafkaConfig{Workers: workers, MaxRetries: 1},
+		logger: zap.NewNop(),
+	}
+}
+
+func TestWorkerIndex(t *testing.T) {
+	assert.Equal(t, 0, workerIndex(nil, 4), "сообщения без ключа обрабатываются первым воркером")
+	assert.Equal(t, 0, workerIndex([]byte("order-1"), 1))
+	assert.Equal(t, workerIndex([]byte("order-1"), 4), workerIndex([]byte("order-1"), 4))
+}
+
+func TestOffsetTracker_Contiguous(t *testing.T) {
+	tracker := newOffsetTracker()
+	for _, off := range []int64{10, 11, 13, 14} {
+		tracker.add(&sarama.ConsumerMessage{Offset: off})
+	}
+
+	assert.Nil(t, tracker.complete(11), "11 завершено раньше 10")
+	assert.Nil(t, tracker.complete(14))
+
+	last := tracker.complete(10)
+	require.NotNil(t, last)
+	assert.Equal(t, int64(11), last.Offset)
+
+	last = tracker.complete(13)
+	require.NotNil(t, last)
+	assert.Equal(t, int64(14), last.Offset, "пропуск оффсета 12 не блокирует коммит")
+	assert.Zero(t, tracker.len())
+	assert.Nil(t, tracker.complete(99))
+}
+
+func TestConsumeClaim_PerKeyOrdering(t *testing.T) {
+	var (
+		mu   sync.Mutex
+		seen = make(map[string][]int)
+		n    int
+	)
+	handler := func(ctx context.Context, value []byte) error {
+		mu.Lock()
+		n++
+		seq := n
+		mu.Unlock()
+
+		time.Sleep(time.Duration(seq%3) * time.Millisecond)
+
+		mu.Lock()
+		seen[string(value)] = append(seen[string(value)], seq)
+		mu.Unlock()
+		return nil
+	}
+
+	var keys []string
+	for i := range 40 {
+		keys = append(keys, fmt.Sprintf("order-%d", i%5))
+	}
+	broker := newTestBroker(4, handler)
+	session := &fakeSession{ctx: context.Background()}
+
+	err := broker.ConsumeClaim(session, newTestClaim(keys...))
+
+	require.NoError(t, err)
+	assert.Equal(t, int64(39), session.lastMarked())
+	for key, order := range seen {
+		assert.IsIncreasing(t, order, "сообщения ключа %s обработаны не по порядку", key)
+		assert.Len(t, order, 8)
+	}
+}
+
+func TestConsumeClaim_NeverMarksPastUnfinished(t *testing.T) {
+	// Быстрые сообщения должны попасть к другим воркерам, иначе они ждут медленное по порядку ключа
+	keys := []string{"slow"}
+	for i := 0; len(keys) < 6; i++ {
+		key := fmt.Sprintf("fast-%d", i)
+		if workerIndex([]byte(key), 4) != workerIndex([]byte("slow"), 4) {
+			keys = append(keys, key)
+		}
+	}
+
+	release := make(chan struct{})
+	var done sync.WaitGroup
+	done.Add(5)
+
+	handler := func(ctx context.Context, value []byte) error {
+		if string(value) == "slow" {
+			<-release
+			return nil
+		}
+		done.Done()
+		return nil
+	}
+
+	broker := newTestBroker(4, handler)
+	session := &fakeSession{ctx: context.Background()}
+	claim := newTestClaim(keys...)
+
+	result := make(chan error, 1)
+	go func() { result <- broker.ConsumeClaim(session, claim) }()
+
+	done.Wait()
+	time.Sleep(10 * time.Millisecond)
+	assert.Equal(t, int64(-1), session.lastMarked(), "оффсет не должен сдвинуться раньше медленного сообщения")
+
+	close(release)
+	require.NoError(t, <-result)
+	assert.Equal(t, int64(5), session.lastMarked())
+}
+
+func TestConsumeClaim_SessionEnd(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	handler := func(hctx context.Context, value []byte) error {
+		if string(value) == "b" {
+			cancel()
+			<-hctx.Done()
+			return hctx.Err()
+		}
+		return nil
+	}
+
+	broker := newTestBroker(1, handler)
+	session := &fakeSession{ctx: ctx}
+
+	err := broker.ConsumeClaim(session, newTestClaim("a", "b", "c"))
+
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), session.lastMarked(), "коммитится только сообщение до прерванного")
+}
+
+func TestConsumeClaim_DLQFailureKeepsBothErrors(t *testing.T) {
+	processingErr := fmt.Errorf("%w: невалидный заказ", infra.ErrPermanent)
+	dlqErr := errors.New("брокер недоступен")
+	handler := func(context.Context, []byte) error { return processingErr }
+
+	producer := mocks.NewSyncProducer(t, nil)
+	producer.ExpectSendMessageAndFail(dlqErr)
+
+	broker := newTestBroker(1, handler)
+	broker.producer = producer
+	broker.config.DLQTopic = "orders.dlq"
+	session := &fakeSession{ctx: context.Background()}
+
+	err := broker.ConsumeClaim(session, newTestClaim("a"))
+
+	require.Error(t, err)
+	assert.ErrorIs(t, err, processingErr)
+	assert.ErrorIs(t, err, dlqErr)
+	assert.Empty(t, session.marked, "без записи в DLQ оффсет не коммитится")
+}
+
+func TestConsumerHealthCheck_Grace(t *testing.T) {
+	broker := newTestBroker(1, nil)
+	broker.config.ConsumerGrace = time.Hour
+
+	require.NoError(t, broker.Cleanup(nil))
+	assert.NoError(t, broker.ConsumerHealthCheck(context.Background()), "ребалансировка в пределах grace не снимает готовность")
+
+	broker.leftAt.Store(time.Now().Add(-2 * time.Hour).UnixNano())
+	assert.Error(t, broker.ConsumerHealthCheck(context.Background()))
+
+	require.NoError(t, broker.Setup(nil))
+	assert.NoError(t, broker.ConsumerHealthCheck(context.Background()))
+}
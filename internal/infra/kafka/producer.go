@@ -0,0 +1,47 @@
+package kafka
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/IBM/sarama"
+	"go.uber.org/zap"
+
+	"github.com/sunr3d/order-stream-processor/internal/interfaces/infra"
+)
+
+// Publish синхронно отправляет сообщение и ждет подтверждения от всех in-sync реплик.
+func (b *kafkaBroker) Publish(ctx context.Context, msg infra.Message) error {
+	logger := b.logger.With(
+		zap.String("op", "kafka.Publish"),
+		zap.String("topic", msg.Topic),
+		zap.String("key", string(msg.Key)),
+	)
+
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	pm := &sarama.ProducerMessage{
+		Topic: msg.Topic,
+		Value: sarama.ByteEncoder(msg.Value),
+	}
+	if msg.Key != nil {
+		pm.Key = sarama.ByteEncoder(msg.Key)
+	}
+	for k, v := range msg.Headers {
+		pm.Headers = append(pm.Headers, sarama.RecordHeader{Key: []byte(k), Value: []byte(v)})
+	}
+
+	partition, offset, err := b.producer.SendMessage(pm)
+	if err != nil {
+		logger.Error("ошибка при публикации сообщения", zap.Error(err))
+		return fmt.Errorf("producer.SendMessage: %w", err)
+	}
+
+	logger.Info("сообщение опубликовано",
+		zap.Int32("partition", partition),
+		zap.Int64("offset", offset),
+	)
+	return nil
+}
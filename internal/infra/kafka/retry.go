@@ -0,0 +1,80 @@
+package kafka
+
+import (
+	"context"
+	"math"
+	"math/rand/v2"
+	"time"
+
+	"github.com/sunr3d/order-stream-processor/internal/config"
+)
+
+// retryPolicy рассчитывает паузы между повторными попытками обработки сообщения:
+// экспоненциальный рост от InitialDelay до MaxDelay со случайным разбросом ±Jitter.
+type retryPolicy struct {
+	initialDelay time.Duration
+	maxDelay     time.Duration
+	multiplier   float64
+	jitter       float64
+	random       func() float64
+}
+
+func newRetryPolicy(cfg config.KafkaRetryConfig) retryPolicy {
+	p := retryPolicy{
+		initialDelay: cfg.InitialDelay,
+		maxDelay:     cfg.MaxDelay,
+		multiplier:   cfg.Multiplier,
+		jitter:       cfg.Jitter,
+		random:       rand.Float64,
+	}
+
+	if p.multiplier < 1 {
+		p.multiplier = 1
+	}
+	if p.jitter < 0 {
+		p.jitter = 0
+	}
+	if p.jitter > 1 {
+		p.jitter = 1
+	}
+	if p.maxDelay > 0 && p.maxDelay < p.initialDelay {
+		p.maxDelay = p.initialDelay
+	}
+
+	return p
+}
+
+// delay возвращает паузу перед следующей попыткой после attempt неудачных попыток (attempt >= 1).
+func (p retryPolicy) delay(attempt int) time.Duration {
+	if p.initialDelay <= 0 || attempt < 1 {
+		return 0
+	}
+
+	d := float64(p.initialDelay) * math.Pow(p.multiplier, float64(attempt-1))
+	if p.maxDelay > 0 && d > float64(p.maxDelay) {
+		d = float64(p.maxDelay)
+	}
+
+	if p.jitter > 0 {
+		d *= 1 - p.jitter + 2*p.jitter*p.random()
+	}
+
+	return time.Duration(d)
+}
+
+// sleep ждет d или отмены контекста; возвращает ошибку контекста, если ожидание прервано.
+func sleep(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
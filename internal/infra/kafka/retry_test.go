@@ -0,0 +1,59 @@
+package kafka
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/sunr3d/order-stream-processor/internal/config"
+)
+
+func TestRetryPolicy_Delay_Exponential(t *testing.T) {
+	p := newRetryPolicy(config.KafkaRetryConfig{
+		InitialDelay: 100 * time.Millisecond,
+		MaxDelay:     time.Second,
+		Multiplier:   2,
+	})
+
+	assert.Equal(t, 100*time.Millisecond, p.delay(1))
+	assert.Equal(t, 200*time.Millisecond, p.delay(2))
+	assert.Equal(t, 400*time.Millisecond, p.delay(3))
+	assert.Equal(t, 800*time.Millisecond, p.delay(4))
+	assert.Equal(t, time.Second, p.delay(5))
+	assert.Equal(t, time.Second, p.delay(10))
+}
+
+func TestRetryPolicy_Delay_Jitter(t *testing.T) {
+	p := newRetryPolicy(config.KafkaRetryConfig{
+		InitialDelay: 100 * time.Millisecond,
+		MaxDelay:     time.Second,
+		Multiplier:   2,
+		Jitter:       0.5,
+	})
+
+	p.random = func() float64 { return 0 }
+	assert.Equal(t, 50*time.Millisecond, p.delay(1))
+
+	p.random = func() float64 { return 1 }
+	assert.Equal(t, 150*time.Millisecond, p.delay(1))
+}
+
+func TestRetryPolicy_Delay_Disabled(t *testing.T) {
+	p := newRetryPolicy(config.KafkaRetryConfig{})
+
+	assert.Zero(t, p.delay(1))
+	assert.Zero(t, p.delay(5))
+}
+
+func TestSleep_ContextCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	start := time.Now()
+	err := sleep(ctx, time.Minute)
+
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Less(t, time.Since(start), time.Second)
+}
@@ -0,0 +1,97 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// maxQueryParams — предел числа параметров одного запроса в протоколе PostgreSQL.
+const maxQueryParams = 65535
+
+// querySkipConflicts пропускает заказы, которые уже есть в БД, и возвращает order_uid вставленных.
+const querySkipConflicts = ` ON CONFLICT (order_uid) DO NOTHING RETURNING order_uid`
+
+// buildBulkInsert дописывает к prefix ("INSERT INTO t (...) VALUES ") по группе плейсхолдеров
+// на каждую строку и затем suffix. Все строки должны иметь одинаковое число колонок.
+func buildBulkInsert(prefix, suffix string, rows [][]any) (string, []any) {
+	var q strings.Builder
+	q.WriteString(prefix)
+
+	var args []any
+	if len(rows) > 0 {
+		args = make([]any, 0, len(rows)*len(rows[0]))
+	}
+	for i, row := range rows {
+		if i > 0 {
+			q.WriteString(", ")
+		}
+		q.WriteString("(")
+		for c := range row {
+			if c > 0 {
+				q.WriteString(", ")
+			}
+			fmt.Fprintf(&q, "$%d", len(args)+c+1)
+		}
+		q.WriteString(")")
+
+		args = append(args, row...)
+	}
+	q.WriteString(suffix)
+
+	return q.String(), args
+}
+
+// bulkChunks делит строки на части, каждая из которых укладывается в maxQueryParams.
+func bulkChunks(rows [][]any) [][][]any {
+	if len(rows) == 0 {
+		return nil
+	}
+
+	size := max(maxQueryParams/max(len(rows[0]), 1), 1)
+	chunks := make([][][]any, 0, (len(rows)+size-1)/size)
+	for len(rows) > size {
+		chunks = append(chunks, rows[:size])
+		rows = rows[size:]
+	}
+	return append(chunks, rows)
+}
+
+// execBulkInsert вставляет строки многострочными INSERT.
+func execBulkInsert(ctx context.Context, q querier, prefix string, rows [][]any) error {
+	for _, chunk := range bulkChunks(rows) {
+		query, args := buildBulkInsert(prefix, "", chunk)
+		if _, err := q.ExecContext(ctx, query, args...); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// insertSkippingConflicts вставляет заказы многострочными INSERT ... ON CONFLICT DO NOTHING
+// и возвращает множество order_uid, которые действительно были вставлены.
+func insertSkippingConflicts(ctx context.Context, q querier, prefix string, rows [][]any) (map[string]bool, error) {
+	inserted := make(map[string]bool, len(rows))
+	for _, chunk := range bulkChunks(rows) {
+		query, args := buildBulkInsert(prefix, querySkipConflicts, chunk)
+		result, err := q.QueryContext(ctx, query, args...)
+		if err != nil {
+			return nil, err
+		}
+
+		for result.Next() {
+			var uid string
+			if err := result.Scan(&uid); err != nil {
+				result.Close()
+				return nil, err
+			}
+			inserted[uid] = true
+		}
+		err = result.Err()
+		result.Close()
+		if err != nil {
+			return nil, err
+		}
+	}
+	return inserted, nil
+}
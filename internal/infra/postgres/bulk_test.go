@@ -0,0 +1,58 @@
+package postgres
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sunr3d/order-stream-processor/models"
+)
+
+func TestBuildBulkInsert(t *testing.T) {
+	rows := [][]any{{"a", []byte("1")}, {"b", []byte("2")}}
+
+	query, args := buildBulkInsert(queryCreateBatch, querySkipConflicts, rows)
+
+	assert.Equal(t, queryCreateBatch+"($1, $2), ($3, $4)"+querySkipConflicts, query)
+	assert.Equal(t, []any{"a", []byte("1"), "b", []byte("2")}, args)
+}
+
+func TestBulkChunks(t *testing.T) {
+	assert.Nil(t, bulkChunks(nil))
+
+	// 5 колонок: в один запрос помещается 13107 строк
+	rows := make([][]any, 30000)
+	for i := range rows {
+		rows[i] = make([]any, 5)
+	}
+
+	chunks := bulkChunks(rows)
+
+	require.Len(t, chunks, 3)
+	assert.Len(t, chunks[0], 13107)
+	assert.Len(t, chunks[1], 13107)
+	assert.Len(t, chunks[2], 30000-2*13107)
+	for _, chunk := range chunks {
+		assert.LessOrEqual(t, len(chunk)*5, maxQueryParams)
+	}
+}
+
+func TestBatchResults(t *testing.T) {
+	orders := []*models.Order{
+		{OrderUID: "new"},
+		{OrderUID: "existing"},
+		{OrderUID: "deleted"},
+		{OrderUID: "new"},
+	}
+	inserted := map[string]bool{"new": true}
+	erased := map[string]bool{"deleted": true}
+
+	results := batchResults(orders, inserted, erased)
+
+	require.Len(t, results, 4)
+	assert.NoError(t, results[0])
+	assert.ErrorIs(t, results[1], models.ErrOrderAlreadyExists)
+	assert.ErrorIs(t, results[2], models.ErrOrderErased)
+	assert.ErrorIs(t, results[3], models.ErrOrderAlreadyExists)
+}
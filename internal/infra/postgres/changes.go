@@ -0,0 +1,94 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/sunr3d/order-stream-processor/internal/metrics"
+	"github.com/sunr3d/order-stream-processor/models"
+)
+
+const (
+	queryNow          = `SELECT now()`
+	queryDeletedSince = `SELECT DISTINCT unnest(order_uids) FROM erasure_audit
+		WHERE action = '` + models.AuditOrderDeleted + `' AND created_at >= $1`
+)
+
+// ScanChangedSince передает в fn пачками по chunkSize заказы, измененные начиная с since,
+// и возвращает удаленные с того же момента заказы. С нулевым since передаются все заказы.
+//
+// Таблица обходится по order_uid (keyset), каждая пачка читается отдельным запросом, поэтому
+// в памяти одновременно находится не больше одной пачки, а обход не держит длинную транзакцию.
+// Watermark — время БД до начала обхода: заказы, измененные во время обхода, могут быть как прочитаны,
+// так и пропущены, и догружаются со следующего Watermark.
+func (r *postgresRepo) ScanChangedSince(ctx context.Context, since time.Time, chunkSize int,
+	fn func(ctx context.Context, orders []*models.Order) error) (*models.OrderChanges, error) {
+	defer metrics.QueryTimer("scan_changed_since").ObserveDuration()
+
+	logger := r.logger.With(
+		zap.String("op", "postgres.ScanChangedSince"),
+		zap.Time("since", since),
+		zap.Int("chunk_size", chunkSize),
+	)
+
+	logger.Info("получение изменений заказов из БД...")
+
+	if chunkSize <= 0 {
+		return nil, fmt.Errorf("некорректный размер пачки: %d", chunkSize)
+	}
+
+	var changes models.OrderChanges
+	if err := r.db.QueryRowContext(ctx, queryNow).Scan(&changes.Watermark); err != nil {
+		logger.Error("ошибка при получении времени БД", zap.Error(err))
+		return nil, wrapErr("db.QueryRowContext", err)
+	}
+
+	if !since.IsZero() {
+		rows, err := r.db.QueryContext(ctx, queryDeletedSince, since)
+		if err != nil {
+			logger.Error("ошибка при получении удаленных заказов", zap.Error(err))
+			return nil, wrapErr("db.QueryContext", err)
+		}
+		changes.Deleted, err = scanOrderUIDs(rows)
+		if err != nil {
+			logger.Error("ошибка при получении удаленных заказов", zap.Error(err))
+			return nil, wrapErr("scanOrderUIDs", err)
+		}
+	}
+
+	after := ""
+	for {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		orders, err := r.store.changedSince(ctx, r.db, since, after, chunkSize)
+		if err != nil {
+			logger.Error("ошибка при получении измененных заказов", zap.String("after", after), zap.Error(err))
+			return nil, wrapErr("store.changedSince", err)
+		}
+		if len(orders) == 0 {
+			break
+		}
+
+		if err := fn(ctx, orders); err != nil {
+			return nil, err
+		}
+		changes.Count += len(orders)
+
+		if len(orders) < chunkSize {
+			break
+		}
+		after = orders[len(orders)-1].OrderUID
+	}
+
+	logger.Info("изменения заказов получены",
+		zap.Int("changed", changes.Count),
+		zap.Int("deleted", len(changes.Deleted)),
+		zap.Time("watermark", changes.Watermark),
+	)
+	return &changes, nil
+}
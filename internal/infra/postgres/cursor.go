@@ -0,0 +1,35 @@
+package postgres
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/sunr3d/order-stream-processor/models"
+)
+
+// listCursor — позиция последнего заказа страницы для keyset-пагинации.
+type listCursor struct {
+	DateCreated time.Time `json:"d"`
+	OrderUID    string    `json:"u"`
+}
+
+func encodeCursor(order *models.Order) string {
+	data, _ := json.Marshal(listCursor{DateCreated: order.DateCreated, OrderUID: order.OrderUID})
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func decodeCursor(s string) (*listCursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, fmt.Errorf("%w: некорректный курсор", models.ErrValidation)
+	}
+
+	var c listCursor
+	if err := json.Unmarshal(data, &c); err != nil || c.OrderUID == "" {
+		return nil, fmt.Errorf("%w: некорректный курсор", models.ErrValidation)
+	}
+
+	return &c, nil
+}
@@ -0,0 +1,215 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/lib/pq"
+	"go.uber.org/zap"
+
+	"github.com/sunr3d/order-stream-processor/internal/metrics"
+	"github.com/sunr3d/order-stream-processor/models"
+)
+
+const (
+	queryDeleteHistory = `DELETE FROM order_status_history WHERE order_uid = $1`
+	// queryScrubOutbox удаляет копии заказов из событий outbox, в том числе еще не опубликованных
+	queryScrubOutbox = `UPDATE outbox SET payload = 'null'::jsonb WHERE aggregate_id = ANY($1) AND payload <> 'null'::jsonb`
+	queryInsertAudit = `INSERT INTO erasure_audit (action, customer_id, order_uids, requested_by, reason)
+		VALUES ($1, $2, $3, $4, $5) RETURNING id, created_at`
+	// queryErasedOrders выбирает из переданных order_uid те, что есть в журнале аудита
+	queryErasedOrders = `SELECT DISTINCT uid FROM erasure_audit, unnest(order_uids) AS uid
+		WHERE order_uids && $1 AND uid = ANY($1)`
+)
+
+// Delete удаляет заказ, его историю статусов и копии заказа в outbox, записывает событие order.deleted
+// и запись в журнал аудита в одной транзакции.
+func (r *postgresRepo) Delete(ctx context.Context, req models.DeletionRequest) error {
+	defer metrics.QueryTimer("delete").ObserveDuration()
+
+	logger := r.logger.With(
+		zap.String("op", "postgres.Delete"),
+		zap.String("order_uid", req.OrderUID),
+		zap.String("requested_by", req.RequestedBy),
+	)
+
+	logger.Info("удаление заказа из БД...")
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		logger.Error("ошибка при создании транзакции", zap.Error(err))
+		return wrapErr("db.BeginTx", err)
+	}
+	defer tx.Rollback()
+
+	order, err := r.store.read(ctx, tx, req.OrderUID, true)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			logger.Info("заказ не найден")
+			return fmt.Errorf("%w: %s", models.ErrOrderNotFound, req.OrderUID)
+		}
+		logger.Error("ошибка чтения из БД", zap.Error(err))
+		return wrapErr("store.read", err)
+	}
+
+	if err := r.store.delete(ctx, tx, req.OrderUID); err != nil {
+		logger.Error("ошибка при удалении заказа из БД", zap.Error(err))
+		return wrapErr("store.delete", err)
+	}
+	if _, err := tx.ExecContext(ctx, queryDeleteHistory, req.OrderUID); err != nil {
+		logger.Error("ошибка при удалении истории статусов", zap.Error(err))
+		return wrapErr("tx.ExecContext(history)", err)
+	}
+	if err := scrubOutbox(ctx, tx, []string{req.OrderUID}); err != nil {
+		logger.Error("ошибка при очистке outbox", zap.Error(err))
+		return err
+	}
+	if err := insertOutbox(ctx, tx, models.EventOrderDeleted, req.OrderUID, []byte("null")); err != nil {
+		logger.Error("ошибка при записи события в outbox", zap.Error(err))
+		return err
+	}
+	if _, _, err := insertAudit(ctx, tx, models.AuditOrderDeleted, order.CustomerID, []string{req.OrderUID},
+		req.RequestedBy, req.Reason); err != nil {
+		logger.Error("ошибка при записи в журнал аудита", zap.Error(err))
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		logger.Error("ошибка при фиксации транзакции", zap.Error(err))
+		return wrapErr("tx.Commit", err)
+	}
+
+	logger.Info("заказ успешно удален из БД")
+	return nil
+}
+
+// EraseCustomer обезличивает данные получателя во всех заказах покупателя (см. models.Order.AnonymizeDelivery)
+// в одной транзакции. Измененные заказы получают новую версию и событие order.updated, копии заказов
+// в outbox очищаются. Запись в журнал аудита делается и тогда, когда заказов у покупателя нет.
+func (r *postgresRepo) EraseCustomer(ctx context.Context, req models.ErasureRequest) (*models.ErasureResult, error) {
+	defer metrics.QueryTimer("erase_customer").ObserveDuration()
+
+	logger := r.logger.With(
+		zap.String("op", "postgres.EraseCustomer"),
+		zap.String("customer_id", req.CustomerID),
+		zap.String("requested_by", req.RequestedBy),
+	)
+
+	logger.Info("обезличивание заказов покупателя в БД...")
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		logger.Error("ошибка при создании транзакции", zap.Error(err))
+		return nil, wrapErr("db.BeginTx", err)
+	}
+	defer tx.Rollback()
+
+	uids, err := r.store.customerOrders(ctx, tx, req.CustomerID)
+	if err != nil {
+		logger.Error("ошибка при поиске заказов покупателя", zap.Error(err))
+		return nil, wrapErr("store.customerOrders", err)
+	}
+
+	// Outbox очищается до записи новых событий: они уже содержат обезличенные заказы
+	if err := scrubOutbox(ctx, tx, uids); err != nil {
+		logger.Error("ошибка при очистке outbox", zap.Error(err))
+		return nil, err
+	}
+
+	erased := 0
+	for _, uid := range uids {
+		order, err := r.store.read(ctx, tx, uid, false)
+		if err != nil {
+			logger.Error("ошибка чтения из БД", zap.String("order_uid", uid), zap.Error(err))
+			return nil, wrapErr("store.read", err)
+		}
+		if !order.AnonymizeDelivery() {
+			continue
+		}
+
+		order.Version = order.CurrentVersion() + 1
+		if err := r.replace(ctx, tx, order); err != nil {
+			logger.Error("ошибка при обновлении заказа в БД", zap.String("order_uid", uid), zap.Error(err))
+			return nil, err
+		}
+		erased++
+	}
+
+	if uids == nil {
+		uids = []string{}
+	}
+	auditID, erasedAt, err := insertAudit(ctx, tx, models.AuditCustomerErased, req.CustomerID, uids,
+		req.RequestedBy, req.Reason)
+	if err != nil {
+		logger.Error("ошибка при записи в журнал аудита", zap.Error(err))
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		logger.Error("ошибка при фиксации транзакции", zap.Error(err))
+		return nil, wrapErr("tx.Commit", err)
+	}
+
+	logger.Info("заказы покупателя обезличены",
+		zap.Int("orders", len(uids)),
+		zap.Int("erased", erased),
+		zap.Int64("audit_id", auditID),
+	)
+	return &models.ErasureResult{
+		CustomerID: req.CustomerID,
+		OrderUIDs:  uids,
+		AuditID:    auditID,
+		ErasedAt:   erasedAt,
+	}, nil
+}
+
+// scrubOutbox заменяет копии заказов в событиях outbox на null.
+func scrubOutbox(ctx context.Context, tx *sql.Tx, orderUIDs []string) error {
+	if len(orderUIDs) == 0 {
+		return nil
+	}
+	if _, err := tx.ExecContext(ctx, queryScrubOutbox, pq.Array(orderUIDs)); err != nil {
+		return wrapErr("tx.ExecContext(outbox)", err)
+	}
+	return nil
+}
+
+func insertAudit(ctx context.Context, tx *sql.Tx, action, customerID string, orderUIDs []string, requestedBy, reason string) (int64, time.Time, error) {
+	var (
+		id        int64
+		createdAt time.Time
+	)
+	err := tx.QueryRowContext(ctx, queryInsertAudit, action, customerID, pq.Array(orderUIDs), requestedBy, reason).
+		Scan(&id, &createdAt)
+	if err != nil {
+		return 0, time.Time{}, wrapErr("tx.QueryRowContext(audit)", err)
+	}
+	return id, createdAt, nil
+}
+
+// erasedOrders возвращает order_uid из uids, которые были удалены или обезличены. Журнал аудита служит
+// надгробием: повторная доставка исходного сообщения не должна вернуть удаленные данные.
+func erasedOrders(ctx context.Context, q querier, uids []string) (map[string]bool, error) {
+	rows, err := q.QueryContext(ctx, queryErasedOrders, pq.Array(uids))
+	if err != nil {
+		return nil, wrapErr("QueryContext(erasure_audit)", err)
+	}
+	erased, err := scanOrderUIDs(rows)
+	if err != nil {
+		return nil, wrapErr("scanOrderUIDs", err)
+	}
+
+	set := make(map[string]bool, len(erased))
+	for _, uid := range erased {
+		set[uid] = true
+	}
+	return set, nil
+}
+
+// erasedErr возвращает ошибку для заказа, найденного в журнале аудита.
+func erasedErr(orderUID string) error {
+	return fmt.Errorf("%w: %s", models.ErrOrderErased, orderUID)
+}
@@ -0,0 +1,69 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"net"
+
+	"github.com/lib/pq"
+
+	"github.com/sunr3d/order-stream-processor/models"
+)
+
+// Коды ошибок PostgreSQL (https://www.postgresql.org/docs/current/errcodes-appendix.html)
+const (
+	pgUniqueViolation        = "23505"
+	pgClassDataException     = "22"
+	pgClassConnection        = "08"
+	pgClassResources         = "53"
+	pgClassOperatorIntervene = "57"
+)
+
+func isUniqueViolation(err error) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code == pgUniqueViolation
+}
+
+// isUnavailable определяет ошибки, вызванные недоступностью БД, а не самим запросом.
+func isUnavailable(err error) bool {
+	if errors.Is(err, driver.ErrBadConn) || errors.Is(err, sql.ErrConnDone) || errors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		return true
+	}
+
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) {
+		switch pqErr.Code.Class() {
+		case pgClassConnection, pgClassResources, pgClassOperatorIntervene:
+			return true
+		}
+	}
+
+	return false
+}
+
+// isDataException определяет ошибки из-за значений, не подходящих под типы колонок
+// (например, слишком длинная строка в нормализованной схеме).
+func isDataException(err error) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code.Class() == pgClassDataException
+}
+
+// wrapErr оборачивает ошибку драйвера в models.ErrUnavailable, если БД недоступна,
+// и в models.ErrValidation, если БД отвергла сами данные.
+func wrapErr(op string, err error) error {
+	if isUnavailable(err) {
+		return fmt.Errorf("%s: %w: %w", op, models.ErrUnavailable, err)
+	}
+	if isDataException(err) {
+		return fmt.Errorf("%s: %w: %w", op, models.ErrValidation, err)
+	}
+	return fmt.Errorf("%s: %w", op, err)
+}
@@ -0,0 +1,130 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"go.uber.org/zap"
+
+	"github.com/sunr3d/order-stream-processor/internal/metrics"
+	"github.com/sunr3d/order-stream-processor/models"
+)
+
+// listSortKey совпадает с выражением индекса idx_orders_date_created (миграция 0011).
+const listSortKey = `order_date_created(data)`
+
+// containment строит JSONB-документ для оператора @>, который обслуживается GIN индексом idx_orders_data.
+func containment(f models.OrderFilter) map[string]any {
+	doc := make(map[string]any)
+
+	if f.CustomerID != "" {
+		doc["customer_id"] = f.CustomerID
+	}
+	if f.DeliveryService != "" {
+		doc["delivery_service"] = f.DeliveryService
+	}
+	if f.TrackNumber != "" {
+		doc["track_number"] = f.TrackNumber
+	}
+
+	payment := make(map[string]any)
+	if f.Currency != "" {
+		payment["currency"] = f.Currency
+	}
+	if f.Provider != "" {
+		payment["provider"] = f.Provider
+	}
+	if len(payment) > 0 {
+		doc["payment"] = payment
+	}
+
+	if f.Brand != "" {
+		doc["items"] = []map[string]any{{"brand": f.Brand}}
+	}
+
+	return doc
+}
+
+func buildListQuery(f models.OrderFilter) (string, []any, error) {
+	var (
+		where []string
+		args  []any
+	)
+	arg := func(v any) string {
+		args = append(args, v)
+		return fmt.Sprintf("$%d", len(args))
+	}
+
+	if doc := containment(f); len(doc) > 0 {
+		data, err := json.Marshal(doc)
+		if err != nil {
+			return "", nil, fmt.Errorf("json.Marshal: %w", err)
+		}
+		where = append(where, "data @> "+arg(string(data))+"::jsonb")
+	}
+	if !f.CreatedFrom.IsZero() {
+		where = append(where, listSortKey+" >= "+arg(f.CreatedFrom))
+	}
+	if !f.CreatedTo.IsZero() {
+		where = append(where, listSortKey+" < "+arg(f.CreatedTo))
+	}
+
+	cmp, dir := ">", "ASC"
+	if f.Sort == models.SortDesc {
+		cmp, dir = "<", "DESC"
+	}
+
+	if f.Cursor != "" {
+		cursor, err := decodeCursor(f.Cursor)
+		if err != nil {
+			return "", nil, err
+		}
+		where = append(where, fmt.Sprintf("(%s, order_uid) %s (%s, %s)", listSortKey, cmp, arg(cursor.DateCreated), arg(cursor.OrderUID)))
+	}
+
+	var q strings.Builder
+	q.WriteString("SELECT " + jsonbDocument + " FROM orders")
+	if len(where) > 0 {
+		q.WriteString(" WHERE ")
+		q.WriteString(strings.Join(where, " AND "))
+	}
+	fmt.Fprintf(&q, " ORDER BY %s %s, order_uid %s LIMIT %s", listSortKey, dir, dir, arg(f.Limit+1))
+
+	return q.String(), args, nil
+}
+
+func (r *postgresRepo) List(ctx context.Context, filter models.OrderFilter) (*models.OrderPage, error) {
+	defer metrics.QueryTimer("list").ObserveDuration()
+
+	logger := r.logger.With(
+		zap.String("op", "postgres.List"),
+	)
+
+	logger.Info("получение списка заказов из БД...")
+
+	if filter.Limit <= 0 {
+		return nil, fmt.Errorf("%w: limit должен быть больше 0", models.ErrValidation)
+	}
+
+	orders, err := r.store.list(ctx, r.db, filter)
+	if err != nil {
+		if errors.Is(err, models.ErrValidation) {
+			logger.Info("некорректные параметры выборки", zap.Error(err))
+			return nil, fmt.Errorf("store.list: %w", err)
+		}
+		logger.Error("ошибка при получении списка заказов из БД", zap.Error(err))
+		return nil, wrapErr("store.list", err)
+	}
+
+	page := &models.OrderPage{Orders: orders}
+	if len(orders) > filter.Limit {
+		page.Orders = orders[:filter.Limit]
+		page.NextCursor = encodeCursor(page.Orders[filter.Limit-1])
+	}
+
+	logger.Info("список заказов успешно получен из БД", zap.Int("count", len(page.Orders)))
+	return page, nil
+}
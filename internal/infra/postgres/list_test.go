@@ -0,0 +1,58 @@
+package postgres
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sunr3d/order-stream-processor/models"
+)
+
+func TestBuildListQuery_Filters(t *testing.T) {
+	from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	query, args, err := buildListQuery(models.OrderFilter{
+		CustomerID:  "customer-1",
+		Currency:    "RUB",
+		Brand:       "Nike",
+		CreatedFrom: from,
+		Sort:        models.SortAsc,
+		Limit:       10,
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t,
+		`SELECT `+jsonbDocument+` FROM orders WHERE data @> $1::jsonb AND order_date_created(data) >= $2 `+
+			`ORDER BY order_date_created(data) ASC, order_uid ASC LIMIT $3`,
+		query,
+	)
+	assert.JSONEq(t, `{"customer_id":"customer-1","payment":{"currency":"RUB"},"items":[{"brand":"Nike"}]}`, args[0].(string))
+	assert.Equal(t, from, args[1])
+	assert.Equal(t, 11, args[2])
+}
+
+func TestBuildListQuery_CursorDesc(t *testing.T) {
+	order := &models.Order{OrderUID: "test-1", DateCreated: time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC)}
+
+	query, args, err := buildListQuery(models.OrderFilter{
+		Sort:   models.SortDesc,
+		Limit:  5,
+		Cursor: encodeCursor(order),
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t,
+		`SELECT `+jsonbDocument+` FROM orders WHERE (order_date_created(data), order_uid) < ($1, $2) `+
+			`ORDER BY order_date_created(data) DESC, order_uid DESC LIMIT $3`,
+		query,
+	)
+	assert.True(t, order.DateCreated.Equal(args[0].(time.Time)))
+	assert.Equal(t, "test-1", args[1])
+}
+
+func TestBuildListQuery_InvalidCursor(t *testing.T) {
+	_, _, err := buildListQuery(models.OrderFilter{Limit: 5, Cursor: "не курсор"})
+
+	assert.ErrorIs(t, err, models.ErrValidation)
+}
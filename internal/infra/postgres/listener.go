@@ -0,0 +1,186 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/lib/pq"
+	"go.uber.org/zap"
+
+	"github.com/sunr3d/order-stream-processor/internal/config"
+	"github.com/sunr3d/order-stream-processor/internal/interfaces/infra"
+	"github.com/sunr3d/order-stream-processor/models"
+)
+
+// OrderChangesChannel — канал NOTIFY, в который триггеры таблиц заказов пишут изменения (миграция 0008).
+const OrderChangesChannel = "order_changes"
+
+var _ infra.ChangeListener = (*changeListener)(nil)
+
+// changeListener получает уведомления об изменениях заказов через отдельное соединение LISTEN.
+type changeListener struct {
+	dsn    string
+	cfg    config.CacheInvalidationConfig
+	logger *zap.Logger
+}
+
+func NewListener(cfg config.PostgresConfig, invCfg config.CacheInvalidationConfig, log *zap.Logger) infra.ChangeListener {
+	return &changeListener{
+		dsn:    dsn(cfg),
+		cfg:    invCfg,
+		logger: log,
+	}
+}
+
+// Listen подписывается на OrderChangesChannel и передает уведомления в handler до отмены ctx.
+// Соединение восстанавливается автоматически; после подписки и каждого переподключения вызывается
+// handler.Resync с моментом, начиная с которого уведомления могли быть потеряны. Resync выполняется
+// в отдельной горутине, чтобы уведомления продолжали вычитываться, пока идет сверка.
+func (l *changeListener) Listen(ctx context.Context, handler infra.ChangeHandler) error {
+	logger := l.logger.With(
+		zap.String("op", "postgres.Listen"),
+		zap.String("channel", OrderChangesChannel),
+	)
+
+	lostSince := time.Now()
+	listener := pq.NewListener(l.dsn, l.cfg.MinReconnect, l.cfg.MaxReconnect, func(event pq.ListenerEventType, err error) {
+		switch event {
+		case pq.ListenerEventDisconnected:
+			logger.Warn("соединение для уведомлений потеряно", zap.Error(err))
+		case pq.ListenerEventConnectionAttemptFailed:
+			logger.Warn("не удалось подключиться для получения уведомлений", zap.Error(err))
+		case pq.ListenerEventReconnected:
+			logger.Info("соединение для уведомлений восстановлено")
+		}
+	})
+	defer listener.Close()
+
+	// Listen ждет соединения с БД; закрытие слушателя прерывает ожидание при отмене ctx
+	stop := context.AfterFunc(ctx, func() { listener.Close() })
+	defer stop()
+
+	if err := listener.Listen(OrderChangesChannel); err != nil {
+		if ctx.Err() != nil {
+			return nil
+		}
+		logger.Error("ошибка при подписке на уведомления", zap.Error(err))
+		return fmt.Errorf("listener.Listen: %w", err)
+	}
+
+	logger.Info("подписка на уведомления об изменениях заказов оформлена")
+
+	resync := newResyncer()
+	resyncCtx, cancel := context.WithCancel(ctx)
+	resyncDone := make(chan struct{})
+	go func() {
+		defer close(resyncDone)
+		resync.run(resyncCtx, handler)
+	}()
+	defer func() {
+		cancel()
+		<-resyncDone
+	}()
+
+	// Изменения, сделанные до подписки, уведомлений не дали
+	resync.request(lostSince)
+	lastAlive := time.Now()
+
+	var tick <-chan time.Time
+	if l.cfg.PingInterval > 0 {
+		ticker := time.NewTicker(l.cfg.PingInterval)
+		defer ticker.Stop()
+		tick = ticker.C
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case n, ok := <-listener.Notify:
+			if !ok {
+				return nil
+			}
+			// nil приходит после переподключения: уведомления без соединения потеряны
+			if n == nil {
+				resync.request(lastAlive)
+				lastAlive = time.Now()
+				continue
+			}
+			lastAlive = time.Now()
+
+			change, err := parseChange(n.Extra)
+			if err != nil {
+				logger.Warn("некорректное уведомление об изменении заказа", zap.String("payload", n.Extra), zap.Error(err))
+				continue
+			}
+			handler.OrderChanged(ctx, change)
+		case <-tick:
+			// Без проверки обрыв соединения обнаруживается только при следующей записи в него
+			if err := listener.Ping(); err != nil {
+				logger.Warn("соединение для уведомлений не отвечает", zap.Error(err))
+				continue
+			}
+			lastAlive = time.Now()
+		}
+	}
+}
+
+// resyncer выполняет запрошенные сверки по одной. Запросы, пришедшие во время сверки, объединяются
+// в одну следующую сверку с самого раннего lostSince.
+type resyncer struct {
+	mu      sync.Mutex
+	pending time.Time
+	wake    chan struct{}
+}
+
+func newResyncer() *resyncer {
+	return &resyncer{wake: make(chan struct{}, 1)}
+}
+
+// request запрашивает сверку с момента lostSince и не блокируется.
+func (r *resyncer) request(lostSince time.Time) {
+	r.mu.Lock()
+	if r.pending.IsZero() || lostSince.Before(r.pending) {
+		r.pending = lostSince
+	}
+	r.mu.Unlock()
+
+	select {
+	case r.wake <- struct{}{}:
+	default:
+	}
+}
+
+// run вызывает handler.Resync для запрошенных сверок до отмены ctx.
+func (r *resyncer) run(ctx context.Context, handler infra.ChangeHandler) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-r.wake:
+		}
+
+		r.mu.Lock()
+		lostSince := r.pending
+		r.pending = time.Time{}
+		r.mu.Unlock()
+
+		if !lostSince.IsZero() {
+			handler.Resync(ctx, lostSince)
+		}
+	}
+}
+
+func parseChange(payload string) (models.OrderChange, error) {
+	var change models.OrderChange
+	if err := json.Unmarshal([]byte(payload), &change); err != nil {
+		return models.OrderChange{}, fmt.Errorf("json.Unmarshal: %w", err)
+	}
+	if change.OrderUID == "" {
+		return models.OrderChange{}, fmt.Errorf("не указан order_uid")
+	}
+	return change, nil
+}
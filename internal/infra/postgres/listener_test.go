@@ -0,0 +1,79 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sunr3d/order-stream-processor/models"
+)
+
+func TestParseChange(t *testing.T) {
+	change, err := parseChange(`{"order_uid": "b563feb7b2b84b6test", "version": 3, "deleted": false}`)
+	require.NoError(t, err)
+	assert.Equal(t, models.OrderChange{OrderUID: "b563feb7b2b84b6test", Version: 3}, change)
+
+	change, err = parseChange(`{"order_uid": "b563feb7b2b84b6test", "version": 3, "deleted": true}`)
+	require.NoError(t, err)
+	assert.True(t, change.Deleted)
+
+	_, err = parseChange(`{"version": 3}`)
+	assert.Error(t, err)
+	_, err = parseChange(`not json`)
+	assert.Error(t, err)
+}
+
+type blockingHandler struct {
+	started chan time.Time
+	release chan struct{}
+}
+
+func (h *blockingHandler) OrderChanged(context.Context, models.OrderChange) {}
+
+func (h *blockingHandler) Resync(ctx context.Context, lostSince time.Time) {
+	h.started <- lostSince
+	select {
+	case <-ctx.Done():
+	case <-h.release:
+	}
+}
+
+func TestResyncer_CoalescesToEarliest(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	h := &blockingHandler{started: make(chan time.Time, 10), release: make(chan struct{})}
+	r := newResyncer()
+	go r.run(ctx, h)
+
+	base := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	r.request(base)
+	assert.Equal(t, base, <-h.started)
+
+	// Пока первая сверка идет, запросы не блокируются и объединяются
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		r.request(base.Add(3 * time.Second))
+		r.request(base.Add(time.Second))
+		r.request(base.Add(2 * time.Second))
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("request заблокирован выполняющейся сверкой")
+	}
+
+	h.release <- struct{}{}
+	assert.Equal(t, base.Add(time.Second), <-h.started)
+
+	h.release <- struct{}{}
+	select {
+	case lostSince := <-h.started:
+		t.Fatalf("лишняя сверка с %v", lostSince)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
@@ -0,0 +1,58 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"go.uber.org/zap"
+
+	"github.com/sunr3d/order-stream-processor/internal/metrics"
+	"github.com/sunr3d/order-stream-processor/models"
+)
+
+// lookupKeys — выражения вторичных ключей в документе заказа, по ним построены индексы миграции 0009.
+var lookupKeys = map[models.LookupField]string{
+	models.LookupTrackNumber: `data->>'track_number'`,
+	models.LookupTransaction: `data->'payment'->>'transaction'`,
+	models.LookupCustomer:    `data->>'customer_id'`,
+}
+
+func buildLookupQuery(l models.OrderLookup) (string, error) {
+	key, ok := lookupKeys[l.Field]
+	if !ok {
+		return "", fmt.Errorf("%w: неизвестный ключ поиска %q", models.ErrValidation, l.Field)
+	}
+	return fmt.Sprintf("SELECT %s FROM orders WHERE (%s) = $1 ORDER BY %s DESC, order_uid DESC LIMIT $2",
+		jsonbDocument, key, listSortKey), nil
+}
+
+// Lookup возвращает до lookup.Limit заказов с заданным значением вторичного ключа, от новых к старым.
+func (r *postgresRepo) Lookup(ctx context.Context, lookup models.OrderLookup) ([]*models.Order, error) {
+	defer metrics.QueryTimer("lookup").ObserveDuration()
+
+	logger := r.logger.With(
+		zap.String("op", "postgres.Lookup"),
+		zap.String("field", string(lookup.Field)),
+		zap.String("value", lookup.Value),
+	)
+
+	logger.Info("поиск заказов в БД...")
+
+	if lookup.Limit <= 0 {
+		return nil, fmt.Errorf("%w: limit должен быть больше 0", models.ErrValidation)
+	}
+
+	orders, err := r.store.lookup(ctx, r.db, lookup)
+	if err != nil {
+		if errors.Is(err, models.ErrValidation) {
+			logger.Info("некорректные параметры поиска", zap.Error(err))
+			return nil, fmt.Errorf("store.lookup: %w", err)
+		}
+		logger.Error("ошибка при поиске заказов в БД", zap.Error(err))
+		return nil, wrapErr("store.lookup", err)
+	}
+
+	logger.Info("поиск заказов в БД выполнен", zap.Int("count", len(orders)))
+	return orders, nil
+}
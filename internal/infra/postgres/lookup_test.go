@@ -0,0 +1,47 @@
+package postgres
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sunr3d/order-stream-processor/models"
+)
+
+func TestBuildLookupQuery(t *testing.T) {
+	query, err := buildLookupQuery(models.OrderLookup{Field: models.LookupTransaction, Value: "tx-1", Limit: 10})
+	require.NoError(t, err)
+
+	assert.Equal(t,
+		`SELECT `+jsonbDocument+` FROM orders WHERE (data->'payment'->>'transaction') = $1 `+
+			`ORDER BY order_date_created(data) DESC, order_uid DESC LIMIT $2`,
+		query,
+	)
+}
+
+func TestBuildNormalizedLookupQuery(t *testing.T) {
+	query, err := buildNormalizedLookupQuery(models.OrderLookup{Field: models.LookupTrackNumber, Value: "TRACK-1", Limit: 10})
+	require.NoError(t, err)
+
+	assert.True(t, strings.HasPrefix(query, normSelectOrders))
+	assert.True(t, strings.HasSuffix(query,
+		` WHERE o.track_number = $1 ORDER BY o.date_created DESC, o.order_uid DESC LIMIT $2`,
+	), query)
+}
+
+func TestBuildLookupQuery_UnknownField(t *testing.T) {
+	_, err := buildLookupQuery(models.OrderLookup{Field: "phone", Value: "1", Limit: 10})
+	assert.ErrorIs(t, err, models.ErrValidation)
+
+	_, err = buildNormalizedLookupQuery(models.OrderLookup{Field: "phone", Value: "1", Limit: 10})
+	assert.ErrorIs(t, err, models.ErrValidation)
+}
+
+func TestLookupKeys_CoverAllFields(t *testing.T) {
+	for _, field := range models.LookupFields {
+		assert.Contains(t, lookupKeys, field)
+		assert.Contains(t, normLookupKeys, field)
+	}
+}
@@ -0,0 +1,396 @@
+package postgres
+
+import (
+	"cmp"
+	"context"
+	"database/sql"
+	"fmt"
+	"io/fs"
+	"path"
+	"regexp"
+	"slices"
+	"strconv"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// migrationsLockKey — ключ advisory lock, под которым миграции применяет только одна реплика.
+const migrationsLockKey = 7_406_002
+
+const (
+	queryCreateMigrationsTable = `
+		CREATE TABLE IF NOT EXISTS schema_migrations (
+			version BIGINT PRIMARY KEY,
+			name VARCHAR(255) NOT NULL,
+			applied_at TIMESTAMPTZ NOT NULL DEFAULT now()
+		)`
+	queryAppliedMigrations = `SELECT version, applied_at FROM schema_migrations ORDER BY version`
+	queryInsertMigration   = `INSERT INTO schema_migrations (version, name) VALUES ($1, $2)`
+	queryDeleteMigration   = `DELETE FROM schema_migrations WHERE version = $1`
+	queryMigrationsLock    = `SELECT pg_advisory_lock($1)`
+	queryMigrationsUnlock  = `SELECT pg_advisory_unlock($1)`
+)
+
+var migrationFileRe = regexp.MustCompile(`^(\d+)_(\w+)\.(up|down)\.sql$`)
+
+type migration struct {
+	version int64
+	name    string
+	up      string
+	down    string
+}
+
+// MigrationStatus — состояние одной миграции.
+// Unknown отмечает версии, примененные в БД, но отсутствующие в бинарнике.
+type MigrationStatus struct {
+	Version   int64
+	Name      string
+	Applied   bool
+	AppliedAt time.Time
+	Unknown   bool
+}
+
+// Migrator применяет и откатывает версионированные миграции.
+// Каждая миграция выполняется в отдельной транзакции вместе с записью в schema_migrations,
+// а на время работы берется advisory lock, поэтому одновременный запуск нескольких реплик безопасен.
+type Migrator struct {
+	db         *sql.DB
+	migrations []migration
+	logger     *zap.Logger
+}
+
+func NewMigrator(db *sql.DB, source fs.FS, logger *zap.Logger) (*Migrator, error) {
+	migrations, err := parseMigrations(source)
+	if err != nil {
+		return nil, fmt.Errorf("parseMigrations: %w", err)
+	}
+
+	return &Migrator{db: db, migrations: migrations, logger: logger}, nil
+}
+
+// Latest возвращает последнюю известную версию схемы.
+func (m *Migrator) Latest() int64 {
+	if len(m.migrations) == 0 {
+		return 0
+	}
+	return m.migrations[len(m.migrations)-1].version
+}
+
+// Up применяет все непримененные миграции бинарника и ничего не откатывает. Версии, примененные
+// более новым релизом и неизвестные бинарнику, только отмечаются в логе: при постепенном обновлении
+// реплики старого релиза должны запускаться на уже обновленной схеме.
+func (m *Migrator) Up(ctx context.Context) (int, error) {
+	logger := m.logger.With(
+		zap.String("op", "postgres.Migrator.Up"),
+	)
+
+	return m.run(ctx, func(applied map[int64]time.Time) ([]migration, []migration, error) {
+		up, unknown := planUp(m.migrations, applied)
+		if len(unknown) > 0 {
+			logger.Warn("в БД применены миграции, неизвестные этому бинарнику",
+				zap.Int64s("versions", unknown),
+				zap.Int64("latest", m.Latest()),
+			)
+		}
+		return up, nil, nil
+	})
+}
+
+// Down откатывает последние steps примененных миграций.
+func (m *Migrator) Down(ctx context.Context, steps int) (int, error) {
+	if steps <= 0 {
+		return 0, fmt.Errorf("число откатываемых миграций должно быть положительным: %d", steps)
+	}
+
+	return m.run(ctx, func(applied map[int64]time.Time) ([]migration, []migration, error) {
+		down, err := planDown(m.migrations, applied, steps)
+		return nil, down, err
+	})
+}
+
+// To приводит схему к версии version: применяет недостающие миграции до нее включительно
+// и откатывает примененные миграции старше нее. Версия 0 откатывает все миграции.
+func (m *Migrator) To(ctx context.Context, version int64) (int, error) {
+	if version != 0 && !slices.ContainsFunc(m.migrations, func(mg migration) bool { return mg.version == version }) {
+		return 0, fmt.Errorf("неизвестная версия миграции: %d", version)
+	}
+
+	return m.run(ctx, func(applied map[int64]time.Time) ([]migration, []migration, error) {
+		return planTo(m.migrations, applied, version)
+	})
+}
+
+// Status возвращает состояние всех известных и примененных миграций в порядке версий.
+func (m *Migrator) Status(ctx context.Context) ([]MigrationStatus, error) {
+	var applied map[int64]time.Time
+	_, err := m.run(ctx, func(a map[int64]time.Time) ([]migration, []migration, error) {
+		applied = a
+		return nil, nil, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := make([]MigrationStatus, 0, len(m.migrations))
+	known := make(map[int64]bool, len(m.migrations))
+	for _, mg := range m.migrations {
+		known[mg.version] = true
+		at, ok := applied[mg.version]
+		statuses = append(statuses, MigrationStatus{Version: mg.version, Name: mg.name, Applied: ok, AppliedAt: at})
+	}
+	for version, at := range applied {
+		if !known[version] {
+			statuses = append(statuses, MigrationStatus{Version: version, Applied: true, AppliedAt: at, Unknown: true})
+		}
+	}
+	slices.SortFunc(statuses, func(a, b MigrationStatus) int { return cmp.Compare(a.Version, b.Version) })
+
+	return statuses, nil
+}
+
+// run берет advisory lock на отдельном соединении, читает примененные версии,
+// строит план и выполняет его: сначала откаты, затем применения.
+func (m *Migrator) run(ctx context.Context, plan func(applied map[int64]time.Time) (up, down []migration, err error)) (int, error) {
+	logger := m.logger.With(
+		zap.String("op", "postgres.Migrator.run"),
+	)
+
+	conn, err := m.db.Conn(ctx)
+	if err != nil {
+		return 0, wrapErr("db.Conn", err)
+	}
+	defer conn.Close()
+
+	if _, err := conn.ExecContext(ctx, queryMigrationsLock, migrationsLockKey); err != nil {
+		return 0, wrapErr("conn.ExecContext(lock)", err)
+	}
+	defer func() {
+		// Контекст может быть уже отменен, а блокировку нужно снять в любом случае
+		if _, err := conn.ExecContext(context.Background(), queryMigrationsUnlock, migrationsLockKey); err != nil {
+			logger.Warn("не удалось снять блокировку миграций", zap.Error(err))
+		}
+	}()
+
+	if _, err := conn.ExecContext(ctx, queryCreateMigrationsTable); err != nil {
+		return 0, wrapErr("conn.ExecContext(schema_migrations)", err)
+	}
+
+	applied, err := readApplied(ctx, conn)
+	if err != nil {
+		return 0, err
+	}
+
+	up, down, err := plan(applied)
+	if err != nil {
+		return 0, err
+	}
+
+	done := 0
+	for _, mg := range down {
+		if err := m.apply(ctx, conn, mg, false); err != nil {
+			return done, err
+		}
+		done++
+	}
+	for _, mg := range up {
+		if err := m.apply(ctx, conn, mg, true); err != nil {
+			return done, err
+		}
+		done++
+	}
+
+	return done, nil
+}
+
+func (m *Migrator) apply(ctx context.Context, conn *sql.Conn, mg migration, up bool) error {
+	logger := m.logger.With(
+		zap.String("op", "postgres.Migrator.apply"),
+		zap.Int64("version", mg.version),
+		zap.String("name", mg.name),
+		zap.Bool("up", up),
+	)
+
+	script, bookkeeping, args := mg.up, queryInsertMigration, []any{mg.version, mg.name}
+	if !up {
+		script, bookkeeping, args = mg.down, queryDeleteMigration, []any{mg.version}
+	}
+
+	logger.Info("выполнение миграции...")
+
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return wrapErr("conn.BeginTx", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, script); err != nil {
+		logger.Error("ошибка при выполнении миграции", zap.Error(err))
+		return fmt.Errorf("миграция %d_%s: %w", mg.version, mg.name, wrapErr("tx.ExecContext", err))
+	}
+	if _, err := tx.ExecContext(ctx, bookkeeping, args...); err != nil {
+		return wrapErr("tx.ExecContext(schema_migrations)", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return wrapErr("tx.Commit", err)
+	}
+
+	logger.Info("миграция выполнена")
+	return nil
+}
+
+func readApplied(ctx context.Context, conn *sql.Conn) (map[int64]time.Time, error) {
+	rows, err := conn.QueryContext(ctx, queryAppliedMigrations)
+	if err != nil {
+		return nil, wrapErr("conn.QueryContext", err)
+	}
+	defer rows.Close()
+
+	applied := make(map[int64]time.Time)
+	for rows.Next() {
+		var version int64
+		var at time.Time
+		if err := rows.Scan(&version, &at); err != nil {
+			return nil, fmt.Errorf("rows.Scan: %w", err)
+		}
+		applied[version] = at
+	}
+	if err := rows.Err(); err != nil {
+		return nil, wrapErr("rows.Err", err)
+	}
+
+	return applied, nil
+}
+
+// parseMigrations читает пары up/down файлов из корня source и сортирует их по версии.
+// Down-файл необязателен, но без него миграцию нельзя откатить.
+func parseMigrations(source fs.FS) ([]migration, error) {
+	files, err := fs.Glob(source, "*.sql")
+	if err != nil {
+		return nil, fmt.Errorf("fs.Glob: %w", err)
+	}
+
+	byVersion := make(map[int64]*migration)
+	for _, file := range files {
+		match := migrationFileRe.FindStringSubmatch(path.Base(file))
+		if match == nil {
+			return nil, fmt.Errorf("некорректное имя файла миграции: %s", file)
+		}
+
+		version, err := strconv.ParseInt(match[1], 10, 64)
+		if err != nil || version <= 0 {
+			return nil, fmt.Errorf("некорректная версия миграции: %s", file)
+		}
+
+		body, err := fs.ReadFile(source, file)
+		if err != nil {
+			return nil, fmt.Errorf("fs.ReadFile(%s): %w", file, err)
+		}
+
+		mg, ok := byVersion[version]
+		if !ok {
+			mg = &migration{version: version, name: match[2]}
+			byVersion[version] = mg
+		} else if mg.name != match[2] {
+			return nil, fmt.Errorf("версия %d используется миграциями %s и %s", version, mg.name, match[2])
+		}
+
+		if match[3] == "up" {
+			mg.up = string(body)
+		} else {
+			mg.down = string(body)
+		}
+	}
+
+	migrations := make([]migration, 0, len(byVersion))
+	for _, mg := range byVersion {
+		if mg.up == "" {
+			return nil, fmt.Errorf("для миграции %d_%s нет up-файла", mg.version, mg.name)
+		}
+		migrations = append(migrations, *mg)
+	}
+	slices.SortFunc(migrations, func(a, b migration) int { return cmp.Compare(a.version, b.version) })
+
+	return migrations, nil
+}
+
+// planUp возвращает непримененные миграции бинарника и примененные версии, которых в нем нет.
+func planUp(migrations []migration, applied map[int64]time.Time) (up []migration, unknown []int64) {
+	known := make(map[int64]bool, len(migrations))
+	for _, mg := range migrations {
+		known[mg.version] = true
+		if _, ok := applied[mg.version]; !ok {
+			up = append(up, mg)
+		}
+	}
+
+	for version := range applied {
+		if !known[version] {
+			unknown = append(unknown, version)
+		}
+	}
+	slices.Sort(unknown)
+
+	return up, unknown
+}
+
+// planTo строит план приведения схемы к версии target:
+// откат примененных миграций новее target (от новых к старым) и применение недостающих до target включительно.
+func planTo(migrations []migration, applied map[int64]time.Time, target int64) (up, down []migration, err error) {
+	down, err = revertable(migrations, applied, func(version int64) bool { return version > target })
+	if err != nil {
+		return nil, nil, err
+	}
+
+	for _, mg := range migrations {
+		if _, ok := applied[mg.version]; !ok && mg.version <= target {
+			up = append(up, mg)
+		}
+	}
+
+	return up, down, nil
+}
+
+// planDown строит план отката последних steps примененных миграций.
+func planDown(migrations []migration, applied map[int64]time.Time, steps int) ([]migration, error) {
+	versions := make([]int64, 0, len(applied))
+	for version := range applied {
+		versions = append(versions, version)
+	}
+	slices.Sort(versions)
+	if len(versions) > steps {
+		versions = versions[len(versions)-steps:]
+	}
+
+	return revertable(migrations, applied, func(version int64) bool { return slices.Contains(versions, version) })
+}
+
+// revertable возвращает примененные миграции, отобранные selected, в порядке отката.
+func revertable(migrations []migration, applied map[int64]time.Time, selected func(int64) bool) ([]migration, error) {
+	known := make(map[int64]migration, len(migrations))
+	for _, mg := range migrations {
+		known[mg.version] = mg
+	}
+
+	versions := make([]int64, 0, len(applied))
+	for version := range applied {
+		if selected(version) {
+			versions = append(versions, version)
+		}
+	}
+	slices.SortFunc(versions, func(a, b int64) int { return cmp.Compare(b, a) })
+
+	down := make([]migration, 0, len(versions))
+	for _, version := range versions {
+		mg, ok := known[version]
+		if !ok {
+			return nil, fmt.Errorf("примененная миграция %d отсутствует в бинарнике", version)
+		}
+		if mg.down == "" {
+			return nil, fmt.Errorf("для миграции %d_%s нет down-файла", mg.version, mg.name)
+		}
+		down = append(down, mg)
+	}
+
+	return down, nil
+}
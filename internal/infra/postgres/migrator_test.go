@@ -0,0 +1,148 @@
+package postgres
+
+import (
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sunr3d/order-stream-processor/migrations"
+)
+
+func testMigrations() []migration {
+	return []migration{
+		{version: 1, name: "a", up: "up1", down: "down1"},
+		{version: 2, name: "b", up: "up2", down: "down2"},
+		{version: 3, name: "c", up: "up3", down: "down3"},
+	}
+}
+
+func versions(migrations []migration) []int64 {
+	var out []int64
+	for _, mg := range migrations {
+		out = append(out, mg.version)
+	}
+	return out
+}
+
+func applied(versions ...int64) map[int64]time.Time {
+	out := make(map[int64]time.Time, len(versions))
+	for _, v := range versions {
+		out[v] = time.Now()
+	}
+	return out
+}
+
+func TestParseMigrations_Embedded(t *testing.T) {
+	parsed, err := parseMigrations(migrations.FS)
+
+	require.NoError(t, err)
+	require.NotEmpty(t, parsed)
+	for i, mg := range parsed {
+		assert.Equal(t, int64(i+1), mg.version, "версии должны идти подряд")
+		assert.NotEmpty(t, mg.up)
+		assert.NotEmpty(t, mg.down)
+	}
+}
+
+func TestParseMigrations_Sorted(t *testing.T) {
+	source := fstest.MapFS{
+		"0010_later.up.sql":   {Data: []byte("SELECT 10")},
+		"0002_early.up.sql":   {Data: []byte("SELECT 2")},
+		"0002_early.down.sql": {Data: []byte("SELECT -2")},
+	}
+
+	parsed, err := parseMigrations(source)
+
+	require.NoError(t, err)
+	assert.Equal(t, []int64{2, 10}, versions(parsed))
+	assert.Equal(t, "early", parsed[0].name)
+	assert.Equal(t, "SELECT -2", parsed[0].down)
+	assert.Empty(t, parsed[1].down)
+}
+
+func TestParseMigrations_Invalid(t *testing.T) {
+	tests := map[string]fstest.MapFS{
+		"bad name":          {"init.sql": {Data: []byte("SELECT 1")}},
+		"zero version":      {"0000_zero.up.sql": {Data: []byte("SELECT 1")}},
+		"down without up":   {"0001_a.down.sql": {Data: []byte("SELECT 1")}},
+		"duplicate version": {"0001_a.up.sql": {Data: []byte("SELECT 1")}, "0001_b.up.sql": {Data: []byte("SELECT 1")}},
+	}
+
+	for name, source := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := parseMigrations(source)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestPlanUp(t *testing.T) {
+	up, unknown := planUp(testMigrations(), applied(1, 3))
+
+	assert.Equal(t, []int64{2}, versions(up))
+	assert.Empty(t, unknown)
+}
+
+func TestPlanUp_DatabaseAheadOfBinary(t *testing.T) {
+	up, unknown := planUp(testMigrations(), applied(1, 2, 3, 4, 5))
+
+	assert.Empty(t, up, "миграции более нового релиза не откатываются")
+	assert.Equal(t, []int64{4, 5}, unknown)
+
+	up, unknown = planUp(testMigrations(), applied(1, 5))
+	assert.Equal(t, []int64{2, 3}, versions(up), "пропущенные версии бинарника применяются")
+	assert.Equal(t, []int64{5}, unknown)
+}
+
+func TestPlanTo(t *testing.T) {
+	tests := []struct {
+		name     string
+		applied  map[int64]time.Time
+		target   int64
+		wantUp   []int64
+		wantDown []int64
+	}{
+		{name: "с нуля до последней", applied: applied(), target: 3, wantUp: []int64{1, 2, 3}},
+		{name: "догнать", applied: applied(1), target: 3, wantUp: []int64{2, 3}},
+		{name: "пропущенная версия", applied: applied(1, 3), target: 3, wantUp: []int64{2}},
+		{name: "откат до версии", applied: applied(1, 2, 3), target: 1, wantDown: []int64{3, 2}},
+		{name: "откат всех", applied: applied(1, 2, 3), target: 0, wantDown: []int64{3, 2, 1}},
+		{name: "уже на версии", applied: applied(1, 2), target: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			up, down, err := planTo(testMigrations(), tt.applied, tt.target)
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantUp, versions(up))
+			assert.Equal(t, tt.wantDown, versions(down))
+		})
+	}
+}
+
+func TestPlanTo_UnknownApplied(t *testing.T) {
+	_, _, err := planTo(testMigrations(), applied(1, 2, 3, 4), 3)
+	assert.Error(t, err)
+}
+
+func TestPlanDown(t *testing.T) {
+	down, err := planDown(testMigrations(), applied(1, 2, 3), 2)
+	require.NoError(t, err)
+	assert.Equal(t, []int64{3, 2}, versions(down))
+
+	down, err = planDown(testMigrations(), applied(1), 5)
+	require.NoError(t, err)
+	assert.Equal(t, []int64{1}, versions(down))
+}
+
+func TestPlanDown_NoDownFile(t *testing.T) {
+	migrations := testMigrations()
+	migrations[2].down = ""
+
+	_, err := planDown(migrations, applied(1, 2, 3), 1)
+	assert.Error(t, err)
+}
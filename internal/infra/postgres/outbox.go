@@ -0,0 +1,195 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/lib/pq"
+	"go.uber.org/zap"
+
+	"github.com/sunr3d/order-stream-processor/internal/interfaces/infra"
+	"github.com/sunr3d/order-stream-processor/internal/metrics"
+	"github.com/sunr3d/order-stream-processor/models"
+)
+
+// outboxLockKey — ключ advisory lock, который удерживает единственный активный relay среди реплик,
+// чтобы события одного заказа публиковались в порядке записи.
+const outboxLockKey = 7_406_001
+
+const (
+	queryInsertOutbox      = `INSERT INTO outbox (event_type, aggregate_id, payload) VALUES ($1, $2, $3)`
+	queryInsertOutboxBatch = `INSERT INTO outbox (event_type, aggregate_id, payload) VALUES `
+	queryLockOutbox        = `SELECT pg_try_advisory_lock($1)`
+	queryUnlockOutbox      = `SELECT pg_advisory_unlock($1)`
+	querySelectOutbox      = `SELECT id, event_type, aggregate_id, payload, created_at FROM outbox
+		WHERE published_at IS NULL ORDER BY id LIMIT $1`
+	queryMarkOutbox   = `UPDATE outbox SET published_at = now() WHERE id = ANY($1)`
+	queryDeleteOutbox = `DELETE FROM outbox WHERE published_at IS NOT NULL AND published_at < $1`
+)
+
+var _ infra.Outbox = (*postgresRepo)(nil)
+
+// insertOutbox записывает событие в outbox в рамках транзакции изменения заказа.
+func insertOutbox(ctx context.Context, tx *sql.Tx, eventType, orderUID string, payload []byte) error {
+	if _, err := tx.ExecContext(ctx, queryInsertOutbox, eventType, orderUID, payload); err != nil {
+		return wrapErr("tx.ExecContext(outbox)", err)
+	}
+	return nil
+}
+
+// insertOutboxBatch записывает события created для пачки заказов одним запросом.
+func insertOutboxBatch(ctx context.Context, tx *sql.Tx, orders []*models.Order) error {
+	rows := make([][]any, 0, len(orders))
+	for _, order := range orders {
+		data, err := json.Marshal(order)
+		if err != nil {
+			return fmt.Errorf("json.Marshal: %w", err)
+		}
+		rows = append(rows, []any{models.EventOrderCreated, order.OrderUID, data})
+	}
+
+	if err := execBulkInsert(ctx, tx, queryInsertOutboxBatch, rows); err != nil {
+		return wrapErr("tx.ExecContext(outbox)", err)
+	}
+	return nil
+}
+
+// ProcessOutbox держит advisory lock на отдельном соединении без транзакции: публикация в Kafka
+// не удерживает блокировок строк outbox и не мешает записи заказов.
+func (r *postgresRepo) ProcessOutbox(ctx context.Context, limit int, publish func(context.Context, models.OrderEvent) error) (int, error) {
+	defer metrics.QueryTimer("process_outbox").ObserveDuration()
+
+	logger := r.logger.With(
+		zap.String("op", "postgres.ProcessOutbox"),
+	)
+
+	conn, err := r.db.Conn(ctx)
+	if err != nil {
+		logger.Error("ошибка при получении соединения", zap.Error(err))
+		return 0, wrapErr("db.Conn", err)
+	}
+	defer conn.Close()
+
+	var locked bool
+	if err := conn.QueryRowContext(ctx, queryLockOutbox, outboxLockKey).Scan(&locked); err != nil {
+		logger.Error("ошибка при захвате блокировки outbox", zap.Error(err))
+		return 0, wrapErr("conn.QueryRowContext", err)
+	}
+	if !locked {
+		logger.Debug("outbox обрабатывается другой репликой")
+		return 0, nil
+	}
+	defer func() {
+		// Контекст может быть уже отменен, а блокировку нужно снять в любом случае
+		if _, err := conn.ExecContext(context.Background(), queryUnlockOutbox, outboxLockKey); err != nil {
+			logger.Warn("не удалось снять блокировку outbox", zap.Error(err))
+		}
+	}()
+
+	events, err := selectOutbox(ctx, conn, limit)
+	if err != nil {
+		logger.Error("ошибка при чтении outbox", zap.Error(err))
+		return 0, err
+	}
+	if len(events) == 0 {
+		return 0, nil
+	}
+
+	published, publishErr := publishEvents(ctx, events, publish)
+
+	// События помечаются только после публикации: при падении между ними они будут опубликованы повторно
+	if len(published) > 0 {
+		if _, err := conn.ExecContext(ctx, queryMarkOutbox, pq.Array(published)); err != nil {
+			logger.Error("ошибка при отметке опубликованных событий", zap.Error(err))
+			return 0, wrapErr("conn.ExecContext", err)
+		}
+	}
+
+	if publishErr != nil {
+		logger.Warn("публикация событий прервана",
+			zap.Int("published", len(published)),
+			zap.Int("pending", len(events)-len(published)),
+			zap.Error(publishErr),
+		)
+		return len(published), fmt.Errorf("publish: %w", publishErr)
+	}
+
+	logger.Info("события outbox опубликованы", zap.Int("count", len(published)))
+	return len(published), nil
+}
+
+// DeletePublishedOutbox удаляет события, опубликованные раньше before.
+func (r *postgresRepo) DeletePublishedOutbox(ctx context.Context, before time.Time) (int64, error) {
+	defer metrics.QueryTimer("delete_published_outbox").ObserveDuration()
+
+	logger := r.logger.With(
+		zap.String("op", "postgres.DeletePublishedOutbox"),
+		zap.Time("before", before),
+	)
+
+	res, err := r.db.ExecContext(ctx, queryDeleteOutbox, before)
+	if err != nil {
+		logger.Error("ошибка при удалении опубликованных событий", zap.Error(err))
+		return 0, wrapErr("db.ExecContext", err)
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return 0, wrapErr("res.RowsAffected", err)
+	}
+
+	if n > 0 {
+		logger.Info("опубликованные события outbox удалены", zap.Int64("count", n))
+	}
+	return n, nil
+}
+
+// publishEvents передает события publish по порядку до первой ошибки и возвращает id опубликованных.
+func publishEvents(ctx context.Context, events []models.OrderEvent, publish func(context.Context, models.OrderEvent) error) ([]int64, error) {
+	published := make([]int64, 0, len(events))
+	for _, ev := range events {
+		if err := publish(ctx, ev); err != nil {
+			return published, err
+		}
+		published = append(published, ev.ID)
+	}
+	return published, nil
+}
+
+func selectOutbox(ctx context.Context, q querier, limit int) ([]models.OrderEvent, error) {
+	rows, err := q.QueryContext(ctx, querySelectOutbox, limit)
+	if err != nil {
+		return nil, wrapErr("QueryContext", err)
+	}
+	defer rows.Close()
+
+	var events []models.OrderEvent
+	for rows.Next() {
+		var (
+			ev      models.OrderEvent
+			payload []byte
+		)
+		if err := rows.Scan(&ev.ID, &ev.Type, &ev.OrderUID, &payload, &ev.OccurredAt); err != nil {
+			return nil, wrapErr("rows.Scan", err)
+		}
+
+		if len(payload) > 0 && string(payload) != "null" {
+			var order models.Order
+			if err := json.Unmarshal(payload, &order); err != nil {
+				return nil, fmt.Errorf("json.Unmarshal: %w", err)
+			}
+			ev.Order = &order
+		}
+
+		events = append(events, ev)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, wrapErr("rows.Err", err)
+	}
+
+	return events, nil
+}
@@ -0,0 +1,67 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sunr3d/order-stream-processor/models"
+)
+
+func TestPublishEvents(t *testing.T) {
+	events := []models.OrderEvent{{ID: 1}, {ID: 2}, {ID: 3}}
+
+	var sent []int64
+	published, err := publishEvents(context.Background(), events, func(_ context.Context, ev models.OrderEvent) error {
+		sent = append(sent, ev.ID)
+		return nil
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, []int64{1, 2, 3}, published)
+	assert.Equal(t, []int64{1, 2, 3}, sent)
+}
+
+func TestPublishEvents_StopsOnError(t *testing.T) {
+	events := []models.OrderEvent{{ID: 1}, {ID: 2}, {ID: 3}}
+	kafkaErr := errors.New("kafka недоступна")
+
+	var sent []int64
+	published, err := publishEvents(context.Background(), events, func(_ context.Context, ev models.OrderEvent) error {
+		sent = append(sent, ev.ID)
+		if ev.ID == 2 {
+			return kafkaErr
+		}
+		return nil
+	})
+
+	assert.ErrorIs(t, err, kafkaErr)
+	assert.Equal(t, []int64{1}, published, "отмечаются только события до первой ошибки")
+	assert.Equal(t, []int64{1, 2}, sent, "после ошибки события не отправляются, чтобы не нарушить порядок")
+}
+
+func TestStatusChangeStatements(t *testing.T) {
+	order := &models.Order{OrderUID: "test-123", Status: models.StatusPaid, Version: 3}
+	change := models.StatusChange{OrderUID: "test-123", To: models.StatusPaid, ChangedBy: "operator", Reason: "оплата"}
+
+	stmts, err := statusChangeStatements(order, models.StatusCreated, change)
+	require.NoError(t, err)
+	require.Len(t, stmts, 2)
+
+	assert.Equal(t, queryInsertHistory, stmts[0].query)
+	assert.Equal(t, []any{"test-123", models.StatusCreated, models.StatusPaid, "operator", "оплата"}, stmts[0].args)
+
+	assert.Equal(t, queryInsertOutbox, stmts[1].query, "событие пишется в outbox той же транзакцией")
+	require.Len(t, stmts[1].args, 3)
+	assert.Equal(t, models.EventOrderStatusChanged, stmts[1].args[0])
+	assert.Equal(t, "test-123", stmts[1].args[1])
+
+	var payload models.Order
+	require.NoError(t, json.Unmarshal(stmts[1].args[2].([]byte), &payload))
+	assert.Equal(t, models.StatusPaid, payload.Status)
+	assert.Equal(t, int64(3), payload.Version)
+}
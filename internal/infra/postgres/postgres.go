@@ -3,39 +3,52 @@ package postgres
 import (
 	"context"
 	"database/sql"
-	"encoding/json"
 	"errors"
 	"fmt"
-	"strings"
+	"slices"
 
 	_ "github.com/lib/pq"
 	"go.uber.org/zap"
 
 	"github.com/sunr3d/order-stream-processor/internal/config"
 	"github.com/sunr3d/order-stream-processor/internal/interfaces/infra"
+	"github.com/sunr3d/order-stream-processor/internal/metrics"
 	"github.com/sunr3d/order-stream-processor/models"
 )
 
-const (
-	queryCreate  = `INSERT INTO orders (order_uid, data) VALUES ($1, $2)`
-	queryRead    = `SELECT data FROM orders WHERE order_uid = $1`
-	queryReadAll = `SELECT data FROM orders ORDER BY order_uid`
-)
-
 var _ infra.Database = (*postgresRepo)(nil)
+var _ infra.DBStatsProvider = (*postgresRepo)(nil)
+var _ infra.HealthChecker = (*postgresRepo)(nil)
 
 type postgresRepo struct {
 	db     *sql.DB
+	store  orderStore
 	logger *zap.Logger
 }
 
 func New(cfg config.PostgresConfig, log *zap.Logger) (infra.Database, error) {
-	dsn := fmt.Sprintf(
-		"host=%s port=%s user=%s password=%s dbname=%s sslmode=%s",
-		cfg.Host, cfg.Port, cfg.User, cfg.Password, cfg.DBName, cfg.SSLMode,
-	)
+	store, err := newStore(cfg.Storage)
+	if err != nil {
+		return nil, fmt.Errorf("newStore: %w", err)
+	}
 
-	db, err := sql.Open("postgres", dsn)
+	db, err := Open(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	log.Info("соединение с PostgreSQL установлено", zap.String("storage", cfg.Storage))
+
+	return &postgresRepo{
+		db:     db,
+		store:  store,
+		logger: log,
+	}, nil
+}
+
+// Open открывает пул соединений и проверяет доступность БД.
+func Open(cfg config.PostgresConfig) (*sql.DB, error) {
+	db, err := sql.Open("postgres", dsn(cfg))
 	if err != nil {
 		return nil, fmt.Errorf("sql.Open: %w", err)
 	}
@@ -44,22 +57,40 @@ func New(cfg config.PostgresConfig, log *zap.Logger) (infra.Database, error) {
 	defer cancel()
 
 	if err := db.PingContext(ctx); err != nil {
+		db.Close()
 		return nil, fmt.Errorf("db.PingContext: %w", err)
 	}
 
-	log.Info("соединение с PostgreSQL установлено")
+	return db, nil
+}
 
-	return &postgresRepo{
-		db:     db,
-		logger: log,
-	}, nil
+func dsn(cfg config.PostgresConfig) string {
+	return fmt.Sprintf(
+		"host=%s port=%s user=%s password=%s dbname=%s sslmode=%s",
+		cfg.Host, cfg.Port, cfg.User, cfg.Password, cfg.DBName, cfg.SSLMode,
+	)
 }
 
 func (r *postgresRepo) Close() error {
 	return r.db.Close()
 }
 
+// HealthCheck проверяет доступность БД.
+func (r *postgresRepo) HealthCheck(ctx context.Context) error {
+	if err := r.db.PingContext(ctx); err != nil {
+		return wrapErr("db.PingContext", err)
+	}
+	return nil
+}
+
+// Stats возвращает статистику пула соединений.
+func (r *postgresRepo) Stats() sql.DBStats {
+	return r.db.Stats()
+}
+
 func (r *postgresRepo) Create(ctx context.Context, order *models.Order) error {
+	defer metrics.QueryTimer("create").ObserveDuration()
+
 	logger := r.logger.With(
 		zap.String("op", "postgres.Create"),
 		zap.String("order_uid", order.OrderUID),
@@ -70,31 +101,159 @@ func (r *postgresRepo) Create(ctx context.Context, order *models.Order) error {
 	tx, err := r.db.BeginTx(ctx, nil)
 	if err != nil {
 		logger.Error("ошибка при создании транзакции", zap.Error(err))
-		return fmt.Errorf("db.BeginTx: %w", err)
+		return wrapErr("db.BeginTx", err)
 	}
 	defer tx.Rollback()
 
-	data, err := json.Marshal(order)
+	// Присланная клиентом версия игнорируется: иначе новый заказ обходил бы проверку If-Match
+	// и сравнение версий при инвалидации кэша
+	order.Version = 1
+
+	erased, err := erasedOrders(ctx, tx, []string{order.OrderUID})
 	if err != nil {
-		logger.Error("ошибка при маршалинге заказа", zap.Error(err))
-		return fmt.Errorf("json.Marshal: %w", err)
+		logger.Error("ошибка при проверке журнала аудита", zap.Error(err))
+		return err
+	}
+	if erased[order.OrderUID] {
+		logger.Warn("заказ был удален или обезличен, повторное создание отклонено")
+		return erasedErr(order.OrderUID)
 	}
 
-	_, err = tx.ExecContext(ctx, queryCreate, order.OrderUID, data)
-	if err != nil {
-		if strings.Contains(err.Error(), "duplicate key") {
+	if err := r.store.insert(ctx, tx, order); err != nil {
+		if isUniqueViolation(err) {
 			logger.Info("заказ уже существует в БД")
-			return fmt.Errorf("заказ уже существует в БД: %s", order.OrderUID)
+			return fmt.Errorf("%w в БД: %s", models.ErrOrderAlreadyExists, order.OrderUID)
 		}
 		logger.Error("ошибка при сохранении заказа в БД", zap.Error(err))
-		return fmt.Errorf("db.ExecContext: %w", err)
+		return wrapErr("store.insert", err)
+	}
+
+	if err := insertCreatedHistory(ctx, tx, []*models.Order{order}); err != nil {
+		logger.Error("ошибка при записи истории статусов", zap.Error(err))
+		return err
+	}
+
+	if err := r.writeEvent(ctx, tx, models.EventOrderCreated, order); err != nil {
+		logger.Error("ошибка при записи события в outbox", zap.Error(err))
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		logger.Error("ошибка при фиксации транзакции", zap.Error(err))
+		return wrapErr("tx.Commit", err)
 	}
 
 	logger.Info("заказ успешно сохранен в БД")
-	return tx.Commit()
+	return nil
+}
+
+// CreateBatch сохраняет пачку заказов одной транзакцией многострочными INSERT.
+// Уже существующие заказы (и повторы order_uid внутри пачки) пропускаются
+// и получают models.ErrOrderAlreadyExists, найденные в журнале аудита — models.ErrOrderErased,
+// остальные сохраняются вместе с начальной записью истории
+// статусов и событиями outbox.
+func (r *postgresRepo) CreateBatch(ctx context.Context, orders []*models.Order) ([]error, error) {
+	defer metrics.QueryTimer("create_batch").ObserveDuration()
+
+	logger := r.logger.With(
+		zap.String("op", "postgres.CreateBatch"),
+		zap.Int("count", len(orders)),
+	)
+
+	if len(orders) == 0 {
+		return []error{}, nil
+	}
+
+	logger.Info("сохранение пачки заказов в БД...")
+
+	unique := make([]*models.Order, 0, len(orders))
+	seen := make(map[string]bool, len(orders))
+	for _, order := range orders {
+		order.Version = 1
+		if !seen[order.OrderUID] {
+			seen[order.OrderUID] = true
+			unique = append(unique, order)
+		}
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		logger.Error("ошибка при создании транзакции", zap.Error(err))
+		return nil, wrapErr("db.BeginTx", err)
+	}
+	defer tx.Rollback()
+
+	uids := make([]string, len(unique))
+	for i, order := range unique {
+		uids[i] = order.OrderUID
+	}
+	erased, err := erasedOrders(ctx, tx, uids)
+	if err != nil {
+		logger.Error("ошибка при проверке журнала аудита", zap.Error(err))
+		return nil, err
+	}
+	if len(erased) > 0 {
+		logger.Warn("заказы пачки были удалены или обезличены, повторное создание отклонено",
+			zap.Int("erased", len(erased)))
+		unique = slices.DeleteFunc(unique, func(order *models.Order) bool { return erased[order.OrderUID] })
+	}
+
+	inserted, err := r.store.insertBatch(ctx, tx, unique)
+	if err != nil {
+		logger.Error("ошибка при сохранении пачки заказов в БД", zap.Error(err))
+		return nil, wrapErr("store.insertBatch", err)
+	}
+
+	fresh := make([]*models.Order, 0, len(inserted))
+	for _, order := range unique {
+		if inserted[order.OrderUID] {
+			fresh = append(fresh, order)
+		}
+	}
+
+	if err := insertCreatedHistory(ctx, tx, fresh); err != nil {
+		logger.Error("ошибка при записи истории статусов", zap.Error(err))
+		return nil, err
+	}
+
+	if err := insertOutboxBatch(ctx, tx, fresh); err != nil {
+		logger.Error("ошибка при записи событий в outbox", zap.Error(err))
+		return nil, fmt.Errorf("insertOutboxBatch: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		logger.Error("ошибка при фиксации транзакции", zap.Error(err))
+		return nil, wrapErr("tx.Commit", err)
+	}
+
+	logger.Info("пачка заказов сохранена в БД",
+		zap.Int("inserted", len(fresh)),
+		zap.Int("skipped", len(orders)-len(fresh)),
+	)
+	return batchResults(orders, inserted, erased), nil
+}
+
+// batchResults сопоставляет заказам пачки итог сохранения. Каждый order_uid засчитывается вставленным
+// только для первого вхождения в пачку, удаленные и обезличенные заказы получают models.ErrOrderErased,
+// остальные — models.ErrOrderAlreadyExists.
+func batchResults(orders []*models.Order, inserted, erased map[string]bool) []error {
+	results := make([]error, len(orders))
+	for i, order := range orders {
+		switch {
+		case erased[order.OrderUID]:
+			results[i] = erasedErr(order.OrderUID)
+		case inserted[order.OrderUID]:
+			delete(inserted, order.OrderUID)
+		default:
+			results[i] = fmt.Errorf("%w в БД: %s", models.ErrOrderAlreadyExists, order.OrderUID)
+		}
+	}
+	return results
 }
 
 func (r *postgresRepo) Read(ctx context.Context, orderUID string) (*models.Order, error) {
+	defer metrics.QueryTimer("read").ObserveDuration()
+
 	logger := r.logger.With(
 		zap.String("op", "postgres.Read"),
 		zap.String("order_uid", orderUID),
@@ -102,61 +261,33 @@ func (r *postgresRepo) Read(ctx context.Context, orderUID string) (*models.Order
 
 	logger.Info("поиск заказа в БД...")
 
-	var data []byte
-	err := r.db.QueryRowContext(ctx, queryRead, orderUID).Scan(&data)
+	order, err := r.store.read(ctx, r.db, orderUID, false)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			logger.Info("заказ не найден")
-			return nil, fmt.Errorf("заказ не найден: %s", orderUID)
+			return nil, fmt.Errorf("%w: %s", models.ErrOrderNotFound, orderUID)
 		}
 		logger.Error("ошибка чтения из БД", zap.Error(err))
-		return nil, fmt.Errorf("db.QueryRowContext: %w", err)
-	}
-
-	var order models.Order
-	if err := json.Unmarshal(data, &order); err != nil {
-		logger.Error("ошибка при парсинге заказа", zap.Error(err))
-		return nil, fmt.Errorf("json.Unmarshal: %w", err)
+		return nil, wrapErr("store.read", err)
 	}
 
 	logger.Info("заказ успешно найден в БД")
-	return &order, nil
+	return order, nil
 }
 
 func (r *postgresRepo) ReadAll(ctx context.Context) ([]*models.Order, error) {
+	defer metrics.QueryTimer("read_all").ObserveDuration()
+
 	logger := r.logger.With(
 		zap.String("op", "postgres.ReadAll"),
 	)
 
 	logger.Info("получение всех заказов из БД...")
 
-	rows, err := r.db.QueryContext(ctx, queryReadAll)
+	orders, err := r.store.readAll(ctx, r.db)
 	if err != nil {
 		logger.Error("ошибка при получении всех заказов из БД", zap.Error(err))
-		return nil, fmt.Errorf("db.QueryContext: %w", err)
-	}
-	defer rows.Close()
-
-	var orders []*models.Order
-	for rows.Next() {
-		var data []byte
-		if err := rows.Scan(&data); err != nil {
-			logger.Error("ошибка при записи строки из БД", zap.Error(err))
-			return nil, fmt.Errorf("rows.Scan: %w", err)
-		}
-
-		var order models.Order
-		if err := json.Unmarshal(data, &order); err != nil {
-			logger.Error("ошибка при парсинге заказа", zap.Error(err))
-			return nil, fmt.Errorf("json.Unmarshal: %w", err)
-		}
-
-		orders = append(orders, &order)
-	}
-
-	if err := rows.Err(); err != nil {
-		logger.Error("произошла ошибка во время чтения строк из БД", zap.Error(err))
-		return nil, fmt.Errorf("rows.Err: %w", err)
+		return nil, wrapErr("store.readAll", err)
 	}
 
 	logger.Info("все заказы успешно получены из БД", zap.Int("count", len(orders)))
@@ -0,0 +1,190 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"go.uber.org/zap"
+
+	"github.com/sunr3d/order-stream-processor/internal/metrics"
+	"github.com/sunr3d/order-stream-processor/models"
+)
+
+const (
+	queryInsertHistory = `INSERT INTO order_status_history (order_uid, from_status, to_status, changed_by, reason)
+		VALUES ($1, $2, $3, $4, $5)`
+	queryInsertHistoryBatch = `INSERT INTO order_status_history (order_uid, from_status, to_status, changed_by, reason) VALUES `
+	queryReadHistory        = `SELECT order_uid, from_status, to_status, changed_by, reason, changed_at
+		FROM order_status_history WHERE order_uid = $1 ORDER BY changed_at, id`
+)
+
+// historyCreatedBy — инициатор начальной записи истории, которую пишет создание заказа.
+const historyCreatedBy = "system"
+
+// UpdateStatus меняет статус заказа по правилам жизненного цикла и записывает переход в историю
+// в одной транзакции. Строка заказа блокируется, поэтому конкурентные переходы выполняются по очереди.
+func (r *postgresRepo) UpdateStatus(ctx context.Context, change models.StatusChange) (*models.Order, error) {
+	defer metrics.QueryTimer("update_status").ObserveDuration()
+
+	logger := r.logger.With(
+		zap.String("op", "postgres.UpdateStatus"),
+		zap.String("order_uid", change.OrderUID),
+		zap.String("to", string(change.To)),
+	)
+
+	logger.Info("смена статуса заказа в БД...")
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		logger.Error("ошибка при создании транзакции", zap.Error(err))
+		return nil, wrapErr("db.BeginTx", err)
+	}
+	defer tx.Rollback()
+
+	order, err := r.store.read(ctx, tx, change.OrderUID, true)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			logger.Info("заказ не найден")
+			return nil, fmt.Errorf("%w: %s", models.ErrOrderNotFound, change.OrderUID)
+		}
+		logger.Error("ошибка чтения из БД", zap.Error(err))
+		return nil, wrapErr("store.read", err)
+	}
+
+	from := order.CurrentStatus()
+	changed, err := applyStatusChange(order, change)
+	if err != nil {
+		logger.Info("недопустимая смена статуса", zap.String("from", string(from)))
+		return nil, err
+	}
+	if !changed {
+		logger.Info("заказ уже в целевом статусе, смена статуса пропущена")
+		return order, nil
+	}
+
+	if err := r.store.updateStatus(ctx, tx, order); err != nil {
+		logger.Error("ошибка при обновлении заказа в БД", zap.Error(err))
+		return nil, wrapErr("store.updateStatus", err)
+	}
+
+	stmts, err := statusChangeStatements(order, from, change)
+	if err != nil {
+		logger.Error("ошибка при подготовке истории и события outbox", zap.Error(err))
+		return nil, err
+	}
+	for _, stmt := range stmts {
+		if _, err := tx.ExecContext(ctx, stmt.query, stmt.args...); err != nil {
+			logger.Error("ошибка при записи истории статусов и события outbox", zap.Error(err))
+			return nil, wrapErr("tx.ExecContext", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		logger.Error("ошибка при фиксации транзакции", zap.Error(err))
+		return nil, wrapErr("tx.Commit", err)
+	}
+
+	logger.Info("статус заказа успешно изменен", zap.String("from", string(from)))
+	return order, nil
+}
+
+// applyStatusChange переводит заказ в change.To и увеличивает версию. Возвращает false без ошибки,
+// если заказ уже в целевом статусе.
+func applyStatusChange(order *models.Order, change models.StatusChange) (bool, error) {
+	from := order.CurrentStatus()
+	if from == change.To {
+		return false, nil
+	}
+	if !from.CanTransitionTo(change.To) {
+		return false, fmt.Errorf("%w: %s -> %s", models.ErrInvalidTransition, from, change.To)
+	}
+	order.Status = change.To
+	order.Version++
+	return true, nil
+}
+
+// statement — запрос с аргументами для выполнения в транзакции.
+type statement struct {
+	query string
+	args  []any
+}
+
+// statusChangeStatements возвращает запись истории и событие outbox для перехода заказа из from в его текущий статус.
+func statusChangeStatements(order *models.Order, from models.OrderStatus, change models.StatusChange) ([]statement, error) {
+	data, err := json.Marshal(order)
+	if err != nil {
+		return nil, fmt.Errorf("json.Marshal: %w", err)
+	}
+
+	return []statement{
+		{query: queryInsertHistory, args: []any{order.OrderUID, from, order.Status, change.ChangedBy, change.Reason}},
+		{query: queryInsertOutbox, args: []any{models.EventOrderStatusChanged, order.OrderUID, data}},
+	}, nil
+}
+
+// insertCreatedHistory записывает для новых заказов начальную запись истории — переход в их текущий статус.
+func insertCreatedHistory(ctx context.Context, tx *sql.Tx, orders []*models.Order) error {
+	if err := execBulkInsert(ctx, tx, queryInsertHistoryBatch, createdHistoryRows(orders)); err != nil {
+		return wrapErr("tx.ExecContext(history)", err)
+	}
+	return nil
+}
+
+func createdHistoryRows(orders []*models.Order) [][]any {
+	rows := make([][]any, 0, len(orders))
+	for _, order := range orders {
+		rows = append(rows, []any{order.OrderUID, "", order.CurrentStatus(), historyCreatedBy, ""})
+	}
+	return rows
+}
+
+func (r *postgresRepo) StatusHistory(ctx context.Context, orderUID string) ([]models.StatusChange, error) {
+	defer metrics.QueryTimer("status_history").ObserveDuration()
+
+	logger := r.logger.With(
+		zap.String("op", "postgres.StatusHistory"),
+		zap.String("order_uid", orderUID),
+	)
+
+	logger.Info("получение истории статусов заказа из БД...")
+
+	rows, err := r.db.QueryContext(ctx, queryReadHistory, orderUID)
+	if err != nil {
+		logger.Error("ошибка при получении истории статусов из БД", zap.Error(err))
+		return nil, wrapErr("db.QueryContext", err)
+	}
+	defer rows.Close()
+
+	history := []models.StatusChange{}
+	for rows.Next() {
+		var c models.StatusChange
+		if err := rows.Scan(&c.OrderUID, &c.From, &c.To, &c.ChangedBy, &c.Reason, &c.ChangedAt); err != nil {
+			logger.Error("ошибка при записи строки из БД", zap.Error(err))
+			return nil, wrapErr("rows.Scan", err)
+		}
+		history = append(history, c)
+	}
+
+	if err := rows.Err(); err != nil {
+		logger.Error("произошла ошибка во время чтения строк из БД", zap.Error(err))
+		return nil, wrapErr("rows.Err", err)
+	}
+
+	if len(history) == 0 {
+		exists, err := r.store.exists(ctx, r.db, orderUID)
+		if err != nil {
+			logger.Error("ошибка чтения из БД", zap.Error(err))
+			return nil, wrapErr("store.exists", err)
+		}
+		if !exists {
+			logger.Info("заказ не найден")
+			return nil, fmt.Errorf("%w: %s", models.ErrOrderNotFound, orderUID)
+		}
+	}
+
+	logger.Info("история статусов успешно получена", zap.Int("count", len(history)))
+	return history, nil
+}
@@ -0,0 +1,54 @@
+package postgres
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sunr3d/order-stream-processor/models"
+)
+
+func TestApplyStatusChange(t *testing.T) {
+	order := &models.Order{OrderUID: "test-123", Version: 2}
+
+	changed, err := applyStatusChange(order, models.StatusChange{To: models.StatusPaid})
+	require.NoError(t, err)
+	assert.True(t, changed)
+	assert.Equal(t, models.StatusPaid, order.Status)
+	assert.Equal(t, int64(3), order.Version)
+}
+
+func TestApplyStatusChange_SameStatus(t *testing.T) {
+	order := &models.Order{OrderUID: "test-123", Status: models.StatusPaid, Version: 3}
+
+	changed, err := applyStatusChange(order, models.StatusChange{To: models.StatusPaid})
+	require.NoError(t, err)
+	assert.False(t, changed, "повтор уже выполненной смены статуса ничего не меняет")
+	assert.Equal(t, int64(3), order.Version)
+
+	changed, err = applyStatusChange(&models.Order{}, models.StatusChange{To: models.StatusCreated})
+	require.NoError(t, err)
+	assert.False(t, changed, "заказ без статуса считается созданным")
+}
+
+func TestApplyStatusChange_Invalid(t *testing.T) {
+	order := &models.Order{OrderUID: "test-123", Status: models.StatusCancelled, Version: 3}
+
+	_, err := applyStatusChange(order, models.StatusChange{To: models.StatusPaid})
+	assert.ErrorIs(t, err, models.ErrInvalidTransition)
+	assert.Equal(t, models.StatusCancelled, order.Status)
+	assert.Equal(t, int64(3), order.Version)
+}
+
+func TestCreatedHistoryRows(t *testing.T) {
+	rows := createdHistoryRows([]*models.Order{{OrderUID: "a"}, {OrderUID: "b", Status: models.StatusCreated}})
+
+	assert.Equal(t, [][]any{
+		{"a", "", models.StatusCreated, historyCreatedBy, ""},
+		{"b", "", models.StatusCreated, historyCreatedBy, ""},
+	}, rows)
+
+	query, _ := buildBulkInsert(queryInsertHistoryBatch, "", rows)
+	assert.Equal(t, queryInsertHistoryBatch+"($1, $2, $3, $4, $5), ($6, $7, $8, $9, $10)", query)
+}
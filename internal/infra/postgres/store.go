@@ -0,0 +1,61 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/sunr3d/order-stream-processor/models"
+)
+
+// Режимы хранения заказов
+const (
+	StorageJSONB      = "jsonb"
+	StorageNormalized = "normalized"
+)
+
+// querier — общие методы *sql.DB и *sql.Tx.
+type querier interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+// orderStore — способ хранения заказов в БД. Логирование и перевод ошибок в доменные
+// выполняет postgresRepo, хранилище возвращает ошибки драйвера как есть
+// (в том числе sql.ErrNoRows, если заказ не найден).
+type orderStore interface {
+	insert(ctx context.Context, tx *sql.Tx, order *models.Order) error
+	// insertBatch вставляет заказы (order_uid в пачке уникальны), пропуская уже существующие в БД,
+	// и возвращает множество order_uid вставленных заказов.
+	insertBatch(ctx context.Context, tx *sql.Tx, orders []*models.Order) (map[string]bool, error)
+	read(ctx context.Context, q querier, orderUID string, forUpdate bool) (*models.Order, error)
+	readAll(ctx context.Context, q querier) ([]*models.Order, error)
+	// changedSince возвращает до limit заказов с order_uid больше after, измененных начиная с since,
+	// по возрастанию order_uid.
+	changedSince(ctx context.Context, q querier, since time.Time, after string, limit int) ([]*models.Order, error)
+	// list возвращает до filter.Limit+1 заказов, лишний заказ означает наличие следующей страницы.
+	list(ctx context.Context, q querier, filter models.OrderFilter) ([]*models.Order, error)
+	// lookup возвращает до lookup.Limit заказов по вторичному ключу, от новых к старым.
+	lookup(ctx context.Context, q querier, lookup models.OrderLookup) ([]*models.Order, error)
+	// replace заменяет заказ целиком, включая статус и версию.
+	replace(ctx context.Context, tx *sql.Tx, order *models.Order) error
+	updateStatus(ctx context.Context, tx *sql.Tx, order *models.Order) error
+	// delete удаляет заказ вместе со всеми его данными.
+	delete(ctx context.Context, tx *sql.Tx, orderUID string) error
+	// customerOrders блокирует заказы покупателя и возвращает их order_uid по возрастанию.
+	customerOrders(ctx context.Context, tx *sql.Tx, customerID string) ([]string, error)
+	exists(ctx context.Context, q querier, orderUID string) (bool, error)
+}
+
+func newStore(mode string) (orderStore, error) {
+	switch mode {
+	case StorageJSONB, "":
+		return jsonbStore{}, nil
+	case StorageNormalized:
+		return normalizedStore{}, nil
+	default:
+		return nil, fmt.Errorf("неизвестный режим хранения: %s", mode)
+	}
+}
@@ -0,0 +1,215 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/sunr3d/order-stream-processor/models"
+)
+
+// jsonbDocument — заказ из строки orders: документ data с версией из колонки version.
+const jsonbDocument = `data || jsonb_build_object('version', version)`
+
+const (
+	queryCreate        = `INSERT INTO orders (order_uid, data, version) VALUES ($1, $2, $3)`
+	queryCreateBatch   = `INSERT INTO orders (order_uid, data, version) VALUES `
+	queryRead          = `SELECT ` + jsonbDocument + ` FROM orders WHERE order_uid = $1`
+	queryReadForUpdate = `SELECT ` + jsonbDocument + ` FROM orders WHERE order_uid = $1 FOR UPDATE`
+	queryReadAll       = `SELECT ` + jsonbDocument + ` FROM orders ORDER BY order_uid`
+	queryUpdateData    = `UPDATE orders SET data = $2, version = $3, updated_at = now() WHERE order_uid = $1`
+	queryChangedSince  = `SELECT ` + jsonbDocument + ` FROM orders WHERE updated_at >= $1 AND order_uid > $2
+		ORDER BY order_uid LIMIT $3`
+	queryOrderExists = `SELECT EXISTS (SELECT 1 FROM orders WHERE order_uid = $1)`
+	queryDelete      = `DELETE FROM orders WHERE order_uid = $1`
+	// queryCustomerOrders использует containment, чтобы поиск обслуживался GIN индексом idx_orders_data
+	queryCustomerOrders = `SELECT order_uid FROM orders WHERE data @> jsonb_build_object('customer_id', $1::text)
+		ORDER BY order_uid FOR UPDATE`
+)
+
+// jsonbStore хранит заказ целиком одним JSONB-документом в таблице orders.
+type jsonbStore struct{}
+
+func (jsonbStore) insert(ctx context.Context, tx *sql.Tx, order *models.Order) error {
+	data, err := json.Marshal(order)
+	if err != nil {
+		return fmt.Errorf("json.Marshal: %w", err)
+	}
+
+	if _, err := tx.ExecContext(ctx, queryCreate, order.OrderUID, data, order.Version); err != nil {
+		return fmt.Errorf("tx.ExecContext: %w", err)
+	}
+	return nil
+}
+
+func (jsonbStore) insertBatch(ctx context.Context, tx *sql.Tx, orders []*models.Order) (map[string]bool, error) {
+	rows := make([][]any, 0, len(orders))
+	for _, order := range orders {
+		data, err := json.Marshal(order)
+		if err != nil {
+			return nil, fmt.Errorf("json.Marshal: %w", err)
+		}
+		rows = append(rows, []any{order.OrderUID, data, order.Version})
+	}
+
+	inserted, err := insertSkippingConflicts(ctx, tx, queryCreateBatch, rows)
+	if err != nil {
+		return nil, fmt.Errorf("insertSkippingConflicts: %w", err)
+	}
+	return inserted, nil
+}
+
+func (jsonbStore) read(ctx context.Context, q querier, orderUID string, forUpdate bool) (*models.Order, error) {
+	query := queryRead
+	if forUpdate {
+		query = queryReadForUpdate
+	}
+
+	var data []byte
+	if err := q.QueryRowContext(ctx, query, orderUID).Scan(&data); err != nil {
+		return nil, fmt.Errorf("QueryRowContext: %w", err)
+	}
+
+	return decodeOrder(data)
+}
+
+func (jsonbStore) readAll(ctx context.Context, q querier) ([]*models.Order, error) {
+	rows, err := q.QueryContext(ctx, queryReadAll)
+	if err != nil {
+		return nil, fmt.Errorf("QueryContext: %w", err)
+	}
+
+	return scanDocuments(rows)
+}
+
+func (jsonbStore) list(ctx context.Context, q querier, filter models.OrderFilter) ([]*models.Order, error) {
+	query, args, err := buildListQuery(filter)
+	if err != nil {
+		return nil, fmt.Errorf("buildListQuery: %w", err)
+	}
+
+	rows, err := q.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("QueryContext: %w", err)
+	}
+
+	return scanDocuments(rows)
+}
+
+func (jsonbStore) lookup(ctx context.Context, q querier, lookup models.OrderLookup) ([]*models.Order, error) {
+	query, err := buildLookupQuery(lookup)
+	if err != nil {
+		return nil, fmt.Errorf("buildLookupQuery: %w", err)
+	}
+
+	rows, err := q.QueryContext(ctx, query, lookup.Value, lookup.Limit)
+	if err != nil {
+		return nil, fmt.Errorf("QueryContext: %w", err)
+	}
+
+	return scanDocuments(rows)
+}
+
+func (jsonbStore) changedSince(ctx context.Context, q querier, since time.Time, after string, limit int) ([]*models.Order, error) {
+	rows, err := q.QueryContext(ctx, queryChangedSince, since, after, limit)
+	if err != nil {
+		return nil, fmt.Errorf("QueryContext: %w", err)
+	}
+
+	return scanDocuments(rows)
+}
+
+func (jsonbStore) replace(ctx context.Context, tx *sql.Tx, order *models.Order) error {
+	data, err := json.Marshal(order)
+	if err != nil {
+		return fmt.Errorf("json.Marshal: %w", err)
+	}
+
+	if _, err := tx.ExecContext(ctx, queryUpdateData, order.OrderUID, data, order.Version); err != nil {
+		return fmt.Errorf("tx.ExecContext: %w", err)
+	}
+	return nil
+}
+
+// updateStatus перезаписывает документ целиком: статус хранится внутри data.
+func (s jsonbStore) updateStatus(ctx context.Context, tx *sql.Tx, order *models.Order) error {
+	return s.replace(ctx, tx, order)
+}
+
+func (jsonbStore) exists(ctx context.Context, q querier, orderUID string) (bool, error) {
+	var exists bool
+	if err := q.QueryRowContext(ctx, queryOrderExists, orderUID).Scan(&exists); err != nil {
+		return false, fmt.Errorf("QueryRowContext: %w", err)
+	}
+	return exists, nil
+}
+
+func (jsonbStore) delete(ctx context.Context, tx *sql.Tx, orderUID string) error {
+	if _, err := tx.ExecContext(ctx, queryDelete, orderUID); err != nil {
+		return fmt.Errorf("tx.ExecContext: %w", err)
+	}
+	return nil
+}
+
+func (jsonbStore) customerOrders(ctx context.Context, tx *sql.Tx, customerID string) ([]string, error) {
+	rows, err := tx.QueryContext(ctx, queryCustomerOrders, customerID)
+	if err != nil {
+		return nil, fmt.Errorf("tx.QueryContext: %w", err)
+	}
+	return scanOrderUIDs(rows)
+}
+
+func decodeOrder(data []byte) (*models.Order, error) {
+	var order models.Order
+	if err := json.Unmarshal(data, &order); err != nil {
+		return nil, fmt.Errorf("json.Unmarshal: %w", err)
+	}
+	return &order, nil
+}
+
+// scanDocuments читает заказы из выборки с единственной колонкой-документом и закрывает rows.
+func scanDocuments(rows *sql.Rows) ([]*models.Order, error) {
+	defer rows.Close()
+
+	var orders []*models.Order
+	for rows.Next() {
+		var data []byte
+		if err := rows.Scan(&data); err != nil {
+			return nil, fmt.Errorf("rows.Scan: %w", err)
+		}
+
+		order, err := decodeOrder(data)
+		if err != nil {
+			return nil, err
+		}
+		orders = append(orders, order)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows.Err: %w", err)
+	}
+
+	return orders, nil
+}
+
+// scanOrderUIDs читает выборку из единственной колонки order_uid и закрывает rows.
+func scanOrderUIDs(rows *sql.Rows) ([]string, error) {
+	defer rows.Close()
+
+	var uids []string
+	for rows.Next() {
+		var uid string
+		if err := rows.Scan(&uid); err != nil {
+			return nil, fmt.Errorf("rows.Scan: %w", err)
+		}
+		uids = append(uids, uid)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows.Err: %w", err)
+	}
+
+	return uids, nil
+}
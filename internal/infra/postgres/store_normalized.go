@@ -0,0 +1,464 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/lib/pq"
+
+	"github.com/sunr3d/order-stream-processor/models"
+)
+
+const (
+	queryNormInsertOrders = `INSERT INTO normalized.orders (order_uid, track_number, entry, locale, internal_signature,
+		customer_id, delivery_service, shardkey, sm_id, date_created, oof_shard, status, version) VALUES `
+	queryNormInsertDeliveries = `INSERT INTO normalized.deliveries (order_uid, name, phone, zip, city, address, region, email) VALUES `
+	queryNormInsertPayments   = `INSERT INTO normalized.payments (order_uid, transaction, request_id, currency, provider,
+		amount, payment_dt, bank, delivery_cost, goods_total, custom_fee) VALUES `
+	queryNormInsertItems = `INSERT INTO normalized.items (order_uid, position, chrt_id, track_number, price, rid,
+		name, sale, size, total_price, nm_id, brand, status) VALUES `
+
+	// normSelectOrders собирает заказ без товаров из orders, deliveries и payments
+	normSelectOrders = `SELECT o.order_uid, o.track_number, o.entry, o.locale, o.internal_signature, o.customer_id,
+		o.delivery_service, o.shardkey, o.sm_id, o.date_created, o.oof_shard, o.status, o.version,
+		d.name, d.phone, d.zip, d.city, d.address, d.region, d.email,
+		p.transaction, p.request_id, p.currency, p.provider, p.amount, EXTRACT(EPOCH FROM p.payment_dt)::BIGINT,
+		p.bank, p.delivery_cost, p.goods_total, p.custom_fee
+		FROM normalized.orders o
+		JOIN normalized.deliveries d ON d.order_uid = o.order_uid
+		JOIN normalized.payments p ON p.order_uid = o.order_uid`
+	normSelectItems = `SELECT order_uid, chrt_id, track_number, price, rid, name, sale, size, total_price, nm_id, brand, status
+		FROM normalized.items`
+
+	queryNormRead          = normSelectOrders + ` WHERE o.order_uid = $1`
+	queryNormReadForUpdate = normSelectOrders + ` WHERE o.order_uid = $1 FOR UPDATE OF o`
+	queryNormReadAll       = normSelectOrders + ` ORDER BY o.order_uid`
+	queryNormItemsByOrders = normSelectItems + ` WHERE order_uid = ANY($1) ORDER BY order_uid, position`
+	queryNormAllItems      = normSelectItems + ` ORDER BY order_uid, position`
+	queryNormChangedSince  = normSelectOrders + ` WHERE o.updated_at >= $1 AND o.order_uid > $2 ORDER BY o.order_uid LIMIT $3`
+	queryNormUpdateStatus  = `UPDATE normalized.orders SET status = $2, version = $3, updated_at = now() WHERE order_uid = $1`
+	queryNormReplaceOrder  = `UPDATE normalized.orders SET track_number = $2, entry = $3, locale = $4,
+		internal_signature = $5, customer_id = $6, delivery_service = $7, shardkey = $8, sm_id = $9,
+		date_created = $10, oof_shard = $11, status = $12, version = $13, updated_at = now() WHERE order_uid = $1`
+	queryNormDeleteDetails = `WITH d AS (DELETE FROM normalized.deliveries WHERE order_uid = $1),
+		p AS (DELETE FROM normalized.payments WHERE order_uid = $1)
+		DELETE FROM normalized.items WHERE order_uid = $1`
+	queryNormOrderExists = `SELECT EXISTS (SELECT 1 FROM normalized.orders WHERE order_uid = $1)`
+	// Доставка, оплата и товары удаляются каскадно
+	queryNormDelete         = `DELETE FROM normalized.orders WHERE order_uid = $1`
+	queryNormCustomerOrders = `SELECT order_uid FROM normalized.orders WHERE customer_id = $1 ORDER BY order_uid FOR UPDATE`
+
+	normListSortKey = `o.date_created`
+)
+
+// normalizedStore хранит заказ в связанных таблицах схемы normalized:
+// orders, deliveries и payments (один к одному) и items (один ко многим, порядок товаров сохраняется).
+type normalizedStore struct{}
+
+func (normalizedStore) insert(ctx context.Context, tx *sql.Tx, order *models.Order) error {
+	if err := execBulkInsert(ctx, tx, queryNormInsertOrders, [][]any{normOrderRow(order)}); err != nil {
+		return fmt.Errorf("tx.ExecContext(orders): %w", err)
+	}
+	return insertNormalizedDetails(ctx, tx, []*models.Order{order})
+}
+
+func (normalizedStore) insertBatch(ctx context.Context, tx *sql.Tx, orders []*models.Order) (map[string]bool, error) {
+	rows := make([][]any, 0, len(orders))
+	for _, order := range orders {
+		rows = append(rows, normOrderRow(order))
+	}
+
+	inserted, err := insertSkippingConflicts(ctx, tx, queryNormInsertOrders, rows)
+	if err != nil {
+		return nil, fmt.Errorf("insertSkippingConflicts(orders): %w", err)
+	}
+
+	// Доставка, оплата и товары пишутся только для вставленных заказов:
+	// у пропущенных они уже есть в БД
+	fresh := make([]*models.Order, 0, len(inserted))
+	for _, order := range orders {
+		if inserted[order.OrderUID] {
+			fresh = append(fresh, order)
+		}
+	}
+	if err := insertNormalizedDetails(ctx, tx, fresh); err != nil {
+		return nil, err
+	}
+
+	return inserted, nil
+}
+
+// insertNormalizedDetails вставляет доставку, оплату и товары уже вставленных заказов.
+func insertNormalizedDetails(ctx context.Context, tx *sql.Tx, orders []*models.Order) error {
+	if len(orders) == 0 {
+		return nil
+	}
+
+	deliveries := make([][]any, 0, len(orders))
+	payments := make([][]any, 0, len(orders))
+	var items [][]any
+	for _, order := range orders {
+		d, p := order.Delivery, order.Payment
+		deliveries = append(deliveries, []any{
+			order.OrderUID, d.Name, d.Phone, d.Zip, d.City, d.Address, d.Region, d.Email,
+		})
+		payments = append(payments, []any{
+			order.OrderUID, p.Transaction, p.RequestID, p.Currency, p.Provider,
+			p.Amount, time.Unix(p.PaymentDT, 0).UTC(), p.Bank, p.DeliveryCost, p.GoodsTotal, p.CustomFee,
+		})
+		items = append(items, normItemRows(order)...)
+	}
+
+	if err := execBulkInsert(ctx, tx, queryNormInsertDeliveries, deliveries); err != nil {
+		return fmt.Errorf("tx.ExecContext(deliveries): %w", err)
+	}
+	if err := execBulkInsert(ctx, tx, queryNormInsertPayments, payments); err != nil {
+		return fmt.Errorf("tx.ExecContext(payments): %w", err)
+	}
+	if err := execBulkInsert(ctx, tx, queryNormInsertItems, items); err != nil {
+		return fmt.Errorf("tx.ExecContext(items): %w", err)
+	}
+
+	return nil
+}
+
+func (normalizedStore) read(ctx context.Context, q querier, orderUID string, forUpdate bool) (*models.Order, error) {
+	query := queryNormRead
+	if forUpdate {
+		query = queryNormReadForUpdate
+	}
+
+	order, err := scanNormalizedOrder(q.QueryRowContext(ctx, query, orderUID))
+	if err != nil {
+		return nil, fmt.Errorf("QueryRowContext: %w", err)
+	}
+
+	rows, err := q.QueryContext(ctx, queryNormItemsByOrders, pq.Array([]string{orderUID}))
+	if err != nil {
+		return nil, fmt.Errorf("QueryContext(items): %w", err)
+	}
+	if err := attachItems(rows, []*models.Order{order}); err != nil {
+		return nil, err
+	}
+
+	return order, nil
+}
+
+func (normalizedStore) readAll(ctx context.Context, q querier) ([]*models.Order, error) {
+	rows, err := q.QueryContext(ctx, queryNormReadAll)
+	if err != nil {
+		return nil, fmt.Errorf("QueryContext: %w", err)
+	}
+
+	orders, err := scanNormalizedOrders(rows)
+	if err != nil {
+		return nil, err
+	}
+
+	itemRows, err := q.QueryContext(ctx, queryNormAllItems)
+	if err != nil {
+		return nil, fmt.Errorf("QueryContext(items): %w", err)
+	}
+	if err := attachItems(itemRows, orders); err != nil {
+		return nil, err
+	}
+
+	return orders, nil
+}
+
+func (s normalizedStore) list(ctx context.Context, q querier, filter models.OrderFilter) ([]*models.Order, error) {
+	query, args, err := buildNormalizedListQuery(filter)
+	if err != nil {
+		return nil, fmt.Errorf("buildNormalizedListQuery: %w", err)
+	}
+
+	rows, err := q.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("QueryContext: %w", err)
+	}
+
+	orders, err := scanNormalizedOrders(rows)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.loadItems(ctx, q, orders); err != nil {
+		return nil, err
+	}
+
+	return orders, nil
+}
+
+func (s normalizedStore) lookup(ctx context.Context, q querier, lookup models.OrderLookup) ([]*models.Order, error) {
+	query, err := buildNormalizedLookupQuery(lookup)
+	if err != nil {
+		return nil, fmt.Errorf("buildNormalizedLookupQuery: %w", err)
+	}
+
+	rows, err := q.QueryContext(ctx, query, lookup.Value, lookup.Limit)
+	if err != nil {
+		return nil, fmt.Errorf("QueryContext: %w", err)
+	}
+
+	orders, err := scanNormalizedOrders(rows)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.loadItems(ctx, q, orders); err != nil {
+		return nil, err
+	}
+
+	return orders, nil
+}
+
+func (s normalizedStore) changedSince(ctx context.Context, q querier, since time.Time, after string, limit int) ([]*models.Order, error) {
+	rows, err := q.QueryContext(ctx, queryNormChangedSince, since, after, limit)
+	if err != nil {
+		return nil, fmt.Errorf("QueryContext: %w", err)
+	}
+
+	orders, err := scanNormalizedOrders(rows)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.loadItems(ctx, q, orders); err != nil {
+		return nil, err
+	}
+
+	return orders, nil
+}
+
+// replace обновляет строку заказа и пересоздает доставку, оплату и товары.
+func (normalizedStore) replace(ctx context.Context, tx *sql.Tx, order *models.Order) error {
+	if _, err := tx.ExecContext(ctx, queryNormReplaceOrder, normOrderRow(order)...); err != nil {
+		return fmt.Errorf("tx.ExecContext(orders): %w", err)
+	}
+	if _, err := tx.ExecContext(ctx, queryNormDeleteDetails, order.OrderUID); err != nil {
+		return fmt.Errorf("tx.ExecContext(delete details): %w", err)
+	}
+	return insertNormalizedDetails(ctx, tx, []*models.Order{order})
+}
+
+func (normalizedStore) updateStatus(ctx context.Context, tx *sql.Tx, order *models.Order) error {
+	if _, err := tx.ExecContext(ctx, queryNormUpdateStatus, order.OrderUID, string(order.Status), order.Version); err != nil {
+		return fmt.Errorf("tx.ExecContext: %w", err)
+	}
+	return nil
+}
+
+func (normalizedStore) exists(ctx context.Context, q querier, orderUID string) (bool, error) {
+	var exists bool
+	if err := q.QueryRowContext(ctx, queryNormOrderExists, orderUID).Scan(&exists); err != nil {
+		return false, fmt.Errorf("QueryRowContext: %w", err)
+	}
+	return exists, nil
+}
+
+func (normalizedStore) delete(ctx context.Context, tx *sql.Tx, orderUID string) error {
+	if _, err := tx.ExecContext(ctx, queryNormDelete, orderUID); err != nil {
+		return fmt.Errorf("tx.ExecContext: %w", err)
+	}
+	return nil
+}
+
+func (normalizedStore) customerOrders(ctx context.Context, tx *sql.Tx, customerID string) ([]string, error) {
+	rows, err := tx.QueryContext(ctx, queryNormCustomerOrders, customerID)
+	if err != nil {
+		return nil, fmt.Errorf("tx.QueryContext: %w", err)
+	}
+	return scanOrderUIDs(rows)
+}
+
+// loadItems подгружает товары для уже прочитанных заказов одним запросом.
+func (normalizedStore) loadItems(ctx context.Context, q querier, orders []*models.Order) error {
+	if len(orders) == 0 {
+		return nil
+	}
+
+	uids := make([]string, 0, len(orders))
+	for _, order := range orders {
+		uids = append(uids, order.OrderUID)
+	}
+
+	rows, err := q.QueryContext(ctx, queryNormItemsByOrders, pq.Array(uids))
+	if err != nil {
+		return fmt.Errorf("QueryContext(items): %w", err)
+	}
+	return attachItems(rows, orders)
+}
+
+func normOrderRow(order *models.Order) []any {
+	return []any{
+		order.OrderUID, order.TrackNumber, order.Entry, order.Locale, order.InternalSignature,
+		order.CustomerID, order.DeliveryService, order.ShardKey, order.SmID, order.DateCreated, order.OofShard,
+		string(order.Status), order.Version,
+	}
+}
+
+// normItemRows возвращает строки товаров заказа, position сохраняет их порядок.
+func normItemRows(order *models.Order) [][]any {
+	rows := make([][]any, 0, len(order.Items))
+	for i, it := range order.Items {
+		rows = append(rows, []any{
+			order.OrderUID, i, it.ChrtID, it.TrackNumber, it.Price, it.RID,
+			it.Name, it.Sale, it.Size, it.TotalPrice, it.NmID, it.Brand, it.Status,
+		})
+	}
+	return rows
+}
+
+// normLookupKeys — колонки вторичных ключей, индексы на них созданы миграцией 0004.
+var normLookupKeys = map[models.LookupField]string{
+	models.LookupTrackNumber: "o.track_number",
+	models.LookupTransaction: "p.transaction",
+	models.LookupCustomer:    "o.customer_id",
+}
+
+func buildNormalizedLookupQuery(l models.OrderLookup) (string, error) {
+	key, ok := normLookupKeys[l.Field]
+	if !ok {
+		return "", fmt.Errorf("%w: неизвестный ключ поиска %q", models.ErrValidation, l.Field)
+	}
+	return fmt.Sprintf("%s WHERE %s = $1 ORDER BY %s DESC, o.order_uid DESC LIMIT $2",
+		normSelectOrders, key, normListSortKey), nil
+}
+
+func buildNormalizedListQuery(f models.OrderFilter) (string, []any, error) {
+	var (
+		where []string
+		args  []any
+	)
+	arg := func(v any) string {
+		args = append(args, v)
+		return fmt.Sprintf("$%d", len(args))
+	}
+
+	if f.CustomerID != "" {
+		where = append(where, "o.customer_id = "+arg(f.CustomerID))
+	}
+	if f.DeliveryService != "" {
+		where = append(where, "o.delivery_service = "+arg(f.DeliveryService))
+	}
+	if f.TrackNumber != "" {
+		where = append(where, "o.track_number = "+arg(f.TrackNumber))
+	}
+	if f.Currency != "" {
+		where = append(where, "p.currency = "+arg(f.Currency))
+	}
+	if f.Provider != "" {
+		where = append(where, "p.provider = "+arg(f.Provider))
+	}
+	if f.Brand != "" {
+		where = append(where, "EXISTS (SELECT 1 FROM normalized.items i WHERE i.order_uid = o.order_uid AND i.brand = "+arg(f.Brand)+")")
+	}
+	if !f.CreatedFrom.IsZero() {
+		where = append(where, normListSortKey+" >= "+arg(f.CreatedFrom))
+	}
+	if !f.CreatedTo.IsZero() {
+		where = append(where, normListSortKey+" < "+arg(f.CreatedTo))
+	}
+
+	cmp, dir := ">", "ASC"
+	if f.Sort == models.SortDesc {
+		cmp, dir = "<", "DESC"
+	}
+
+	if f.Cursor != "" {
+		cursor, err := decodeCursor(f.Cursor)
+		if err != nil {
+			return "", nil, err
+		}
+		where = append(where, fmt.Sprintf("(%s, o.order_uid) %s (%s, %s)", normListSortKey, cmp, arg(cursor.DateCreated), arg(cursor.OrderUID)))
+	}
+
+	var q strings.Builder
+	q.WriteString(normSelectOrders)
+	if len(where) > 0 {
+		q.WriteString(" WHERE ")
+		q.WriteString(strings.Join(where, " AND "))
+	}
+	fmt.Fprintf(&q, " ORDER BY %s %s, o.order_uid %s LIMIT %s", normListSortKey, dir, dir, arg(f.Limit+1))
+
+	return q.String(), args, nil
+}
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanNormalizedOrder(row rowScanner) (*models.Order, error) {
+	var (
+		o      models.Order
+		status string
+	)
+	d, p := &o.Delivery, &o.Payment
+
+	err := row.Scan(
+		&o.OrderUID, &o.TrackNumber, &o.Entry, &o.Locale, &o.InternalSignature, &o.CustomerID,
+		&o.DeliveryService, &o.ShardKey, &o.SmID, &o.DateCreated, &o.OofShard, &status, &o.Version,
+		&d.Name, &d.Phone, &d.Zip, &d.City, &d.Address, &d.Region, &d.Email,
+		&p.Transaction, &p.RequestID, &p.Currency, &p.Provider, &p.Amount, &p.PaymentDT,
+		&p.Bank, &p.DeliveryCost, &p.GoodsTotal, &p.CustomFee,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	o.Status = models.OrderStatus(status)
+	o.Items = []models.Item{}
+	return &o, nil
+}
+
+// scanNormalizedOrders читает заказы без товаров и закрывает rows.
+func scanNormalizedOrders(rows *sql.Rows) ([]*models.Order, error) {
+	defer rows.Close()
+
+	var orders []*models.Order
+	for rows.Next() {
+		order, err := scanNormalizedOrder(rows)
+		if err != nil {
+			return nil, fmt.Errorf("rows.Scan: %w", err)
+		}
+		orders = append(orders, order)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows.Err: %w", err)
+	}
+
+	return orders, nil
+}
+
+// attachItems раскладывает товары из выборки по заказам и закрывает rows.
+func attachItems(rows *sql.Rows, orders []*models.Order) error {
+	defer rows.Close()
+
+	byUID := make(map[string]*models.Order, len(orders))
+	for _, order := range orders {
+		byUID[order.OrderUID] = order
+	}
+
+	for rows.Next() {
+		var (
+			uid string
+			it  models.Item
+		)
+		err := rows.Scan(&uid, &it.ChrtID, &it.TrackNumber, &it.Price, &it.RID, &it.Name,
+			&it.Sale, &it.Size, &it.TotalPrice, &it.NmID, &it.Brand, &it.Status)
+		if err != nil {
+			return fmt.Errorf("rows.Scan(items): %w", err)
+		}
+
+		if order, ok := byUID[uid]; ok {
+			order.Items = append(order.Items, it)
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("rows.Err(items): %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,105 @@
+package postgres
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sunr3d/order-stream-processor/models"
+)
+
+func TestNewStore(t *testing.T) {
+	store, err := newStore(StorageJSONB)
+	require.NoError(t, err)
+	assert.IsType(t, jsonbStore{}, store)
+
+	store, err = newStore(StorageNormalized)
+	require.NoError(t, err)
+	assert.IsType(t, normalizedStore{}, store)
+
+	_, err = newStore("mongo")
+	assert.Error(t, err)
+}
+
+func TestNormItemRows(t *testing.T) {
+	order := &models.Order{
+		OrderUID: "test-1",
+		Items: []models.Item{
+			{ChrtID: 1, Name: "first", Brand: "A"},
+			{ChrtID: 2, Name: "second", Brand: "B"},
+		},
+	}
+
+	rows := normItemRows(order)
+
+	require.Len(t, rows, 2)
+	assert.Equal(t, []any{"test-1", 0, 1}, rows[0][:3])
+	assert.Equal(t, []any{"test-1", 1, 2}, rows[1][:3])
+	assert.Equal(t, "B", rows[1][len(rows[1])-2])
+
+	query, args := buildBulkInsert(queryNormInsertItems, "", rows)
+	assert.True(t, strings.HasPrefix(query, queryNormInsertItems))
+	assert.True(t, strings.HasSuffix(query,
+		"($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13), "+
+			"($14, $15, $16, $17, $18, $19, $20, $21, $22, $23, $24, $25, $26)"))
+	assert.Len(t, args, 26)
+}
+
+func TestBuildNormalizedListQuery_Filters(t *testing.T) {
+	from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	query, args, err := buildNormalizedListQuery(models.OrderFilter{
+		CustomerID:  "customer-1",
+		Currency:    "RUB",
+		Brand:       "Nike",
+		CreatedFrom: from,
+		Sort:        models.SortAsc,
+		Limit:       10,
+	})
+	require.NoError(t, err)
+
+	assert.True(t, strings.HasPrefix(query, normSelectOrders))
+	assert.True(t, strings.HasSuffix(query,
+		` WHERE o.customer_id = $1 AND p.currency = $2`+
+			` AND EXISTS (SELECT 1 FROM normalized.items i WHERE i.order_uid = o.order_uid AND i.brand = $3)`+
+			` AND o.date_created >= $4 ORDER BY o.date_created ASC, o.order_uid ASC LIMIT $5`,
+	), query)
+	assert.Equal(t, []any{"customer-1", "RUB", "Nike", from, 11}, args)
+}
+
+func TestBuildNormalizedListQuery_CursorDesc(t *testing.T) {
+	order := &models.Order{OrderUID: "test-1", DateCreated: time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC)}
+
+	query, args, err := buildNormalizedListQuery(models.OrderFilter{
+		Sort:   models.SortDesc,
+		Limit:  5,
+		Cursor: encodeCursor(order),
+	})
+	require.NoError(t, err)
+
+	assert.True(t, strings.HasSuffix(query,
+		` WHERE (o.date_created, o.order_uid) < ($1, $2) ORDER BY o.date_created DESC, o.order_uid DESC LIMIT $3`,
+	), query)
+	assert.True(t, order.DateCreated.Equal(args[0].(time.Time)))
+	assert.Equal(t, "test-1", args[1])
+}
+
+func TestBuildNormalizedListQuery_InvalidCursor(t *testing.T) {
+	_, _, err := buildNormalizedListQuery(models.OrderFilter{Limit: 5, Cursor: "не курсор"})
+
+	assert.ErrorIs(t, err, models.ErrValidation)
+}
+
+func TestNormOrderRow_MatchesQueries(t *testing.T) {
+	row := normOrderRow(&models.Order{OrderUID: "test-1", Version: 2})
+
+	require.Len(t, row, 13)
+	assert.Equal(t, int64(2), row[12])
+	assert.Contains(t, queryNormReplaceOrder, "$13")
+	assert.NotContains(t, queryNormReplaceOrder, "$14")
+
+	columns := queryNormInsertOrders[strings.Index(queryNormInsertOrders, "(")+1 : strings.Index(queryNormInsertOrders, ")")]
+	assert.Len(t, strings.Split(columns, ","), len(row))
+}
@@ -0,0 +1,169 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"go.uber.org/zap"
+
+	"github.com/sunr3d/order-stream-processor/internal/metrics"
+	"github.com/sunr3d/order-stream-processor/models"
+)
+
+// Update заменяет заказ целиком, если текущая версия равна expectedVersion (0 — без проверки).
+// Статус не меняется: он управляется через UpdateStatus. Новая версия записывается в order.Version.
+func (r *postgresRepo) Update(ctx context.Context, order *models.Order, expectedVersion int64) error {
+	defer metrics.QueryTimer("update").ObserveDuration()
+
+	logger := r.logger.With(
+		zap.String("op", "postgres.Update"),
+		zap.String("order_uid", order.OrderUID),
+		zap.Int64("expected_version", expectedVersion),
+	)
+
+	logger.Info("обновление заказа в БД...")
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		logger.Error("ошибка при создании транзакции", zap.Error(err))
+		return wrapErr("db.BeginTx", err)
+	}
+	defer tx.Rollback()
+
+	stored, err := r.store.read(ctx, tx, order.OrderUID, true)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			logger.Info("заказ не найден")
+			return fmt.Errorf("%w: %s", models.ErrOrderNotFound, order.OrderUID)
+		}
+		logger.Error("ошибка чтения из БД", zap.Error(err))
+		return wrapErr("store.read", err)
+	}
+
+	if expectedVersion != 0 && stored.Version != expectedVersion {
+		logger.Info("версия заказа изменилась", zap.Int64("version", stored.Version))
+		return fmt.Errorf("%w: %s ожидалась %d, текущая %d",
+			models.ErrVersionConflict, order.OrderUID, expectedVersion, stored.Version)
+	}
+
+	order.Status = stored.Status
+	order.Version = stored.Version + 1
+
+	if err := r.replace(ctx, tx, order); err != nil {
+		logger.Error("ошибка при обновлении заказа в БД", zap.Error(err))
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		logger.Error("ошибка при фиксации транзакции", zap.Error(err))
+		return wrapErr("tx.Commit", err)
+	}
+
+	logger.Info("заказ успешно обновлен в БД", zap.Int64("version", order.Version))
+	return nil
+}
+
+// Upsert сохраняет новый заказ или заменяет сохраненный, если присланный новее (см. models.Order.Supersedes).
+// Статус сохраненного заказа не меняется. Без явной версии заказ получает следующую за сохраненной.
+func (r *postgresRepo) Upsert(ctx context.Context, order *models.Order) (models.UpsertResult, error) {
+	defer metrics.QueryTimer("upsert").ObserveDuration()
+
+	logger := r.logger.With(
+		zap.String("op", "postgres.Upsert"),
+		zap.String("order_uid", order.OrderUID),
+	)
+
+	logger.Info("upsert заказа в БД...")
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		logger.Error("ошибка при создании транзакции", zap.Error(err))
+		return "", wrapErr("db.BeginTx", err)
+	}
+	defer tx.Rollback()
+
+	// Обезличенный заказ тоже нельзя заменять: присланный заказ вернул бы персональные данные
+	erased, err := erasedOrders(ctx, tx, []string{order.OrderUID})
+	if err != nil {
+		logger.Error("ошибка при проверке журнала аудита", zap.Error(err))
+		return "", err
+	}
+	if erased[order.OrderUID] {
+		logger.Warn("заказ был удален или обезличен, upsert отклонен")
+		return "", erasedErr(order.OrderUID)
+	}
+
+	result := models.UpsertUpdated
+	stored, err := r.store.read(ctx, tx, order.OrderUID, true)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		result = models.UpsertCreated
+		if order.Version <= 0 {
+			order.Version = 1
+		}
+		// Конкурентная вставка того же заказа вернет unique violation, сообщение будет обработано повторно
+		if err := r.store.insert(ctx, tx, order); err != nil {
+			logger.Error("ошибка при сохранении заказа в БД", zap.Error(err))
+			return "", wrapErr("store.insert", err)
+		}
+		if err := insertCreatedHistory(ctx, tx, []*models.Order{order}); err != nil {
+			logger.Error("ошибка при записи истории статусов", zap.Error(err))
+			return "", err
+		}
+		if err := r.writeEvent(ctx, tx, models.EventOrderCreated, order); err != nil {
+			logger.Error("ошибка при записи события в outbox", zap.Error(err))
+			return "", err
+		}
+	case err != nil:
+		logger.Error("ошибка чтения из БД", zap.Error(err))
+		return "", wrapErr("store.read", err)
+	case !order.Supersedes(stored):
+		logger.Info("сохраненный заказ не старше присланного, upsert пропущен",
+			zap.Int64("stored_version", stored.Version),
+			zap.Int64("version", order.Version),
+		)
+		return models.UpsertSkipped, nil
+	default:
+		if order.Version <= 0 {
+			order.Version = stored.Version + 1
+		}
+		order.Status = stored.Status
+		if err := r.replace(ctx, tx, order); err != nil {
+			logger.Error("ошибка при обновлении заказа в БД", zap.Error(err))
+			return "", err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		logger.Error("ошибка при фиксации транзакции", zap.Error(err))
+		return "", wrapErr("tx.Commit", err)
+	}
+
+	logger.Info("upsert заказа выполнен",
+		zap.String("result", string(result)),
+		zap.Int64("version", order.Version),
+	)
+	return result, nil
+}
+
+// replace заменяет заказ и записывает событие order.updated в той же транзакции.
+func (r *postgresRepo) replace(ctx context.Context, tx *sql.Tx, order *models.Order) error {
+	if err := r.store.replace(ctx, tx, order); err != nil {
+		return wrapErr("store.replace", err)
+	}
+	return r.writeEvent(ctx, tx, models.EventOrderUpdated, order)
+}
+
+func (r *postgresRepo) writeEvent(ctx context.Context, tx *sql.Tx, eventType string, order *models.Order) error {
+	data, err := json.Marshal(order)
+	if err != nil {
+		return fmt.Errorf("json.Marshal: %w", err)
+	}
+	if err := insertOutbox(ctx, tx, eventType, order.OrderUID, data); err != nil {
+		return fmt.Errorf("insertOutbox: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,256 @@
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"sync/atomic"
+	"time"
+
+	goredis "github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+
+	"github.com/sunr3d/order-stream-processor/internal/config"
+	"github.com/sunr3d/order-stream-processor/internal/interfaces/infra"
+	"github.com/sunr3d/order-stream-processor/models"
+)
+
+var _ infra.Cache = (*redisCache)(nil)
+var _ infra.CacheStatsProvider = (*redisCache)(nil)
+var _ infra.HealthChecker = (*redisCache)(nil)
+
+// restoreBatchSize — сколько заказов отправляется в Redis одним конвейером (pipeline) при восстановлении.
+const restoreBatchSize = 500
+
+// Заказ хранится в хэше: версия отдельным полем, чтобы сравнивать ее в Redis без разбора JSON.
+const (
+	fieldVersion = "version"
+	fieldOrder   = "order"
+)
+
+// setScript записывает заказ, если в Redis нет более новой версии, и обновляет TTL.
+// KEYS[1] — ключ заказа, ARGV — версия, заказ в JSON, TTL в миллисекундах (0 — без ограничения).
+// Возвращает 1, если заказ записан, и 0, если запись пропущена.
+var setScript = goredis.NewScript(`
+local cur = redis.call('HGET', KEYS[1], '` + fieldVersion + `')
+if cur and tonumber(cur) > tonumber(ARGV[1]) then
+	return 0
+end
+redis.call('HSET', KEYS[1], '` + fieldVersion + `', ARGV[1], '` + fieldOrder + `', ARGV[2])
+if tonumber(ARGV[3]) > 0 then
+	redis.call('PEXPIRE', KEYS[1], ARGV[3])
+else
+	redis.call('PERSIST', KEYS[1])
+end
+return 1
+`)
+
+// redisCache — кэш заказов в Redis, общий для всех реплик сервиса.
+// Лимиты и политика вытеснения задаются настройками самого Redis (maxmemory, maxmemory-policy),
+// из настроек кэша используется только TTL.
+type redisCache struct {
+	client *goredis.Client
+	prefix string
+	ttl    time.Duration
+	logger *zap.Logger
+
+	hits   atomic.Uint64
+	misses atomic.Uint64
+}
+
+func New(cfg config.RedisConfig, cacheCfg config.CacheConfig, log *zap.Logger) (infra.Cache, error) {
+	client := goredis.NewClient(&goredis.Options{
+		Addr:         cfg.Addr,
+		Password:     cfg.Password,
+		DB:           cfg.DB,
+		PoolSize:     cfg.PoolSize,
+		MinIdleConns: cfg.MinIdleConns,
+		DialTimeout:  cfg.DialTimeout,
+		ReadTimeout:  cfg.ReadTimeout,
+		WriteTimeout: cfg.WriteTimeout,
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), cfg.PingTimeout)
+	defer cancel()
+
+	if err := client.Ping(ctx).Err(); err != nil {
+		client.Close()
+		return nil, fmt.Errorf("client.Ping: %w", err)
+	}
+	if err := setScript.Load(ctx, client).Err(); err != nil {
+		client.Close()
+		return nil, fmt.Errorf("setScript.Load: %w", err)
+	}
+
+	log.Info("соединение с Redis установлено", zap.String("addr", cfg.Addr), zap.Int("db", cfg.DB))
+
+	return &redisCache{
+		client: client,
+		prefix: cfg.KeyPrefix,
+		ttl:    cacheCfg.TTL,
+		logger: log,
+	}, nil
+}
+
+func (c *redisCache) Close() error {
+	return c.client.Close()
+}
+
+// HealthCheck проверяет доступность Redis.
+func (c *redisCache) HealthCheck(ctx context.Context) error {
+	if err := c.client.Ping(ctx).Err(); err != nil {
+		return fmt.Errorf("client.Ping: %w", err)
+	}
+	return nil
+}
+
+// Set сохраняет заказ; более новая версия заказа в Redis не заменяется.
+func (c *redisCache) Set(ctx context.Context, orderUID string, order *models.Order) error {
+	logger := c.logger.With(
+		zap.String("op", "redis.Set"),
+		zap.String("order_uid", orderUID),
+	)
+
+	logger.Info("сохранение заказа в кэше...")
+
+	data, err := json.Marshal(order)
+	if err != nil {
+		logger.Error("ошибка при сериализации заказа", zap.Error(err))
+		return fmt.Errorf("json.Marshal: %w", err)
+	}
+
+	written, err := setScript.Run(ctx, c.client, []string{c.key(orderUID)}, order.Version, data, c.ttl.Milliseconds()).Int()
+	if err != nil {
+		logger.Error("ошибка при сохранении заказа в Redis", zap.Error(err))
+		return fmt.Errorf("setScript.Run: %w", err)
+	}
+	if written == 0 {
+		logger.Info("в кэше более новая версия заказа, запись пропущена", zap.Int64("version", order.Version))
+		return nil
+	}
+
+	logger.Info("заказ успешно сохранен в кэше")
+	return nil
+}
+
+func (c *redisCache) Get(ctx context.Context, orderUID string) (*models.Order, error) {
+	logger := c.logger.With(
+		zap.String("op", "redis.Get"),
+		zap.String("order_uid", orderUID),
+	)
+
+	logger.Info("поиск заказа в кэше...")
+
+	data, err := c.client.HGet(ctx, c.key(orderUID), fieldOrder).Bytes()
+	if err != nil {
+		if errors.Is(err, goredis.Nil) {
+			c.misses.Add(1)
+			logger.Info("заказ не найден в кэше")
+			return nil, fmt.Errorf("%w в кэше: %s", models.ErrOrderNotFound, orderUID)
+		}
+		logger.Error("ошибка при чтении заказа из Redis", zap.Error(err))
+		return nil, fmt.Errorf("client.HGet: %w", err)
+	}
+
+	var order models.Order
+	if err := json.Unmarshal(data, &order); err != nil {
+		logger.Error("ошибка при разборе заказа из кэша", zap.Error(err))
+		return nil, fmt.Errorf("json.Unmarshal: %w", err)
+	}
+
+	c.hits.Add(1)
+	logger.Info("заказ успешно найден в кэше")
+	return &order, nil
+}
+
+// Restore добавляет заказы в Redis конвейерами по restoreBatchSize команд, не очищая кэш.
+// Более новая версия заказа в Redis не заменяется.
+func (c *redisCache) Restore(ctx context.Context, orders []*models.Order) error {
+	logger := c.logger.With(
+		zap.String("op", "redis.Restore"),
+		zap.Int("count", len(orders)),
+	)
+
+	logger.Info("восстановление заказов в кэш...")
+
+	restored := 0
+	for batch := range slices.Chunk(orders, restoreBatchSize) {
+		n, err := c.restoreBatch(ctx, batch)
+		// Скрипт пропадает из Redis после перезапуска или SCRIPT FLUSH: EvalSha в конвейере
+		// не загружает его сам, как Run
+		if goredis.HasErrorPrefix(err, "NOSCRIPT") {
+			if err := setScript.Load(ctx, c.client).Err(); err != nil {
+				logger.Error("ошибка при загрузке скрипта в Redis", zap.Error(err))
+				return fmt.Errorf("setScript.Load: %w", err)
+			}
+			n, err = c.restoreBatch(ctx, batch)
+		}
+		if err != nil {
+			logger.Error("ошибка при восстановлении заказов в Redis", zap.Error(err))
+			return err
+		}
+		restored += n
+	}
+
+	logger.Info("заказы восстановлены в кэш",
+		zap.Int("restored_count", restored),
+		zap.Int("skipped", len(orders)-restored),
+	)
+	return nil
+}
+
+// restoreBatch записывает заказы одним конвейером и возвращает число записанных.
+func (c *redisCache) restoreBatch(ctx context.Context, orders []*models.Order) (int, error) {
+	pipe := c.client.Pipeline()
+	cmds := make([]*goredis.Cmd, 0, len(orders))
+	for _, order := range orders {
+		data, err := json.Marshal(order)
+		if err != nil {
+			return 0, fmt.Errorf("json.Marshal(%s): %w", order.OrderUID, err)
+		}
+		cmds = append(cmds, setScript.EvalSha(ctx, pipe, []string{c.key(order.OrderUID)},
+			order.Version, data, c.ttl.Milliseconds()))
+	}
+
+	if _, err := pipe.Exec(ctx); err != nil {
+		return 0, fmt.Errorf("pipe.Exec: %w", err)
+	}
+
+	written := 0
+	for _, cmd := range cmds {
+		if n, _ := cmd.Int(); n == 1 {
+			written++
+		}
+	}
+	return written, nil
+}
+
+func (c *redisCache) Delete(ctx context.Context, orderUID string) error {
+	logger := c.logger.With(
+		zap.String("op", "redis.Delete"),
+		zap.String("order_uid", orderUID),
+	)
+
+	if err := c.client.Del(ctx, c.key(orderUID)).Err(); err != nil {
+		logger.Error("ошибка при удалении заказа из Redis", zap.Error(err))
+		return fmt.Errorf("client.Del: %w", err)
+	}
+
+	logger.Info("заказ удален из кэша")
+	return nil
+}
+
+// Stats возвращает попадания и промахи этой реплики. Число записей, объем и вытеснения
+// ведет сам Redis (INFO), поэтому они не заполняются.
+func (c *redisCache) Stats() infra.CacheStats {
+	return infra.CacheStats{
+		Hits:   c.hits.Load(),
+		Misses: c.misses.Load(),
+	}
+}
+
+func (c *redisCache) key(orderUID string) string {
+	return c.prefix + orderUID
+}
@@ -0,0 +1,153 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/sunr3d/order-stream-processor/internal/config"
+	"github.com/sunr3d/order-stream-processor/models"
+)
+
+func newTestCache(t *testing.T, cacheCfg config.CacheConfig) (*redisCache, *miniredis.Miniredis) {
+	t.Helper()
+	mr := miniredis.RunT(t)
+
+	c, err := New(config.RedisConfig{
+		Addr:        mr.Addr(),
+		PoolSize:    2,
+		PingTimeout: time.Second,
+		KeyPrefix:   "test:",
+	}, cacheCfg, zap.NewNop())
+	require.NoError(t, err)
+	t.Cleanup(func() { c.(*redisCache).Close() })
+
+	return c.(*redisCache), mr
+}
+
+func order(uid string, version int64) *models.Order {
+	return &models.Order{OrderUID: uid, CustomerID: "customer-" + uid, Version: version}
+}
+
+func TestRedisCache_SetGet(t *testing.T) {
+	c, mr := newTestCache(t, config.CacheConfig{})
+	ctx := context.Background()
+
+	require.NoError(t, c.Set(ctx, "a", order("a", 1)))
+
+	got, err := c.Get(ctx, "a")
+	require.NoError(t, err)
+	assert.Equal(t, order("a", 1), got)
+	assert.True(t, mr.Exists("test:a"), "ключ содержит префикс")
+
+	_, err = c.Get(ctx, "missing")
+	assert.ErrorIs(t, err, models.ErrOrderNotFound)
+
+	stats := c.Stats()
+	assert.Equal(t, uint64(1), stats.Hits)
+	assert.Equal(t, uint64(1), stats.Misses)
+}
+
+func TestRedisCache_KeepsNewerVersion(t *testing.T) {
+	c, _ := newTestCache(t, config.CacheConfig{})
+	ctx := context.Background()
+
+	require.NoError(t, c.Set(ctx, "a", order("a", 3)))
+	require.NoError(t, c.Set(ctx, "a", order("a", 2)))
+
+	got, err := c.Get(ctx, "a")
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), got.Version, "старая версия не затирает новую")
+
+	require.NoError(t, c.Set(ctx, "a", order("a", 4)))
+	got, err = c.Get(ctx, "a")
+	require.NoError(t, err)
+	assert.Equal(t, int64(4), got.Version)
+}
+
+func TestRedisCache_TTL(t *testing.T) {
+	c, mr := newTestCache(t, config.CacheConfig{TTL: time.Minute})
+	ctx := context.Background()
+
+	require.NoError(t, c.Set(ctx, "a", order("a", 1)))
+	require.NoError(t, c.Restore(ctx, []*models.Order{order("b", 1)}))
+	assert.Equal(t, time.Minute, mr.TTL("test:a"))
+	assert.Equal(t, time.Minute, mr.TTL("test:b"))
+
+	mr.FastForward(2 * time.Minute)
+	_, err := c.Get(ctx, "a")
+	assert.ErrorIs(t, err, models.ErrOrderNotFound)
+}
+
+func TestRedisCache_Restore(t *testing.T) {
+	c, _ := newTestCache(t, config.CacheConfig{})
+	ctx := context.Background()
+
+	require.NoError(t, c.Set(ctx, "fresh", order("fresh", 1)))
+	require.NoError(t, c.Set(ctx, "a", order("a", 5)))
+
+	orders := []*models.Order{order("a", 4)}
+	for i := range restoreBatchSize + 10 {
+		orders = append(orders, order(fmt.Sprintf("o%d", i), 1))
+	}
+	require.NoError(t, c.Restore(ctx, orders))
+
+	got, err := c.Get(ctx, "a")
+	require.NoError(t, err)
+	assert.Equal(t, int64(5), got.Version, "восстановление не затирает более новую версию")
+
+	for _, uid := range []string{"fresh", "o0", fmt.Sprintf("o%d", restoreBatchSize+9)} {
+		_, err := c.Get(ctx, uid)
+		assert.NoError(t, err, uid)
+	}
+}
+
+func TestRedisCache_Restore_ReloadsFlushedScript(t *testing.T) {
+	c, _ := newTestCache(t, config.CacheConfig{})
+	ctx := context.Background()
+
+	require.NoError(t, c.client.ScriptFlush(ctx).Err())
+	require.NoError(t, c.Restore(ctx, []*models.Order{order("a", 1)}))
+
+	_, err := c.Get(ctx, "a")
+	assert.NoError(t, err)
+}
+
+func TestRedisCache_Delete(t *testing.T) {
+	c, _ := newTestCache(t, config.CacheConfig{})
+	ctx := context.Background()
+
+	require.NoError(t, c.Set(ctx, "a", order("a", 1)))
+	require.NoError(t, c.Delete(ctx, "a"))
+	require.NoError(t, c.Delete(ctx, "a"), "удаление отсутствующего заказа не ошибка")
+
+	_, err := c.Get(ctx, "a")
+	assert.ErrorIs(t, err, models.ErrOrderNotFound)
+}
+
+func TestRedisCache_Unavailable(t *testing.T) {
+	c, mr := newTestCache(t, config.CacheConfig{})
+	ctx := context.Background()
+
+	mr.Close()
+
+	_, err := c.Get(ctx, "a")
+	assert.Error(t, err)
+	assert.NotErrorIs(t, err, models.ErrOrderNotFound, "недоступность Redis не означает отсутствие заказа")
+	assert.Error(t, c.HealthCheck(ctx))
+}
+
+func TestNew_Unreachable(t *testing.T) {
+	mr := miniredis.RunT(t)
+	addr := mr.Addr()
+	mr.Close()
+
+	_, err := New(config.RedisConfig{Addr: addr, PingTimeout: time.Second}, config.CacheConfig{}, zap.NewNop())
+	assert.Error(t, err)
+}
@@ -0,0 +1,234 @@
+package snapshot
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/sunr3d/order-stream-processor/internal/interfaces/infra"
+	"github.com/sunr3d/order-stream-processor/models"
+)
+
+// Формат файла снимка:
+//
+//	magic    8 байт  "OSPCACHE"
+//	version  2 байта номер формата (big endian)
+//	body     gzip-поток JSON-строк: заголовок snapshotHeader, затем по одному заказу в строке
+//	checksum 32 байта SHA-256 всего, что записано до него
+const (
+	magic         = "OSPCACHE"
+	formatVersion = uint16(1)
+	prefixLen     = len(magic) + 2
+)
+
+var errCorrupt = errors.New("снимок поврежден")
+
+type snapshotHeader struct {
+	Watermark time.Time `json:"watermark"`
+	Count     int       `json:"count"`
+}
+
+type fileStore struct {
+	path   string
+	logger *zap.Logger
+}
+
+var _ infra.SnapshotStore = (*fileStore)(nil)
+
+func New(path string, logger *zap.Logger) infra.SnapshotStore {
+	return &fileStore{path: path, logger: logger}
+}
+
+// Save записывает снимок во временный файл рядом с path и атомарно переименовывает его,
+// поэтому прерванная запись не портит предыдущий снимок.
+func (s *fileStore) Save(ctx context.Context, snap *models.CacheSnapshot) error {
+	logger := s.logger.With(
+		zap.String("op", "snapshot.Save"),
+		zap.String("path", s.path),
+		zap.Int("count", len(snap.Orders)),
+	)
+
+	logger.Info("сохранение снимка кэша...")
+
+	tmp, err := os.CreateTemp(filepath.Dir(s.path), filepath.Base(s.path)+".tmp-*")
+	if err != nil {
+		logger.Error("ошибка при создании файла снимка", zap.Error(err))
+		return fmt.Errorf("os.CreateTemp: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	defer tmp.Close()
+
+	if err := encode(ctx, tmp, snap); err != nil {
+		logger.Error("ошибка при записи снимка", zap.Error(err))
+		return fmt.Errorf("encode: %w", err)
+	}
+	if err := tmp.Sync(); err != nil {
+		logger.Error("ошибка при записи снимка на диск", zap.Error(err))
+		return fmt.Errorf("tmp.Sync: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		logger.Error("ошибка при закрытии файла снимка", zap.Error(err))
+		return fmt.Errorf("tmp.Close: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), s.path); err != nil {
+		logger.Error("ошибка при замене файла снимка", zap.Error(err))
+		return fmt.Errorf("os.Rename: %w", err)
+	}
+
+	logger.Info("снимок кэша сохранен", zap.Time("watermark", snap.Watermark))
+	return nil
+}
+
+func (s *fileStore) Load(ctx context.Context) (*models.CacheSnapshot, error) {
+	logger := s.logger.With(
+		zap.String("op", "snapshot.Load"),
+		zap.String("path", s.path),
+	)
+
+	logger.Info("загрузка снимка кэша...")
+
+	f, err := os.Open(s.path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			logger.Info("снимок кэша не найден")
+			return nil, fmt.Errorf("%w: %s", infra.ErrNoSnapshot, s.path)
+		}
+		logger.Error("ошибка при открытии файла снимка", zap.Error(err))
+		return nil, fmt.Errorf("os.Open: %w", err)
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		logger.Error("ошибка при чтении файла снимка", zap.Error(err))
+		return nil, fmt.Errorf("f.Stat: %w", err)
+	}
+
+	snap, err := decode(ctx, f, info.Size())
+	if err != nil {
+		logger.Warn("снимок кэша не прочитан", zap.Error(err))
+		return nil, fmt.Errorf("decode: %w", err)
+	}
+
+	logger.Info("снимок кэша загружен",
+		zap.Int("count", len(snap.Orders)),
+		zap.Time("watermark", snap.Watermark),
+	)
+	return snap, nil
+}
+
+func encode(ctx context.Context, w io.Writer, snap *models.CacheSnapshot) error {
+	hash := sha256.New()
+	buf := bufio.NewWriter(io.MultiWriter(w, hash))
+
+	var prefix [prefixLen]byte
+	copy(prefix[:], magic)
+	binary.BigEndian.PutUint16(prefix[len(magic):], formatVersion)
+	if _, err := buf.Write(prefix[:]); err != nil {
+		return fmt.Errorf("buf.Write: %w", err)
+	}
+
+	zw := gzip.NewWriter(buf)
+	enc := json.NewEncoder(zw)
+	if err := enc.Encode(snapshotHeader{Watermark: snap.Watermark, Count: len(snap.Orders)}); err != nil {
+		return fmt.Errorf("enc.Encode(header): %w", err)
+	}
+	for _, order := range snap.Orders {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if err := enc.Encode(order); err != nil {
+			return fmt.Errorf("enc.Encode: %w", err)
+		}
+	}
+	if err := zw.Close(); err != nil {
+		return fmt.Errorf("zw.Close: %w", err)
+	}
+
+	if err := buf.Flush(); err != nil {
+		return fmt.Errorf("buf.Flush: %w", err)
+	}
+	if _, err := w.Write(hash.Sum(nil)); err != nil {
+		return fmt.Errorf("w.Write(checksum): %w", err)
+	}
+	return nil
+}
+
+// decode читает снимок размером size и сверяет контрольную сумму до того, как вернуть заказы.
+func decode(ctx context.Context, r io.Reader, size int64) (*models.CacheSnapshot, error) {
+	if size < int64(prefixLen+sha256.Size) {
+		return nil, fmt.Errorf("%w: файл слишком короткий", errCorrupt)
+	}
+
+	hash := sha256.New()
+	body := bufio.NewReader(io.TeeReader(io.LimitReader(r, size-sha256.Size), hash))
+
+	var prefix [prefixLen]byte
+	if _, err := io.ReadFull(body, prefix[:]); err != nil {
+		return nil, fmt.Errorf("%w: %w", errCorrupt, err)
+	}
+	if string(prefix[:len(magic)]) != magic {
+		return nil, fmt.Errorf("%w: неизвестная сигнатура", errCorrupt)
+	}
+	if v := binary.BigEndian.Uint16(prefix[len(magic):]); v != formatVersion {
+		return nil, fmt.Errorf("неподдерживаемая версия формата снимка: %d", v)
+	}
+
+	zr, err := gzip.NewReader(body)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", errCorrupt, err)
+	}
+	dec := json.NewDecoder(zr)
+
+	var header snapshotHeader
+	if err := dec.Decode(&header); err != nil {
+		return nil, fmt.Errorf("%w: заголовок: %w", errCorrupt, err)
+	}
+	if header.Count < 0 {
+		return nil, fmt.Errorf("%w: отрицательное число заказов", errCorrupt)
+	}
+
+	snap := &models.CacheSnapshot{Watermark: header.Watermark}
+	for {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		var order models.Order
+		if err := dec.Decode(&order); err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			return nil, fmt.Errorf("%w: заказ %d: %w", errCorrupt, len(snap.Orders), err)
+		}
+		snap.Orders = append(snap.Orders, &order)
+	}
+	if len(snap.Orders) != header.Count {
+		return nil, fmt.Errorf("%w: ожидалось %d заказов, прочитано %d", errCorrupt, header.Count, len(snap.Orders))
+	}
+
+	// Остаток до контрольной суммы тоже должен войти в хэш
+	if _, err := io.Copy(io.Discard, body); err != nil {
+		return nil, fmt.Errorf("%w: %w", errCorrupt, err)
+	}
+	var checksum [sha256.Size]byte
+	if _, err := io.ReadFull(r, checksum[:]); err != nil {
+		return nil, fmt.Errorf("%w: контрольная сумма: %w", errCorrupt, err)
+	}
+	if !bytes.Equal(checksum[:], hash.Sum(nil)) {
+		return nil, fmt.Errorf("%w: контрольная сумма не совпадает", errCorrupt)
+	}
+
+	return snap, nil
+}
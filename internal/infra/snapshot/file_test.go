@@ -0,0 +1,96 @@
+package snapshot_test
+
+import (
+	"context"
+	"encoding/binary"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/sunr3d/order-stream-processor/internal/infra/snapshot"
+	"github.com/sunr3d/order-stream-processor/internal/interfaces/infra"
+	"github.com/sunr3d/order-stream-processor/models"
+)
+
+func testSnapshot() *models.CacheSnapshot {
+	return &models.CacheSnapshot{
+		Watermark: time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC),
+		Orders: []*models.Order{
+			{OrderUID: "a", CustomerID: "test", Version: 2, Items: []models.Item{{ChrtID: 1, Name: "Mascaras"}}},
+			{OrderUID: "b", CustomerID: "test", Version: 1},
+		},
+	}
+}
+
+func saved(t *testing.T) (infra.SnapshotStore, string) {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "cache.snapshot")
+	store := snapshot.New(path, zap.NewNop())
+	require.NoError(t, store.Save(context.Background(), testSnapshot()))
+	return store, path
+}
+
+func TestFileStore_RoundTrip(t *testing.T) {
+	store, path := saved(t)
+
+	snap, err := store.Load(context.Background())
+
+	require.NoError(t, err)
+	want := testSnapshot()
+	assert.True(t, want.Watermark.Equal(snap.Watermark))
+	assert.Equal(t, want.Orders, snap.Orders)
+
+	entries, err := os.ReadDir(filepath.Dir(path))
+	require.NoError(t, err)
+	assert.Len(t, entries, 1, "временный файл удаляется после записи")
+}
+
+func TestFileStore_Missing(t *testing.T) {
+	store := snapshot.New(filepath.Join(t.TempDir(), "cache.snapshot"), zap.NewNop())
+
+	_, err := store.Load(context.Background())
+
+	assert.ErrorIs(t, err, infra.ErrNoSnapshot)
+}
+
+func TestFileStore_Corrupt(t *testing.T) {
+	tests := []struct {
+		name   string
+		damage func(data []byte) []byte
+	}{
+		{"flipped byte", func(data []byte) []byte {
+			data[len(data)/2] ^= 0xff
+			return data
+		}},
+		{"truncated", func(data []byte) []byte { return data[:len(data)-10] }},
+		{"wrong checksum", func(data []byte) []byte {
+			data[len(data)-1] ^= 0xff
+			return data
+		}},
+		{"unsupported version", func(data []byte) []byte {
+			binary.BigEndian.PutUint16(data[8:], 99)
+			return data
+		}},
+		{"empty", func([]byte) []byte { return nil }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store, path := saved(t)
+			data, err := os.ReadFile(path)
+			require.NoError(t, err)
+			require.NoError(t, os.WriteFile(path, tt.damage(data), 0o600))
+
+			snap, err := store.Load(context.Background())
+
+			require.Error(t, err)
+			assert.NotErrorIs(t, err, infra.ErrNoSnapshot)
+			assert.Nil(t, snap)
+		})
+	}
+}
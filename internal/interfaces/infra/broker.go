@@ -1,8 +1,39 @@
 package infra
 
-import "context"
+import (
+	"context"
+	"errors"
+)
+
+// ErrPermanent помечает ошибки обработки сообщения, которые не исчезнут при повторной попытке
+// (некорректный JSON, ошибки валидации). Такие сообщения сразу отправляются в DLQ.
+var ErrPermanent = errors.New("неустранимая ошибка обработки сообщения")
+
+type MessageHandler func(ctx context.Context, msg []byte) error
+
+// BatchHandler обрабатывает пачку сообщений и возвращает итог по каждому сообщению
+// (срез той же длины, nil — сообщение обработано).
+type BatchHandler func(ctx context.Context, msgs [][]byte) []error
+
+// Subscription связывает топик с обработчиком его сообщений.
+// BatchHandler необязателен и используется вместо Handler, если включена пакетная обработка.
+type Subscription struct {
+	Topic        string
+	Handler      MessageHandler
+	BatchHandler BatchHandler
+}
+
+// Message — сообщение для публикации в брокер.
+type Message struct {
+	Topic   string
+	Key     []byte
+	Value   []byte
+	Headers map[string]string
+}
 
 //go:generate go run github.com/vektra/mockery/v2@v2.53.2 --name=Broker --output=../../../mocks --filename=mock_broker.go --with-expecter
 type Broker interface {
-	StartConsumer(ctx context.Context, handler func(context.Context, []byte) error) error
+	StartConsumer(ctx context.Context, subs ...Subscription) error
+	ReplayDLQ(ctx context.Context) (int, error)
+	Publish(ctx context.Context, msg Message) error
 }
@@ -10,5 +10,37 @@ import (
 type Cache interface {
 	Set(ctx context.Context, orderUID string, order *models.Order) error
 	Get(ctx context.Context, orderUID string) (*models.Order, error)
+	// Restore добавляет заказы в кэш, не очищая его: может вызываться пачками и одновременно с Set.
+	// Как и в Set, более новая версия заказа в кэше не заменяется.
 	Restore(ctx context.Context, orders []*models.Order) error
+	// Delete удаляет заказ из кэша; отсутствие заказа в кэше не считается ошибкой.
+	Delete(ctx context.Context, orderUID string) error
+}
+
+// CacheStats — счетчики работы кэша.
+type CacheStats struct {
+	Hits        uint64
+	Misses      uint64
+	Evictions   uint64
+	Expirations uint64
+	Entries     int
+	Bytes       int64
+}
+
+// CacheStatsProvider реализуется кэшами, которые ведут статистику обращений.
+type CacheStatsProvider interface {
+	Stats() CacheStats
+}
+
+// CacheIndex реализуется кэшами со вторичными индексами заказов.
+type CacheIndex interface {
+	// Lookup возвращает заказы из кэша по вторичному ключу, от новых к старым.
+	// Кэш может содержать не все заказы с этим ключом.
+	Lookup(ctx context.Context, lookup models.OrderLookup) ([]*models.Order, error)
+}
+
+// CacheDumper реализуется кэшами, содержимое которых можно сохранить в снимок.
+type CacheDumper interface {
+	// Dump возвращает все действующие записи кэша.
+	Dump() []*models.Order
 }
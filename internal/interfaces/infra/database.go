@@ -2,13 +2,47 @@ package infra
 
 import (
 	"context"
+	"database/sql"
+	"time"
 
 	"github.com/sunr3d/order-stream-processor/models"
 )
 
 //go:generate go run github.com/vektra/mockery/v2@v2.53.2 --name=Database --output=../../../mocks --filename=mock_database.go --with-expecter
 type Database interface {
+	// Create сохраняет новый заказ. Заказ, удаленный или обезличенный ранее (есть в журнале аудита),
+	// не создается повторно: возвращается models.ErrOrderErased.
 	Create(ctx context.Context, order *models.Order) error
+	// CreateBatch сохраняет заказы одной транзакцией. Возвращает итог по каждому заказу
+	// (nil, models.ErrOrderAlreadyExists или models.ErrOrderErased) либо ошибку, из-за которой не сохранен ни один заказ.
+	CreateBatch(ctx context.Context, orders []*models.Order) ([]error, error)
 	Read(ctx context.Context, orderUID string) (*models.Order, error)
 	ReadAll(ctx context.Context) ([]*models.Order, error)
+	// ScanChangedSince передает в fn пачками не больше chunkSize заказы, измененные начиная с since
+	// (с нулевым since — все заказы), и возвращает удаленные заказы и время БД, на которое получены изменения.
+	// Ошибка fn прерывает чтение и возвращается как есть.
+	ScanChangedSince(ctx context.Context, since time.Time, chunkSize int,
+		fn func(ctx context.Context, orders []*models.Order) error) (*models.OrderChanges, error)
+	// Update заменяет заказ, если его версия равна expectedVersion (0 — без проверки),
+	// иначе возвращает models.ErrVersionConflict. Новая версия записывается в order.Version.
+	Update(ctx context.Context, order *models.Order, expectedVersion int64) error
+	// Upsert сохраняет новый заказ или заменяет сохраненный, если присланный новее.
+	// Для удаленного или обезличенного заказа возвращает models.ErrOrderErased.
+	Upsert(ctx context.Context, order *models.Order) (models.UpsertResult, error)
+	List(ctx context.Context, filter models.OrderFilter) (*models.OrderPage, error)
+	// Lookup возвращает до lookup.Limit заказов по вторичному ключу, от новых к старым.
+	Lookup(ctx context.Context, lookup models.OrderLookup) ([]*models.Order, error)
+	// UpdateStatus меняет статус заказа. Если заказ уже в статусе change.To, он возвращается без изменений.
+	UpdateStatus(ctx context.Context, change models.StatusChange) (*models.Order, error)
+	StatusHistory(ctx context.Context, orderUID string) ([]models.StatusChange, error)
+	// Delete удаляет заказ и записывает удаление в журнал аудита.
+	Delete(ctx context.Context, req models.DeletionRequest) error
+	// EraseCustomer обезличивает персональные данные получателя во всех заказах покупателя
+	// и записывает обезличивание в журнал аудита.
+	EraseCustomer(ctx context.Context, req models.ErasureRequest) (*models.ErasureResult, error)
+}
+
+// DBStatsProvider реализуется хранилищами поверх пула соединений database/sql.
+type DBStatsProvider interface {
+	Stats() sql.DBStats
 }
@@ -0,0 +1,9 @@
+package infra
+
+import "context"
+
+// HealthChecker реализуется компонентами, состояние которых учитывается в readiness-проверке.
+// HealthCheck должен уважать дедлайн контекста.
+type HealthChecker interface {
+	HealthCheck(ctx context.Context) error
+}
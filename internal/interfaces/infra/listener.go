@@ -0,0 +1,24 @@
+package infra
+
+import (
+	"context"
+	"time"
+
+	"github.com/sunr3d/order-stream-processor/models"
+)
+
+//go:generate go run github.com/vektra/mockery/v2@v2.53.2 --name=ChangeListener --output=../../../mocks --filename=mock_change_listener.go --with-expecter
+type ChangeListener interface {
+	// Listen передает уведомления об изменениях заказов в handler до отмены ctx
+	// и переподключается при обрыве соединения.
+	Listen(ctx context.Context, handler ChangeHandler) error
+}
+
+// ChangeHandler обрабатывает уведомления ChangeListener. OrderChanged вызывается последовательно,
+// Resync — в отдельной горутине, не пересекаясь с другим Resync, но параллельно с OrderChanged.
+type ChangeHandler interface {
+	OrderChanged(ctx context.Context, change models.OrderChange)
+	// Resync вызывается после подписки и каждого переподключения: уведомления, отправленные
+	// начиная с lostSince (последний момент, когда соединение было точно активно), могли быть потеряны.
+	Resync(ctx context.Context, lostSince time.Time)
+}
@@ -0,0 +1,18 @@
+package infra
+
+import (
+	"context"
+	"time"
+
+	"github.com/sunr3d/order-stream-processor/models"
+)
+
+//go:generate go run github.com/vektra/mockery/v2@v2.53.2 --name=Outbox --output=../../../mocks --filename=mock_outbox.go --with-expecter
+type Outbox interface {
+	// ProcessOutbox передает publish до limit неопубликованных событий в порядке записи, если outbox
+	// не обрабатывается другой репликой. Опубликованные события помечаются после публикации;
+	// на первой ошибке обработка останавливается, а оставшиеся события будут переданы повторно.
+	ProcessOutbox(ctx context.Context, limit int, publish func(context.Context, models.OrderEvent) error) (int, error)
+	// DeletePublishedOutbox удаляет события, опубликованные раньше before, и возвращает их число.
+	DeletePublishedOutbox(ctx context.Context, before time.Time) (int64, error)
+}
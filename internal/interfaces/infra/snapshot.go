@@ -0,0 +1,20 @@
+package infra
+
+import (
+	"context"
+	"errors"
+
+	"github.com/sunr3d/order-stream-processor/models"
+)
+
+// ErrNoSnapshot возвращается SnapshotStore.Load, если снимок еще не сохранялся.
+var ErrNoSnapshot = errors.New("снимок кэша не найден")
+
+//go:generate go run github.com/vektra/mockery/v2@v2.53.2 --name=SnapshotStore --output=../../../mocks --filename=mock_snapshot_store.go --with-expecter
+type SnapshotStore interface {
+	// Save атомарно заменяет сохраненный снимок.
+	Save(ctx context.Context, snapshot *models.CacheSnapshot) error
+	// Load возвращает сохраненный снимок, ErrNoSnapshot, если его нет,
+	// или ошибку, если снимок поврежден или записан в неподдерживаемом формате.
+	Load(ctx context.Context) (*models.CacheSnapshot, error)
+}
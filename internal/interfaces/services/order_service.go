@@ -9,6 +9,17 @@ import (
 //go:generate go run github.com/vektra/mockery/v2@v2.53.2 --name=OrderService --output=../../../mocks --filename=mock_order_service.go --with-expecter
 type OrderService interface {
 	ProcessOrder(ctx context.Context, order *models.Order) error
+	ProcessOrders(ctx context.Context, orders []*models.Order) []error
+	UpdateOrder(ctx context.Context, order *models.Order, expectedVersion int64) error
+	UpsertOrder(ctx context.Context, order *models.Order) (models.UpsertResult, error)
 	GetOrder(ctx context.Context, orderUID string) (*models.Order, error)
 	GetAllOrders(ctx context.Context) ([]*models.Order, error)
+	ListOrders(ctx context.Context, filter models.OrderFilter) (*models.OrderPage, error)
+	LookupOrders(ctx context.Context, lookup models.OrderLookup) (*models.LookupResult, error)
+	ChangeStatus(ctx context.Context, change models.StatusChange) (*models.Order, error)
+	GetStatusHistory(ctx context.Context, orderUID string) ([]models.StatusChange, error)
+	DeleteOrder(ctx context.Context, req models.DeletionRequest) error
+	EraseCustomer(ctx context.Context, req models.ErasureRequest) (*models.ErasureResult, error)
+	// InvalidateOrder убирает из кэша устаревшую копию заказа, измененного вне этой реплики.
+	InvalidateOrder(ctx context.Context, change models.OrderChange)
 }
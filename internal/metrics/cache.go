@@ -0,0 +1,95 @@
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/sunr3d/order-stream-processor/internal/interfaces/infra"
+)
+
+var (
+	cacheWarmupLoaded = factory.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: "cache",
+		Name:      "warmup_loaded_orders",
+		Help:      "Количество заказов, загруженных из БД при текущем или последнем восстановлении кэша.",
+	})
+
+	cacheWarmupDone = factory.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: "cache",
+		Name:      "warmup_done",
+		Help:      "1, если кэш восстановлен после старта, иначе 0.",
+	})
+)
+
+// CacheWarmupProgress обновляет число заказов, загруженных при восстановлении кэша.
+func CacheWarmupProgress(loaded int) {
+	cacheWarmupLoaded.Set(float64(loaded))
+}
+
+// CacheWarmupDone отмечает завершение восстановления кэша.
+func CacheWarmupDone() {
+	cacheWarmupDone.Set(1)
+}
+
+// cacheCollector снимает статистику кэша в момент сбора метрик.
+type cacheCollector struct {
+	provider infra.CacheStatsProvider
+
+	hits        *prometheus.Desc
+	misses      *prometheus.Desc
+	evictions   *prometheus.Desc
+	expirations *prometheus.Desc
+	entries     *prometheus.Desc
+	bytes       *prometheus.Desc
+	hitRatio    *prometheus.Desc
+}
+
+// RegisterCache регистрирует метрики кэша: попадания, промахи, вытеснения, размер и долю попаданий.
+func RegisterCache(provider infra.CacheStatsProvider) error {
+	return Registry.Register(newCacheCollector(provider))
+}
+
+func newCacheCollector(provider infra.CacheStatsProvider) *cacheCollector {
+	desc := func(name, help string) *prometheus.Desc {
+		return prometheus.NewDesc(prometheus.BuildFQName(namespace, "cache", name), help, nil, nil)
+	}
+
+	return &cacheCollector{
+		provider:    provider,
+		hits:        desc("hits_total", "Количество попаданий в кэш."),
+		misses:      desc("misses_total", "Количество промахов кэша."),
+		evictions:   desc("evictions_total", "Количество записей, вытесненных из кэша по лимитам."),
+		expirations: desc("expirations_total", "Количество записей, удаленных из кэша по TTL."),
+		entries:     desc("entries", "Текущее число записей в кэше."),
+		bytes:       desc("bytes", "Приблизительный объем кэша в байтах."),
+		hitRatio:    desc("hit_ratio", "Доля попаданий среди всех обращений к кэшу."),
+	}
+}
+
+func (c *cacheCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.hits
+	ch <- c.misses
+	ch <- c.evictions
+	ch <- c.expirations
+	ch <- c.entries
+	ch <- c.bytes
+	ch <- c.hitRatio
+}
+
+func (c *cacheCollector) Collect(ch chan<- prometheus.Metric) {
+	stats := c.provider.Stats()
+
+	var ratio float64
+	if total := stats.Hits + stats.Misses; total > 0 {
+		ratio = float64(stats.Hits) / float64(total)
+	}
+
+	ch <- prometheus.MustNewConstMetric(c.hits, prometheus.CounterValue, float64(stats.Hits))
+	ch <- prometheus.MustNewConstMetric(c.misses, prometheus.CounterValue, float64(stats.Misses))
+	ch <- prometheus.MustNewConstMetric(c.evictions, prometheus.CounterValue, float64(stats.Evictions))
+	ch <- prometheus.MustNewConstMetric(c.expirations, prometheus.CounterValue, float64(stats.Expirations))
+	ch <- prometheus.MustNewConstMetric(c.entries, prometheus.GaugeValue, float64(stats.Entries))
+	ch <- prometheus.MustNewConstMetric(c.bytes, prometheus.GaugeValue, float64(stats.Bytes))
+	ch <- prometheus.MustNewConstMetric(c.hitRatio, prometheus.GaugeValue, ratio)
+}
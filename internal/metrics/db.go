@@ -0,0 +1,87 @@
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/sunr3d/order-stream-processor/internal/interfaces/infra"
+)
+
+var dbQueryDuration = factory.NewHistogramVec(prometheus.HistogramOpts{
+	Namespace: namespace,
+	Subsystem: "db",
+	Name:      "query_duration_seconds",
+	Help:      "Длительность операций с БД.",
+	Buckets:   []float64{.001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5},
+}, []string{"query"})
+
+// QueryTimer замеряет длительность операции с БД, используется как
+//
+//	defer metrics.QueryTimer("read").ObserveDuration()
+func QueryTimer(query string) *prometheus.Timer {
+	return prometheus.NewTimer(dbQueryDuration.WithLabelValues(query))
+}
+
+// dbStatsCollector снимает статистику пула соединений в момент сбора метрик.
+type dbStatsCollector struct {
+	provider infra.DBStatsProvider
+
+	maxOpen       *prometheus.Desc
+	open          *prometheus.Desc
+	inUse         *prometheus.Desc
+	idle          *prometheus.Desc
+	waitCount     *prometheus.Desc
+	waitDuration  *prometheus.Desc
+	maxIdleClosed *prometheus.Desc
+	maxIdleTime   *prometheus.Desc
+	maxLifetime   *prometheus.Desc
+}
+
+// RegisterDB регистрирует метрики пула соединений с БД.
+func RegisterDB(provider infra.DBStatsProvider) error {
+	return Registry.Register(newDBStatsCollector(provider))
+}
+
+func newDBStatsCollector(provider infra.DBStatsProvider) *dbStatsCollector {
+	desc := func(name, help string) *prometheus.Desc {
+		return prometheus.NewDesc(prometheus.BuildFQName(namespace, "db", name), help, nil, nil)
+	}
+
+	return &dbStatsCollector{
+		provider:      provider,
+		maxOpen:       desc("max_open_connections", "Максимальное число открытых соединений."),
+		open:          desc("open_connections", "Число открытых соединений."),
+		inUse:         desc("in_use_connections", "Число используемых соединений."),
+		idle:          desc("idle_connections", "Число простаивающих соединений."),
+		waitCount:     desc("wait_count_total", "Количество ожиданий свободного соединения."),
+		waitDuration:  desc("wait_duration_seconds_total", "Суммарное время ожидания свободного соединения."),
+		maxIdleClosed: desc("max_idle_closed_total", "Количество соединений, закрытых из-за лимита простаивающих."),
+		maxIdleTime:   desc("max_idle_time_closed_total", "Количество соединений, закрытых по времени простоя."),
+		maxLifetime:   desc("max_lifetime_closed_total", "Количество соединений, закрытых по времени жизни."),
+	}
+}
+
+func (c *dbStatsCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.maxOpen
+	ch <- c.open
+	ch <- c.inUse
+	ch <- c.idle
+	ch <- c.waitCount
+	ch <- c.waitDuration
+	ch <- c.maxIdleClosed
+	ch <- c.maxIdleTime
+	ch <- c.maxLifetime
+}
+
+func (c *dbStatsCollector) Collect(ch chan<- prometheus.Metric) {
+	stats := c.provider.Stats()
+
+	ch <- prometheus.MustNewConstMetric(c.maxOpen, prometheus.GaugeValue, float64(stats.MaxOpenConnections))
+	ch <- prometheus.MustNewConstMetric(c.open, prometheus.GaugeValue, float64(stats.OpenConnections))
+	ch <- prometheus.MustNewConstMetric(c.inUse, prometheus.GaugeValue, float64(stats.InUse))
+	ch <- prometheus.MustNewConstMetric(c.idle, prometheus.GaugeValue, float64(stats.Idle))
+	ch <- prometheus.MustNewConstMetric(c.waitCount, prometheus.CounterValue, float64(stats.WaitCount))
+	ch <- prometheus.MustNewConstMetric(c.waitDuration, prometheus.CounterValue, stats.WaitDuration.Seconds())
+	ch <- prometheus.MustNewConstMetric(c.maxIdleClosed, prometheus.CounterValue, float64(stats.MaxIdleClosed))
+	ch <- prometheus.MustNewConstMetric(c.maxIdleTime, prometheus.CounterValue, float64(stats.MaxIdleTimeClosed))
+	ch <- prometheus.MustNewConstMetric(c.maxLifetime, prometheus.CounterValue, float64(stats.MaxLifetimeClosed))
+}
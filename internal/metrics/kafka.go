@@ -0,0 +1,72 @@
+package metrics
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	kafkaConsumed = factory.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "kafka",
+		Name:      "messages_consumed_total",
+		Help:      "Количество прочитанных из Kafka сообщений.",
+	}, []string{"topic", "partition"})
+
+	kafkaFailed = factory.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "kafka",
+		Name:      "messages_failed_total",
+		Help:      "Количество сообщений, обработка которых завершилась ошибкой после всех попыток.",
+	}, []string{"topic", "partition"})
+
+	kafkaSkipped = factory.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "kafka",
+		Name:      "messages_skipped_total",
+		Help:      "Количество необработанных сообщений, пропущенных без отправки в DLQ.",
+	}, []string{"topic", "partition"})
+
+	kafkaDuration = factory.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "kafka",
+		Name:      "processing_duration_seconds",
+		Help:      "Длительность обработки сообщения с учетом повторных попыток.",
+		Buckets:   []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10, 30, 60},
+	}, []string{"topic", "partition"})
+
+	kafkaLag = factory.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: "kafka",
+		Name:      "consumer_lag",
+		Help:      "Отставание консьюмера: число сообщений в партиции после последнего прочитанного.",
+	}, []string{"topic", "partition"})
+)
+
+func partitionLabel(partition int32) string {
+	return strconv.FormatInt(int64(partition), 10)
+}
+
+// KafkaMessageConsumed учитывает прочитанное сообщение и обновляет отставание по партиции.
+// highWaterMark — оффсет, который получит следующее записанное в партицию сообщение.
+func KafkaMessageConsumed(topic string, partition int32, offset, highWaterMark int64) {
+	p := partitionLabel(partition)
+	kafkaConsumed.WithLabelValues(topic, p).Inc()
+	kafkaLag.WithLabelValues(topic, p).Set(float64(max(highWaterMark-offset-1, 0)))
+}
+
+// KafkaMessageProcessed учитывает длительность обработки сообщения и ее результат.
+func KafkaMessageProcessed(topic string, partition int32, d time.Duration, err error) {
+	p := partitionLabel(partition)
+	kafkaDuration.WithLabelValues(topic, p).Observe(d.Seconds())
+	if err != nil {
+		kafkaFailed.WithLabelValues(topic, p).Inc()
+	}
+}
+
+// KafkaMessageSkipped учитывает необработанное сообщение, оффсет которого закоммичен без записи в DLQ.
+func KafkaMessageSkipped(topic string, partition int32) {
+	kafkaSkipped.WithLabelValues(topic, partitionLabel(partition)).Inc()
+}
@@ -0,0 +1,103 @@
+package metrics
+
+import (
+	"database/sql"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sunr3d/order-stream-processor/internal/interfaces/infra"
+)
+
+type statsFunc func() infra.CacheStats
+
+func (f statsFunc) Stats() infra.CacheStats { return f() }
+
+type dbStatsFunc func() sql.DBStats
+
+func (f dbStatsFunc) Stats() sql.DBStats { return f() }
+
+func TestCacheCollector(t *testing.T) {
+	collector := newCacheCollector(statsFunc(func() infra.CacheStats {
+		return infra.CacheStats{Hits: 3, Misses: 1, Evictions: 2, Entries: 10, Bytes: 2048}
+	}))
+
+	expected := `
+# HELP order_stream_cache_hit_ratio Доля попаданий среди всех обращений к кэшу.
+# TYPE order_stream_cache_hit_ratio gauge
+order_stream_cache_hit_ratio 0.75
+# HELP order_stream_cache_entries Текущее число записей в кэше.
+# TYPE order_stream_cache_entries gauge
+order_stream_cache_entries 10
+# HELP order_stream_cache_evictions_total Количество записей, вытесненных из кэша по лимитам.
+# TYPE order_stream_cache_evictions_total counter
+order_stream_cache_evictions_total 2
+`
+	err := testutil.CollectAndCompare(collector, strings.NewReader(expected),
+		"order_stream_cache_hit_ratio", "order_stream_cache_entries", "order_stream_cache_evictions_total")
+	assert.NoError(t, err)
+}
+
+func TestCacheCollector_NoRequests(t *testing.T) {
+	collector := newCacheCollector(statsFunc(func() infra.CacheStats { return infra.CacheStats{} }))
+
+	expected := `
+# HELP order_stream_cache_hit_ratio Доля попаданий среди всех обращений к кэшу.
+# TYPE order_stream_cache_hit_ratio gauge
+order_stream_cache_hit_ratio 0
+`
+	err := testutil.CollectAndCompare(collector, strings.NewReader(expected), "order_stream_cache_hit_ratio")
+	assert.NoError(t, err)
+}
+
+func TestDBStatsCollector(t *testing.T) {
+	collector := newDBStatsCollector(dbStatsFunc(func() sql.DBStats {
+		return sql.DBStats{OpenConnections: 5, InUse: 2, Idle: 3, WaitDuration: 1500 * time.Millisecond}
+	}))
+
+	assert.Equal(t, 9, testutil.CollectAndCount(collector))
+
+	expected := `
+# HELP order_stream_db_in_use_connections Число используемых соединений.
+# TYPE order_stream_db_in_use_connections gauge
+order_stream_db_in_use_connections 2
+# HELP order_stream_db_wait_duration_seconds_total Суммарное время ожидания свободного соединения.
+# TYPE order_stream_db_wait_duration_seconds_total counter
+order_stream_db_wait_duration_seconds_total 1.5
+`
+	err := testutil.CollectAndCompare(collector, strings.NewReader(expected),
+		"order_stream_db_in_use_connections", "order_stream_db_wait_duration_seconds_total")
+	assert.NoError(t, err)
+}
+
+func TestKafkaMessageConsumed_Lag(t *testing.T) {
+	KafkaMessageConsumed("lag-test", 1, 41, 50)
+	assert.Equal(t, float64(8), testutil.ToFloat64(kafkaLag.WithLabelValues("lag-test", "1")))
+
+	KafkaMessageConsumed("lag-test", 1, 49, 50)
+	assert.Equal(t, float64(0), testutil.ToFloat64(kafkaLag.WithLabelValues("lag-test", "1")))
+	assert.Equal(t, float64(2), testutil.ToFloat64(kafkaConsumed.WithLabelValues("lag-test", "1")))
+}
+
+func TestRegistry_Gather(t *testing.T) {
+	ObserveHTTPRequest("GET", "GET /order/{order_uid}", 200, 10*time.Millisecond)
+
+	families, err := Registry.Gather()
+	require.NoError(t, err)
+
+	names := make(map[string]bool, len(families))
+	for _, f := range families {
+		names[f.GetName()] = true
+	}
+	assert.True(t, names["order_stream_http_requests_total"])
+	assert.True(t, names["go_goroutines"])
+
+	assert.Equal(t, float64(1), testutil.ToFloat64(
+		httpRequests.With(prometheus.Labels{"method": "GET", "route": "GET /order/{order_uid}", "status": "200"}),
+	))
+}
@@ -0,0 +1,15 @@
+package metrics
+
+import "github.com/prometheus/client_golang/prometheus"
+
+var orderRuleViolations = factory.NewCounterVec(prometheus.CounterOpts{
+	Namespace: namespace,
+	Subsystem: "orders",
+	Name:      "validation_violations_total",
+	Help:      "Количество нарушений правил валидации заказов по правилу и режиму (reject — заказ отклонен, warn — принят).",
+}, []string{"rule", "mode"})
+
+// OrderRuleViolation учитывает нарушение правила валидации заказа.
+func OrderRuleViolation(rule, mode string) {
+	orderRuleViolations.WithLabelValues(rule, mode).Inc()
+}
@@ -0,0 +1,260 @@
+package middleware
+
+import (
+	"bytes"
+	"container/list"
+	"crypto/sha256"
+	"errors"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/sunr3d/order-stream-processor/internal/httpx"
+)
+
+const (
+	// IdempotencyKeyHeader — заголовок, которым клиент помечает запрос, безопасный для повтора.
+	IdempotencyKeyHeader = "Idempotency-Key"
+	// IdempotentReplayedHeader выставляется в ответе, повторенном из кэша.
+	IdempotentReplayedHeader = "Idempotent-Replayed"
+
+	maxIdempotencyKeyLen = 255
+)
+
+// IdempotencyStore хранит ответы на запросы с Idempotency-Key в течение ttl в памяти реплики:
+// повтор, попавший на другую реплику, выполняется заново.
+//
+// Записей не больше maxEntries (0 — без ограничения): при заполнении вытесняется самый старый
+// сохраненный ответ, а если все записи заняты выполняющимися запросами, новый ключ не принимается.
+type IdempotencyStore struct {
+	mu         sync.Mutex
+	ttl        time.Duration
+	maxEntries int
+	entries    map[string]*idempotencyEntry
+	// completed — ключи сохраненных ответов в порядке завершения, то есть истечения
+	completed *list.List
+	lastSweep time.Time
+	now       func() time.Time
+}
+
+type idempotencyEntry struct {
+	fingerprint [sha256.Size]byte
+	done        bool
+	status      int
+	header      http.Header
+	body        []byte
+	expiresAt   time.Time
+	elem        *list.Element
+}
+
+// idempotencyState — результат попытки занять ключ.
+type idempotencyState int
+
+const (
+	idempotencyNew idempotencyState = iota
+	idempotencyInFlight
+	idempotencyMismatch
+	idempotencyReplay
+	idempotencyFull
+)
+
+func NewIdempotencyStore(ttl time.Duration, maxEntries int) *IdempotencyStore {
+	return &IdempotencyStore{
+		ttl:        ttl,
+		maxEntries: maxEntries,
+		entries:    make(map[string]*idempotencyEntry),
+		completed:  list.New(),
+		now:        time.Now,
+	}
+}
+
+// begin занимает ключ под новый запрос или возвращает состояние уже известного запроса.
+func (s *IdempotencyStore) begin(key string, fingerprint [sha256.Size]byte) (idempotencyState, *idempotencyEntry) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	s.sweep(now)
+
+	if e, ok := s.entries[key]; ok && now.Before(e.expiresAt) {
+		switch {
+		case e.fingerprint != fingerprint:
+			return idempotencyMismatch, nil
+		case !e.done:
+			return idempotencyInFlight, nil
+		default:
+			return idempotencyReplay, e
+		}
+	}
+
+	s.remove(key)
+	if s.maxEntries > 0 && len(s.entries) >= s.maxEntries {
+		oldest := s.completed.Front()
+		if oldest == nil {
+			return idempotencyFull, nil
+		}
+		s.remove(oldest.Value.(string))
+	}
+
+	s.entries[key] = &idempotencyEntry{fingerprint: fingerprint, expiresAt: now.Add(s.ttl)}
+	return idempotencyNew, nil
+}
+
+// finish сохраняет ответ, TTL отсчитывается от завершения запроса.
+func (s *IdempotencyStore) finish(key string, status int, header http.Header, body []byte) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if e, ok := s.entries[key]; ok && !e.done {
+		e.done = true
+		e.status, e.header, e.body = status, header, body
+		e.expiresAt = s.now().Add(s.ttl)
+		e.elem = s.completed.PushBack(key)
+	}
+}
+
+// release освобождает ключ, чтобы запрос можно было повторить.
+func (s *IdempotencyStore) release(key string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.remove(key)
+}
+
+func (s *IdempotencyStore) remove(key string) {
+	e, ok := s.entries[key]
+	if !ok {
+		return
+	}
+	if e.elem != nil {
+		s.completed.Remove(e.elem)
+	}
+	delete(s.entries, key)
+}
+
+// sweep удаляет истекшие записи не чаще раза в минуту.
+func (s *IdempotencyStore) sweep(now time.Time) {
+	if now.Sub(s.lastSweep) < time.Minute {
+		return
+	}
+	s.lastSweep = now
+
+	for key, e := range s.entries {
+		if !now.Before(e.expiresAt) {
+			s.remove(key)
+		}
+	}
+}
+
+// responseCapture передает ответ клиенту и одновременно запоминает его.
+type responseCapture struct {
+	statusRecorder
+	body bytes.Buffer
+}
+
+func (c *responseCapture) Write(b []byte) (int, error) {
+	c.body.Write(b)
+	return c.statusRecorder.Write(b)
+}
+
+// Idempotency повторяет сохраненный ответ на изменяющий запрос с тем же Idempotency-Key,
+// методом и путем, не вызывая обработчик повторно. Тот же ключ с другим телом запроса
+// отклоняется с 422, одновременный повтор еще выполняющегося запроса — с 409.
+// Ответы 5xx не сохраняются, такой запрос можно повторить с тем же ключом.
+// Тело запроса читается целиком для сравнения с повтором, поэтому тело больше maxBody отклоняется с 413.
+func Idempotency(store *IdempotencyStore, maxBody int64, log *zap.Logger) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			key := r.Header.Get(IdempotencyKeyHeader)
+			if key == "" || !isMutating(r.Method) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			logger := log.With(
+				zap.String("op", "middleware.Idempotency"),
+				zap.String("method", r.Method),
+				zap.String("url", r.URL.Path),
+				zap.String("idempotency_key", key),
+			)
+
+			if len(key) > maxIdempotencyKeyLen {
+				_ = httpx.HttpError(w, http.StatusBadRequest, "Idempotency-Key слишком длинный")
+				return
+			}
+
+			body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxBody))
+			if err != nil {
+				var tooLarge *http.MaxBytesError
+				if errors.As(err, &tooLarge) {
+					logger.Warn("тело запроса превышает допустимый размер", zap.Int64("limit", maxBody))
+					_ = httpx.HttpError(w, http.StatusRequestEntityTooLarge, "Тело запроса слишком большое")
+					return
+				}
+				logger.Warn("не удалось прочитать тело запроса", zap.Error(err))
+				_ = httpx.HttpError(w, http.StatusBadRequest, "Не удалось прочитать тело запроса")
+				return
+			}
+			r.Body = io.NopCloser(bytes.NewReader(body))
+
+			storeKey := r.Method + " " + r.URL.Path + " " + key
+			state, entry := store.begin(storeKey, sha256.Sum256(body))
+			switch state {
+			case idempotencyMismatch:
+				logger.Warn("Idempotency-Key повторно использован с другим телом запроса")
+				_ = httpx.HttpError(w, http.StatusUnprocessableEntity, "Idempotency-Key уже использован с другим запросом")
+				return
+			case idempotencyFull:
+				logger.Warn("хранилище Idempotency-Key заполнено выполняющимися запросами")
+				_ = httpx.HttpError(w, http.StatusServiceUnavailable, "Слишком много одновременных запросов с Idempotency-Key")
+				return
+			case idempotencyInFlight:
+				logger.Info("запрос с этим Idempotency-Key еще выполняется")
+				_ = httpx.HttpError(w, http.StatusConflict, "Запрос с этим Idempotency-Key еще выполняется")
+				return
+			case idempotencyReplay:
+				logger.Info("ответ повторен по Idempotency-Key")
+				for name, values := range entry.header {
+					w.Header()[name] = values
+				}
+				w.Header().Set(IdempotentReplayedHeader, "true")
+				w.WriteHeader(entry.status)
+				_, _ = w.Write(entry.body)
+				return
+			}
+
+			capture := &responseCapture{statusRecorder: statusRecorder{ResponseWriter: w}}
+			finished := false
+			// Ключ освобождается и при панике обработчика
+			defer func() {
+				if !finished {
+					store.release(storeKey)
+				}
+			}()
+
+			next.ServeHTTP(capture, r)
+
+			status := capture.status
+			if status == 0 {
+				status = http.StatusOK
+			}
+			if status >= http.StatusInternalServerError {
+				return
+			}
+
+			store.finish(storeKey, status, w.Header().Clone(), capture.body.Bytes())
+			finished = true
+		})
+	}
+}
+
+func isMutating(method string) bool {
+	switch method {
+	case http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,158 @@
+package middleware_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+
+	"github.com/sunr3d/order-stream-processor/internal/middleware"
+)
+
+// countingHandler отвечает кодом status и считает вызовы.
+func countingHandler(status int, calls *int) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		*calls++
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte(`{"call":` + strconv.Itoa(*calls) + `}`))
+	})
+}
+
+func idempotentRequest(method, body, key string) *http.Request {
+	r := httptest.NewRequest(method, "/order", strings.NewReader(body))
+	if key != "" {
+		r.Header.Set(middleware.IdempotencyKeyHeader, key)
+	}
+	return r
+}
+
+func TestIdempotency_Replay(t *testing.T) {
+	calls := 0
+	handler := middleware.Idempotency(middleware.NewIdempotencyStore(time.Hour, 0), 1<<20, zap.NewNop())(
+		countingHandler(http.StatusCreated, &calls))
+
+	first := httptest.NewRecorder()
+	handler.ServeHTTP(first, idempotentRequest(http.MethodPost, `{"a":1}`, "key-1"))
+	second := httptest.NewRecorder()
+	handler.ServeHTTP(second, idempotentRequest(http.MethodPost, `{"a":1}`, "key-1"))
+
+	assert.Equal(t, 1, calls, "обработчик не вызывается повторно")
+	assert.Equal(t, http.StatusCreated, second.Code)
+	assert.Equal(t, first.Body.String(), second.Body.String())
+	assert.Equal(t, "application/json", second.Header().Get("Content-Type"))
+	assert.Equal(t, "true", second.Header().Get(middleware.IdempotentReplayedHeader))
+	assert.Empty(t, first.Header().Get(middleware.IdempotentReplayedHeader))
+}
+
+func TestIdempotency_DifferentBody(t *testing.T) {
+	calls := 0
+	handler := middleware.Idempotency(middleware.NewIdempotencyStore(time.Hour, 0), 1<<20, zap.NewNop())(
+		countingHandler(http.StatusCreated, &calls))
+
+	handler.ServeHTTP(httptest.NewRecorder(), idempotentRequest(http.MethodPost, `{"a":1}`, "key-1"))
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, idempotentRequest(http.MethodPost, `{"a":2}`, "key-1"))
+
+	assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+	assert.Equal(t, 1, calls)
+}
+
+func TestIdempotency_ServerErrorNotStored(t *testing.T) {
+	calls := 0
+	handler := middleware.Idempotency(middleware.NewIdempotencyStore(time.Hour, 0), 1<<20, zap.NewNop())(
+		countingHandler(http.StatusServiceUnavailable, &calls))
+
+	handler.ServeHTTP(httptest.NewRecorder(), idempotentRequest(http.MethodPost, `{}`, "key-1"))
+	handler.ServeHTTP(httptest.NewRecorder(), idempotentRequest(http.MethodPost, `{}`, "key-1"))
+
+	assert.Equal(t, 2, calls, "после ответа 5xx запрос можно повторить")
+}
+
+func TestIdempotency_InFlight(t *testing.T) {
+	store := middleware.NewIdempotencyStore(time.Hour, 0)
+	var inner *httptest.ResponseRecorder
+	var handler http.Handler
+	handler = middleware.Idempotency(store, 1<<20, zap.NewNop())(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			inner = httptest.NewRecorder()
+			handler.ServeHTTP(inner, idempotentRequest(http.MethodPost, `{}`, "key-1"))
+			w.WriteHeader(http.StatusCreated)
+		}))
+
+	handler.ServeHTTP(httptest.NewRecorder(), idempotentRequest(http.MethodPost, `{}`, "key-1"))
+
+	assert.Equal(t, http.StatusConflict, inner.Code)
+}
+
+func TestIdempotency_Skipped(t *testing.T) {
+	calls := 0
+	handler := middleware.Idempotency(middleware.NewIdempotencyStore(time.Hour, 0), 1<<20, zap.NewNop())(
+		countingHandler(http.StatusOK, &calls))
+
+	handler.ServeHTTP(httptest.NewRecorder(), idempotentRequest(http.MethodPost, `{}`, ""))
+	handler.ServeHTTP(httptest.NewRecorder(), idempotentRequest(http.MethodPost, `{}`, ""))
+	handler.ServeHTTP(httptest.NewRecorder(), idempotentRequest(http.MethodGet, "", "key-1"))
+	handler.ServeHTTP(httptest.NewRecorder(), idempotentRequest(http.MethodGet, "", "key-1"))
+
+	assert.Equal(t, 4, calls, "запросы без ключа и GET не кэшируются")
+}
+
+func TestIdempotency_BodyTooLarge(t *testing.T) {
+	calls := 0
+	handler := middleware.Idempotency(middleware.NewIdempotencyStore(time.Hour, 0), 8, zap.NewNop())(
+		countingHandler(http.StatusCreated, &calls))
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, idempotentRequest(http.MethodPost, `{"order_uid":"test-123"}`, "key-1"))
+
+	assert.Equal(t, http.StatusRequestEntityTooLarge, rec.Code)
+	assert.Zero(t, calls)
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, idempotentRequest(http.MethodPost, `{}`, "key-1"))
+	assert.Equal(t, http.StatusCreated, rec.Code, "отклоненный запрос не занимает ключ")
+}
+
+func TestIdempotency_MaxEntriesEvictsOldest(t *testing.T) {
+	calls := 0
+	handler := middleware.Idempotency(middleware.NewIdempotencyStore(time.Hour, 2), 1<<20, zap.NewNop())(
+		countingHandler(http.StatusCreated, &calls))
+
+	for i := range 100 {
+		handler.ServeHTTP(httptest.NewRecorder(), idempotentRequest(http.MethodPost, `{}`, "key-"+strconv.Itoa(i)))
+	}
+	assert.Equal(t, 100, calls)
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, idempotentRequest(http.MethodPost, `{}`, "key-99"))
+	assert.Equal(t, "true", rec.Header().Get(middleware.IdempotentReplayedHeader), "последние ответы сохранены")
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, idempotentRequest(http.MethodPost, `{}`, "key-0"))
+	assert.Empty(t, rec.Header().Get(middleware.IdempotentReplayedHeader), "самые старые ответы вытеснены")
+	assert.Equal(t, 101, calls)
+}
+
+func TestIdempotency_FullOfInFlight(t *testing.T) {
+	store := middleware.NewIdempotencyStore(time.Hour, 1)
+	var inner *httptest.ResponseRecorder
+	var handler http.Handler
+	handler = middleware.Idempotency(store, 1<<20, zap.NewNop())(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if inner == nil {
+				inner = httptest.NewRecorder()
+				handler.ServeHTTP(inner, idempotentRequest(http.MethodPost, `{}`, "key-2"))
+			}
+			w.WriteHeader(http.StatusCreated)
+		}))
+
+	handler.ServeHTTP(httptest.NewRecorder(), idempotentRequest(http.MethodPost, `{}`, "key-1"))
+
+	assert.Equal(t, http.StatusServiceUnavailable, inner.Code)
+}
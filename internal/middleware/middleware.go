@@ -9,6 +9,7 @@ import (
 	"go.uber.org/zap"
 
 	"github.com/sunr3d/order-stream-processor/internal/httpx"
+	"github.com/sunr3d/order-stream-processor/internal/metrics"
 )
 
 func ReqLogger(log *zap.Logger) func(http.Handler) http.Handler {
@@ -25,6 +26,53 @@ func ReqLogger(log *zap.Logger) func(http.Handler) http.Handler {
 	}
 }
 
+// statusRecorder запоминает код ответа, записанный обработчиком.
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (r *statusRecorder) WriteHeader(status int) {
+	if r.status == 0 {
+		r.status = status
+	}
+	r.ResponseWriter.WriteHeader(status)
+}
+
+func (r *statusRecorder) Write(b []byte) (int, error) {
+	if r.status == 0 {
+		r.status = http.StatusOK
+	}
+	return r.ResponseWriter.Write(b)
+}
+
+func (r *statusRecorder) Unwrap() http.ResponseWriter {
+	return r.ResponseWriter
+}
+
+// Metrics учитывает количество и длительность HTTP запросов по маршруту и коду ответа.
+// Маршрут берется из r.Pattern, который заполняет http.ServeMux, поэтому middleware
+// должен оборачивать mux. Запросы без подходящего маршрута учитываются как "unmatched".
+func Metrics() func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+			rec := &statusRecorder{ResponseWriter: w}
+			next.ServeHTTP(rec, r)
+
+			status := rec.status
+			if status == 0 {
+				status = http.StatusOK
+			}
+			route := r.Pattern
+			if route == "" {
+				route = "unmatched"
+			}
+			metrics.ObserveHTTPRequest(r.Method, route, status, time.Since(start))
+		})
+	}
+}
+
 func JSONValidator(log *zap.Logger) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -0,0 +1,119 @@
+package cache_invalidator
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/sunr3d/order-stream-processor/internal/config"
+	"github.com/sunr3d/order-stream-processor/internal/interfaces/infra"
+	"github.com/sunr3d/order-stream-processor/internal/interfaces/services"
+	"github.com/sunr3d/order-stream-processor/models"
+)
+
+// catchUpMargin сдвигает начало сверки назад: транзакция, зафиксированная после обрыва соединения,
+// могла изменить заказ раньше (updated_at — время начала транзакции), а часы БД и сервиса могут расходиться.
+const catchUpMargin = time.Minute
+
+// defaultChunkSize используется, если CACHE_WARMUP_CHUNK_SIZE не задан.
+const defaultChunkSize = 1000
+
+var _ infra.ChangeHandler = (*invalidator)(nil)
+
+// invalidator убирает из кэша заказы, измененные другими репликами, по уведомлениям из БД,
+// а после потери соединения сверяет кэш с изменениями в БД за время, когда уведомления могли теряться.
+type invalidator struct {
+	listener infra.ChangeListener
+	repo     infra.Database
+	svc      services.OrderService
+	cfg      config.CacheConfig
+	logger   *zap.Logger
+}
+
+func New(listener infra.ChangeListener, repo infra.Database, svc services.OrderService, cfg config.CacheConfig, logger *zap.Logger) *invalidator {
+	return &invalidator{
+		listener: listener,
+		repo:     repo,
+		svc:      svc,
+		cfg:      cfg,
+		logger:   logger,
+	}
+}
+
+// Run получает уведомления до отмены ctx.
+func (i *invalidator) Run(ctx context.Context) {
+	logger := i.logger.With(
+		zap.String("op", "cache_invalidator.Run"),
+	)
+
+	logger.Info("инвалидация кэша по уведомлениям из БД запущена")
+
+	if err := i.listener.Listen(ctx, i); err != nil {
+		logger.Error("получение уведомлений об изменениях заказов остановлено", zap.Error(err))
+		return
+	}
+
+	logger.Info("инвалидация кэша по уведомлениям из БД остановлена")
+}
+
+func (i *invalidator) OrderChanged(ctx context.Context, change models.OrderChange) {
+	i.svc.InvalidateOrder(ctx, change)
+}
+
+// Resync инвалидирует заказы, измененные или удаленные начиная с lostSince, повторяя сверку
+// каждые CACHE_INVALIDATION_RESYNC_RETRY, пока она не удастся или не будет отменен ctx.
+func (i *invalidator) Resync(ctx context.Context, lostSince time.Time) {
+	logger := i.logger.With(
+		zap.String("op", "cache_invalidator.Resync"),
+		zap.Time("lost_since", lostSince),
+	)
+
+	since := lostSince.Add(-catchUpMargin)
+	for {
+		changes, err := i.resync(ctx, since)
+		if err == nil {
+			logger.Info("кэш сверен с БД",
+				zap.Int("changed", changes.Count),
+				zap.Int("deleted", len(changes.Deleted)),
+			)
+			return
+		}
+		if ctx.Err() != nil {
+			return
+		}
+
+		logger.Warn("ошибка при сверке кэша с БД, повтор", zap.Duration("retry", i.cfg.Invalidation.ResyncRetry), zap.Error(err))
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(i.cfg.Invalidation.ResyncRetry):
+		}
+	}
+}
+
+func (i *invalidator) resync(ctx context.Context, since time.Time) (*models.OrderChanges, error) {
+	changes, err := i.repo.ScanChangedSince(ctx, since, i.chunkSize(), func(ctx context.Context, orders []*models.Order) error {
+		for _, order := range orders {
+			i.svc.InvalidateOrder(ctx, models.OrderChange{OrderUID: order.OrderUID, Version: order.CurrentVersion()})
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("repo.ScanChangedSince: %w", err)
+	}
+
+	for _, uid := range changes.Deleted {
+		i.svc.InvalidateOrder(ctx, models.OrderChange{OrderUID: uid, Deleted: true})
+	}
+	return changes, nil
+}
+
+func (i *invalidator) chunkSize() int {
+	if i.cfg.WarmupChunkSize > 0 {
+		return i.cfg.WarmupChunkSize
+	}
+	return defaultChunkSize
+}
@@ -0,0 +1,101 @@
+package cache_invalidator_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap"
+
+	"github.com/sunr3d/order-stream-processor/internal/config"
+	"github.com/sunr3d/order-stream-processor/internal/interfaces/infra"
+	"github.com/sunr3d/order-stream-processor/internal/services/cache_invalidator"
+	"github.com/sunr3d/order-stream-processor/mocks"
+	"github.com/sunr3d/order-stream-processor/models"
+)
+
+var lostSince = time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+
+func TestRun_HandlesNotifications(t *testing.T) {
+	listener := &mocks.ChangeListener{}
+	repo := &mocks.Database{}
+	svc := &mocks.OrderService{}
+	ctx := context.Background()
+
+	change := models.OrderChange{OrderUID: "a", Version: 2}
+	listener.EXPECT().Listen(ctx, mock.Anything).RunAndReturn(func(ctx context.Context, h infra.ChangeHandler) error {
+		h.OrderChanged(ctx, change)
+		return nil
+	})
+	svc.On("InvalidateOrder", ctx, change).Return()
+
+	cache_invalidator.New(listener, repo, svc, config.CacheConfig{}, zap.NewNop()).Run(ctx)
+
+	listener.AssertExpectations(t)
+	svc.AssertExpectations(t)
+}
+
+func TestResync_InvalidatesChangedAndDeleted(t *testing.T) {
+	repo := &mocks.Database{}
+	svc := &mocks.OrderService{}
+	ctx := context.Background()
+
+	repo.EXPECT().ScanChangedSince(ctx, lostSince.Add(-time.Minute), 2, mock.Anything).RunAndReturn(
+		func(ctx context.Context, _ time.Time, _ int, fn func(context.Context, []*models.Order) error) (*models.OrderChanges, error) {
+			if err := fn(ctx, []*models.Order{{OrderUID: "a", Version: 3}, {OrderUID: "b"}}); err != nil {
+				return nil, err
+			}
+			return &models.OrderChanges{Count: 2, Deleted: []string{"c"}}, nil
+		})
+	svc.On("InvalidateOrder", ctx, models.OrderChange{OrderUID: "a", Version: 3}).Return().Once()
+	svc.On("InvalidateOrder", ctx, models.OrderChange{OrderUID: "b", Version: 1}).Return().Once()
+	svc.On("InvalidateOrder", ctx, models.OrderChange{OrderUID: "c", Deleted: true}).Return().Once()
+
+	inv := cache_invalidator.New(&mocks.ChangeListener{}, repo, svc, config.CacheConfig{WarmupChunkSize: 2}, zap.NewNop())
+	inv.Resync(ctx, lostSince)
+
+	repo.AssertExpectations(t)
+	svc.AssertExpectations(t)
+}
+
+func TestResync_RetriesUntilSuccess(t *testing.T) {
+	repo := &mocks.Database{}
+	svc := &mocks.OrderService{}
+	ctx := context.Background()
+
+	repo.On("ScanChangedSince", ctx, mock.Anything, mock.Anything, mock.Anything).
+		Return((*models.OrderChanges)(nil), models.ErrUnavailable).Twice()
+	repo.On("ScanChangedSince", ctx, mock.Anything, mock.Anything, mock.Anything).
+		Return(&models.OrderChanges{}, nil).Once()
+
+	cfg := config.CacheConfig{Invalidation: config.CacheInvalidationConfig{ResyncRetry: time.Millisecond}}
+	cache_invalidator.New(&mocks.ChangeListener{}, repo, svc, cfg, zap.NewNop()).Resync(ctx, lostSince)
+
+	repo.AssertNumberOfCalls(t, "ScanChangedSince", 3)
+}
+
+func TestResync_StopsOnCancel(t *testing.T) {
+	repo := &mocks.Database{}
+	svc := &mocks.OrderService{}
+	ctx, cancel := context.WithCancel(context.Background())
+
+	repo.On("ScanChangedSince", ctx, mock.Anything, mock.Anything, mock.Anything).
+		Run(func(mock.Arguments) { cancel() }).
+		Return((*models.OrderChanges)(nil), context.Canceled)
+
+	cfg := config.CacheConfig{Invalidation: config.CacheInvalidationConfig{ResyncRetry: time.Hour}}
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		cache_invalidator.New(&mocks.ChangeListener{}, repo, svc, cfg, zap.NewNop()).Resync(ctx, lostSince)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Resync не завершился после отмены контекста")
+	}
+	assert.Equal(t, 1, len(repo.Calls))
+}
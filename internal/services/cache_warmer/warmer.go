@@ -0,0 +1,275 @@
+package cache_warmer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/sunr3d/order-stream-processor/internal/config"
+	"github.com/sunr3d/order-stream-processor/internal/interfaces/infra"
+	"github.com/sunr3d/order-stream-processor/internal/metrics"
+	"github.com/sunr3d/order-stream-processor/models"
+)
+
+// catchUpMargin сдвигает начало догрузки назад: транзакция, начатая до watermark,
+// могла зафиксировать изменение уже после чтения изменений.
+const catchUpMargin = time.Minute
+
+// defaultChunkSize используется, если CACHE_WARMUP_CHUNK_SIZE не задан.
+const defaultChunkSize = 1000
+
+// warmer восстанавливает кэш при старте и сохраняет его снимки.
+//
+// Снимок хранит watermark — время БД, на которое кэш последний раз был полностью согласован с БД
+// (при восстановлении). Изменения, сделанные после этого другими репликами, в кэш этой реплики
+// не попадают, поэтому при следующем старте догружается все, что изменилось с watermark,
+// а не с момента записи снимка.
+type warmer struct {
+	repo   infra.Database
+	cache  infra.Cache
+	store  infra.SnapshotStore
+	cfg    config.CacheConfig
+	logger *zap.Logger
+
+	mu        sync.Mutex
+	watermark time.Time
+}
+
+// New создает warmer; store == nil отключает снимки, и кэш восстанавливается полным чтением из БД.
+func New(repo infra.Database, cache infra.Cache, store infra.SnapshotStore, cfg config.CacheConfig, logger *zap.Logger) *warmer {
+	return &warmer{
+		repo:   repo,
+		cache:  cache,
+		store:  store,
+		cfg:    cfg,
+		logger: logger,
+	}
+}
+
+// Warm восстанавливает кэш из снимка с догрузкой изменений из БД. Если снимка нет или он поврежден,
+// кэш восстанавливается полным чтением заказов из БД. Заказы загружаются в кэш пачками и дополняют его,
+// не затирая заказы, записанные во время восстановления. Отмена ctx прерывает восстановление.
+func (w *warmer) Warm(ctx context.Context) error {
+	logger := w.logger.With(
+		zap.String("op", "cache_warmer.Warm"),
+	)
+
+	if w.store != nil {
+		err := w.warmFromSnapshot(ctx)
+		if err == nil {
+			return nil
+		}
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if errors.Is(err, infra.ErrNoSnapshot) {
+			logger.Info("снимок кэша отсутствует, восстанавливаем кэш из БД")
+		} else {
+			logger.Warn("снимок кэша не использован, восстанавливаем кэш из БД", zap.Error(err))
+		}
+	}
+
+	return w.warmFull(ctx)
+}
+
+func (w *warmer) warmFromSnapshot(ctx context.Context) error {
+	logger := w.logger.With(
+		zap.String("op", "cache_warmer.warmFromSnapshot"),
+	)
+
+	snap, err := w.store.Load(ctx)
+	if err != nil {
+		return fmt.Errorf("store.Load: %w", err)
+	}
+
+	// Изменения загружаются до заказов из снимка: без них снимок может содержать удаленные
+	// или обезличенные с тех пор заказы. Если догрузка не удалась, снимок не используется
+	changed := make(map[string]struct{})
+	changes, err := w.load(ctx, snap.Watermark.Add(-catchUpMargin), changed)
+	if err != nil {
+		return err
+	}
+
+	// Заказ, удаленный и созданный заново, есть и среди удаленных, и среди измененных:
+	// в кэше уже его новая версия, из снимка он не берется
+	skip := changed
+	for _, uid := range changes.Deleted {
+		skip[uid] = struct{}{}
+	}
+	orders := make([]*models.Order, 0, len(snap.Orders))
+	for _, order := range snap.Orders {
+		if _, ok := skip[order.OrderUID]; !ok {
+			orders = append(orders, order)
+		}
+	}
+	if err := w.restoreChunks(ctx, orders); err != nil {
+		return err
+	}
+
+	w.finish(changes.Watermark)
+
+	logger.Info("кэш восстановлен из снимка",
+		zap.Int("snapshot", len(orders)),
+		zap.Int("changed", changes.Count),
+		zap.Int("deleted", len(changes.Deleted)),
+		zap.Time("watermark", changes.Watermark),
+	)
+	return nil
+}
+
+func (w *warmer) warmFull(ctx context.Context) error {
+	logger := w.logger.With(
+		zap.String("op", "cache_warmer.warmFull"),
+	)
+
+	changes, err := w.load(ctx, time.Time{}, nil)
+	if err != nil {
+		logger.Error("ошибка при восстановлении кэша из БД", zap.Error(err))
+		return err
+	}
+
+	w.finish(changes.Watermark)
+
+	logger.Info("кэш восстановлен из БД", zap.Int("count", changes.Count))
+	return nil
+}
+
+// load загружает в кэш заказы, измененные начиная с since, пачками по CACHE_WARMUP_CHUNK_SIZE
+// и сообщает о ходе загрузки. Если seen не nil, в него добавляются order_uid загруженных заказов.
+func (w *warmer) load(ctx context.Context, since time.Time, seen map[string]struct{}) (*models.OrderChanges, error) {
+	logger := w.logger.With(
+		zap.String("op", "cache_warmer.load"),
+		zap.Time("since", since),
+	)
+
+	started := time.Now()
+	loaded := 0
+	metrics.CacheWarmupProgress(loaded)
+
+	changes, err := w.repo.ScanChangedSince(ctx, since, w.chunkSize(), func(ctx context.Context, orders []*models.Order) error {
+		if err := w.cache.Restore(ctx, orders); err != nil {
+			return fmt.Errorf("cache.Restore: %w", err)
+		}
+		if seen != nil {
+			for _, order := range orders {
+				seen[order.OrderUID] = struct{}{}
+			}
+		}
+
+		loaded += len(orders)
+		metrics.CacheWarmupProgress(loaded)
+		logger.Info("заказы загружены в кэш",
+			zap.Int("loaded", loaded),
+			zap.Duration("elapsed", time.Since(started)),
+		)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("repo.ScanChangedSince: %w", err)
+	}
+	return changes, nil
+}
+
+// restoreChunks добавляет заказы в кэш пачками, чтобы не блокировать кэш надолго.
+func (w *warmer) restoreChunks(ctx context.Context, orders []*models.Order) error {
+	for chunk := range slices.Chunk(orders, w.chunkSize()) {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if err := w.cache.Restore(ctx, chunk); err != nil {
+			return fmt.Errorf("cache.Restore: %w", err)
+		}
+	}
+	return nil
+}
+
+func (w *warmer) chunkSize() int {
+	if w.cfg.WarmupChunkSize > 0 {
+		return w.cfg.WarmupChunkSize
+	}
+	return defaultChunkSize
+}
+
+func (w *warmer) finish(watermark time.Time) {
+	w.setWatermark(watermark)
+	metrics.CacheWarmupDone()
+}
+
+// Run сохраняет снимок кэша каждые CACHE_SNAPSHOT_INTERVAL и при отмене контекста.
+// Снимок не сохраняется, пока кэш не восстановлен.
+func (w *warmer) Run(ctx context.Context) {
+	logger := w.logger.With(
+		zap.String("op", "cache_warmer.Run"),
+	)
+
+	if w.store == nil {
+		return
+	}
+
+	var tick <-chan time.Time
+	if w.cfg.SnapshotInterval > 0 {
+		ticker := time.NewTicker(w.cfg.SnapshotInterval)
+		defer ticker.Stop()
+		tick = ticker.C
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			// Снимок при остановке пишется с новым контекстом: исходный уже отменен
+			if err := w.Snapshot(context.WithoutCancel(ctx)); err != nil {
+				logger.Warn("не удалось сохранить снимок кэша при остановке", zap.Error(err))
+			}
+			return
+		case <-tick:
+			if err := w.Snapshot(ctx); err != nil {
+				logger.Warn("не удалось сохранить снимок кэша", zap.Error(err))
+			}
+		}
+	}
+}
+
+// Snapshot сохраняет текущее содержимое кэша.
+func (w *warmer) Snapshot(ctx context.Context) error {
+	logger := w.logger.With(
+		zap.String("op", "cache_warmer.Snapshot"),
+	)
+
+	if w.store == nil {
+		return nil
+	}
+
+	watermark := w.getWatermark()
+	if watermark.IsZero() {
+		logger.Info("кэш еще не восстановлен, снимок не сохраняется")
+		return nil
+	}
+
+	dumper, ok := w.cache.(infra.CacheDumper)
+	if !ok {
+		return fmt.Errorf("кэш не поддерживает снимки")
+	}
+
+	snap := &models.CacheSnapshot{Watermark: watermark, Orders: dumper.Dump()}
+	if err := w.store.Save(ctx, snap); err != nil {
+		return fmt.Errorf("store.Save: %w", err)
+	}
+	return nil
+}
+
+func (w *warmer) setWatermark(t time.Time) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.watermark = t
+}
+
+func (w *warmer) getWatermark() time.Time {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.watermark
+}
@@ -0,0 +1,207 @@
+package cache_warmer_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/sunr3d/order-stream-processor/internal/config"
+	"github.com/sunr3d/order-stream-processor/internal/infra/inmem"
+	"github.com/sunr3d/order-stream-processor/internal/interfaces/infra"
+	"github.com/sunr3d/order-stream-processor/internal/services/cache_warmer"
+	"github.com/sunr3d/order-stream-processor/mocks"
+	"github.com/sunr3d/order-stream-processor/models"
+)
+
+var watermark = time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+
+func newCache(t *testing.T) infra.Cache {
+	t.Helper()
+	cache, err := inmem.New(config.CacheConfig{}, zap.NewNop())
+	require.NoError(t, err)
+	return cache
+}
+
+func cachedVersions(t *testing.T, cache infra.Cache) map[string]int64 {
+	t.Helper()
+	versions := make(map[string]int64)
+	for _, order := range cache.(infra.CacheDumper).Dump() {
+		versions[order.OrderUID] = order.Version
+	}
+	return versions
+}
+
+// expectScan настраивает чтение изменений из БД: заказы передаются пачками chunks, затем возвращается changes.
+func expectScan(repo *mocks.Database, since any, changes *models.OrderChanges, chunks ...[]*models.Order) {
+	repo.EXPECT().ScanChangedSince(mock.Anything, since, mock.Anything, mock.Anything).RunAndReturn(
+		func(ctx context.Context, _ time.Time, _ int, fn func(context.Context, []*models.Order) error) (*models.OrderChanges, error) {
+			result := *changes
+			for _, chunk := range chunks {
+				if err := fn(ctx, chunk); err != nil {
+					return nil, err
+				}
+				result.Count += len(chunk)
+			}
+			return &result, nil
+		})
+}
+
+func TestWarm_FromSnapshotWithCatchUp(t *testing.T) {
+	repo := &mocks.Database{}
+	store := &mocks.SnapshotStore{}
+	cache := newCache(t)
+	ctx := context.Background()
+
+	store.On("Load", ctx).Return(&models.CacheSnapshot{
+		Watermark: watermark,
+		Orders: []*models.Order{
+			{OrderUID: "kept", Version: 1},
+			{OrderUID: "updated", Version: 1},
+			{OrderUID: "deleted", Version: 1},
+		},
+	}, nil)
+	expectScan(repo, watermark.Add(-time.Minute), &models.OrderChanges{
+		Deleted:   []string{"deleted"},
+		Watermark: watermark.Add(time.Hour),
+	}, []*models.Order{{OrderUID: "updated", Version: 2}, {OrderUID: "new", Version: 1}})
+
+	w := cache_warmer.New(repo, cache, store, config.CacheConfig{}, zap.NewNop())
+	require.NoError(t, w.Warm(ctx))
+
+	assert.Equal(t, map[string]int64{"kept": 1, "updated": 2, "new": 1}, cachedVersions(t, cache))
+	repo.AssertExpectations(t)
+
+	// Следующий снимок сохраняет время догрузки
+	store.On("Save", ctx, mock.MatchedBy(func(s *models.CacheSnapshot) bool {
+		return s.Watermark.Equal(watermark.Add(time.Hour)) && len(s.Orders) == 3
+	})).Return(nil)
+	require.NoError(t, w.Snapshot(ctx))
+	store.AssertExpectations(t)
+}
+
+func TestWarm_FallsBackToFullRead(t *testing.T) {
+	tests := []struct {
+		name    string
+		loadErr error
+	}{
+		{"no snapshot", infra.ErrNoSnapshot},
+		{"corrupt snapshot", errors.New("снимок поврежден")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &mocks.Database{}
+			store := &mocks.SnapshotStore{}
+			cache := newCache(t)
+			ctx := context.Background()
+
+			store.On("Load", ctx).Return((*models.CacheSnapshot)(nil), tt.loadErr)
+			expectScan(repo, time.Time{}, &models.OrderChanges{Watermark: watermark},
+				[]*models.Order{{OrderUID: "a", Version: 3}})
+
+			w := cache_warmer.New(repo, cache, store, config.CacheConfig{}, zap.NewNop())
+			require.NoError(t, w.Warm(ctx))
+
+			assert.Equal(t, map[string]int64{"a": 3}, cachedVersions(t, cache))
+			repo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestWarm_CatchUpFailureKeepsSnapshotOut(t *testing.T) {
+	repo := &mocks.Database{}
+	store := &mocks.SnapshotStore{}
+	cache := newCache(t)
+	ctx := context.Background()
+
+	store.On("Load", ctx).Return(&models.CacheSnapshot{
+		Watermark: watermark,
+		Orders:    []*models.Order{{OrderUID: "stale", Version: 1}},
+	}, nil)
+	repo.On("ScanChangedSince", ctx, mock.Anything, mock.Anything, mock.Anything).
+		Return((*models.OrderChanges)(nil), models.ErrUnavailable)
+
+	w := cache_warmer.New(repo, cache, store, config.CacheConfig{}, zap.NewNop())
+	err := w.Warm(ctx)
+
+	assert.ErrorIs(t, err, models.ErrUnavailable)
+	assert.Empty(t, cachedVersions(t, cache), "снимок без догрузки не попадает в кэш")
+
+	// Кэш не восстановлен — снимок не перезаписывается
+	require.NoError(t, w.Snapshot(ctx))
+	store.AssertNotCalled(t, "Save", mock.Anything, mock.Anything)
+}
+
+func TestWarm_StreamsChunksKeepingConcurrentWrites(t *testing.T) {
+	repo := &mocks.Database{}
+	cache := newCache(t)
+	ctx := context.Background()
+
+	// Заказ, пришедший из Kafka во время восстановления, новее прочитанного из БД
+	require.NoError(t, cache.Set(ctx, "b", &models.Order{OrderUID: "b", Version: 5}))
+	require.NoError(t, cache.Set(ctx, "fresh", &models.Order{OrderUID: "fresh", Version: 1}))
+
+	repo.EXPECT().ScanChangedSince(mock.Anything, time.Time{}, 2, mock.Anything).RunAndReturn(
+		func(ctx context.Context, _ time.Time, _ int, fn func(context.Context, []*models.Order) error) (*models.OrderChanges, error) {
+			require.NoError(t, fn(ctx, []*models.Order{{OrderUID: "a", Version: 1}, {OrderUID: "b", Version: 4}}))
+			assert.Equal(t, map[string]int64{"a": 1, "b": 5, "fresh": 1}, cachedVersions(t, cache),
+				"пачка попадает в кэш сразу после чтения")
+			require.NoError(t, fn(ctx, []*models.Order{{OrderUID: "c", Version: 1}}))
+			return &models.OrderChanges{Count: 3, Watermark: watermark}, nil
+		})
+
+	w := cache_warmer.New(repo, cache, nil, config.CacheConfig{WarmupChunkSize: 2}, zap.NewNop())
+	require.NoError(t, w.Warm(ctx))
+
+	assert.Equal(t, map[string]int64{"a": 1, "b": 5, "c": 1, "fresh": 1}, cachedVersions(t, cache))
+	repo.AssertExpectations(t)
+}
+
+func TestWarm_Cancelled(t *testing.T) {
+	repo := &mocks.Database{}
+	cache := newCache(t)
+	ctx, cancel := context.WithCancel(context.Background())
+
+	repo.EXPECT().ScanChangedSince(mock.Anything, time.Time{}, mock.Anything, mock.Anything).RunAndReturn(
+		func(ctx context.Context, _ time.Time, _ int, fn func(context.Context, []*models.Order) error) (*models.OrderChanges, error) {
+			require.NoError(t, fn(ctx, []*models.Order{{OrderUID: "a", Version: 1}}))
+			cancel()
+			return nil, ctx.Err()
+		})
+
+	w := cache_warmer.New(repo, cache, nil, config.CacheConfig{}, zap.NewNop())
+	err := w.Warm(ctx)
+
+	assert.ErrorIs(t, err, context.Canceled)
+	// Загруженные до отмены пачки остаются в кэше
+	assert.Equal(t, map[string]int64{"a": 1}, cachedVersions(t, cache))
+}
+
+func TestRun_SavesOnShutdown(t *testing.T) {
+	repo := &mocks.Database{}
+	store := &mocks.SnapshotStore{}
+	cache := newCache(t)
+
+	expectScan(repo, time.Time{}, &models.OrderChanges{Watermark: watermark}, []*models.Order{{OrderUID: "a", Version: 1}})
+	store.On("Save", mock.Anything, mock.AnythingOfType("*models.CacheSnapshot")).Return(nil).Once()
+
+	w := cache_warmer.New(repo, cache, nil, config.CacheConfig{}, zap.NewNop())
+	require.NoError(t, w.Warm(context.Background()))
+	w.Run(context.Background()) // без хранилища снимков Run сразу завершается
+
+	w = cache_warmer.New(repo, cache, store, config.CacheConfig{SnapshotInterval: time.Hour}, zap.NewNop())
+	store.On("Load", mock.Anything).Return((*models.CacheSnapshot)(nil), infra.ErrNoSnapshot)
+	require.NoError(t, w.Warm(context.Background()))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	w.Run(ctx)
+
+	store.AssertExpectations(t)
+}
@@ -0,0 +1,136 @@
+package order_service
+
+import (
+	"sync"
+	"time"
+)
+
+// negativeCache помнит order_uid, которых не оказалось в БД, в течение ttl,
+// чтобы повторные запросы несуществующих заказов не доходили до БД.
+//
+// Запись заказа, пока по его order_uid идет чтение из БД, оставляет отметку об инвалидации на ttl:
+// чтение, начатое до записи и вернувшее «не найден», не должно закэшировать промах поверх уже
+// созданного заказа. Без идущих чтений запись только удаляет промах, поэтому размер кэша
+// ограничен maxEntries промахов и числом конкурентных чтений.
+type negativeCache struct {
+	mu         sync.Mutex
+	ttl        time.Duration
+	maxEntries int
+	entries    map[string]negativeEntry
+	inflight   map[string]int
+	lastSweep  time.Time
+	now        func() time.Time
+}
+
+type negativeEntry struct {
+	// invalidatedAt не нулевой у отметки об инвалидации, такая запись не означает промах
+	invalidatedAt time.Time
+	expiresAt     time.Time
+}
+
+// newNegativeCache возвращает nil при ttl <= 0: методы nil-кэша ничего не делают.
+func newNegativeCache(ttl time.Duration, maxEntries int) *negativeCache {
+	if ttl <= 0 {
+		return nil
+	}
+	return &negativeCache{
+		ttl:        ttl,
+		maxEntries: maxEntries,
+		entries:    make(map[string]negativeEntry),
+		inflight:   make(map[string]int),
+		now:        time.Now,
+	}
+}
+
+// started отмечает начало чтения order_uid из БД и возвращает его момент для последующего вызова done.
+func (c *negativeCache) started(orderUID string) time.Time {
+	if c == nil {
+		return time.Time{}
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.inflight[orderUID]++
+	return c.now()
+}
+
+// missing сообщает, что order_uid недавно не был найден в БД.
+func (c *negativeCache) missing(orderUID string) bool {
+	if c == nil {
+		return false
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	e, ok := c.entries[orderUID]
+	return ok && e.invalidatedAt.IsZero() && c.now().Before(e.expiresAt)
+}
+
+// done завершает чтение, начатое в момент since. При notFound промах запоминается, если order_uid
+// не инвалидирован после начала чтения. При заполненном кэше промах не запоминается.
+func (c *negativeCache) done(orderUID string, since time.Time, notFound bool) {
+	if c == nil {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.inflight[orderUID] <= 1 {
+		delete(c.inflight, orderUID)
+	} else {
+		c.inflight[orderUID]--
+	}
+
+	if !notFound {
+		return
+	}
+
+	now := c.now()
+	c.sweep(now)
+
+	e, ok := c.entries[orderUID]
+	if ok && !e.invalidatedAt.IsZero() && !e.invalidatedAt.Before(since) {
+		return
+	}
+	if !ok && c.maxEntries > 0 && len(c.entries) >= c.maxEntries {
+		return
+	}
+
+	c.entries[orderUID] = negativeEntry{expiresAt: now.Add(c.ttl)}
+}
+
+// invalidate забывает промах по order_uid после записи заказа.
+func (c *negativeCache) invalidate(orderUID string) {
+	if c == nil {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := c.now()
+	c.sweep(now)
+
+	if c.inflight[orderUID] == 0 {
+		delete(c.entries, orderUID)
+		return
+	}
+	c.entries[orderUID] = negativeEntry{invalidatedAt: now, expiresAt: now.Add(c.ttl)}
+}
+
+// sweep удаляет истекшие записи не чаще раза в ttl.
+func (c *negativeCache) sweep(now time.Time) {
+	if now.Sub(c.lastSweep) < c.ttl {
+		return
+	}
+	c.lastSweep = now
+
+	for uid, e := range c.entries {
+		if !now.Before(e.expiresAt) {
+			delete(c.entries, uid)
+		}
+	}
+}
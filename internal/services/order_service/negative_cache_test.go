@@ -0,0 +1,85 @@
+package order_service
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestNegativeCache(maxEntries int) (*negativeCache, *time.Time) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	c := newNegativeCache(time.Second, maxEntries)
+	c.now = func() time.Time { return now }
+	return c, &now
+}
+
+func TestNegativeCache_Expires(t *testing.T) {
+	c, now := newTestNegativeCache(0)
+
+	c.done("a", c.started("a"), true)
+	assert.True(t, c.missing("a"))
+	assert.False(t, c.missing("b"))
+
+	*now = now.Add(time.Second)
+	assert.False(t, c.missing("a"))
+}
+
+func TestNegativeCache_InvalidateBeatsEarlierRead(t *testing.T) {
+	c, now := newTestNegativeCache(0)
+
+	since := c.started("a")
+	*now = now.Add(time.Millisecond)
+	c.invalidate("a")
+	c.done("a", since, true)
+	assert.False(t, c.missing("a"), "промах чтения, начатого до записи заказа, не запоминается")
+
+	*now = now.Add(time.Millisecond)
+	c.done("a", c.started("a"), true)
+	assert.True(t, c.missing("a"), "чтение после записи снова может запомнить промах")
+
+	c.invalidate("a")
+	assert.False(t, c.missing("a"))
+}
+
+func TestNegativeCache_FoundDoesNotRemember(t *testing.T) {
+	c, _ := newTestNegativeCache(0)
+
+	c.done("a", c.started("a"), false)
+	assert.False(t, c.missing("a"))
+	assert.Empty(t, c.inflight)
+}
+
+func TestNegativeCache_MaxEntries(t *testing.T) {
+	c, _ := newTestNegativeCache(1)
+
+	c.done("a", c.started("a"), true)
+	c.done("b", c.started("b"), true)
+
+	assert.True(t, c.missing("a"))
+	assert.False(t, c.missing("b"))
+}
+
+func TestNegativeCache_InvalidateFloodStaysBounded(t *testing.T) {
+	c, _ := newTestNegativeCache(10)
+
+	c.done("missing", c.started("missing"), true)
+	for i := range 10_000 {
+		c.invalidate(fmt.Sprintf("order-%d", i))
+	}
+
+	assert.Len(t, c.entries, 1, "инвалидация без промаха и идущего чтения не занимает места в кэше")
+	assert.True(t, c.missing("missing"))
+
+	c.invalidate("missing")
+	assert.Empty(t, c.entries)
+}
+
+func TestNegativeCache_Disabled(t *testing.T) {
+	c := newNegativeCache(0, 0)
+
+	c.done("a", c.started("a"), true)
+	c.invalidate("a")
+	assert.False(t, c.missing("a"))
+}
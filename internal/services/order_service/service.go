@@ -2,11 +2,13 @@ package order_service
 
 import (
 	"context"
+	"errors"
 	"fmt"
-	"strings"
 
 	"go.uber.org/zap"
+	"golang.org/x/sync/singleflight"
 
+	"github.com/sunr3d/order-stream-processor/internal/config"
 	"github.com/sunr3d/order-stream-processor/internal/interfaces/infra"
 	"github.com/sunr3d/order-stream-processor/internal/interfaces/services"
 	"github.com/sunr3d/order-stream-processor/models"
@@ -14,19 +16,22 @@ import (
 
 var _ services.OrderService = (*orderService)(nil)
 
+// События о заказах публикуются не сервисом, а outbox_relay: запись в outbox
+// выполняется в одной транзакции с сохранением заказа (см. postgres.Create).
 type orderService struct {
-	repo  infra.Database
-	cache infra.Cache
-	// broker infra.Broker
-	logger *zap.Logger
+	repo     infra.Database
+	cache    infra.Cache
+	reads    singleflight.Group
+	negative *negativeCache
+	logger   *zap.Logger
 }
 
-func New(repo infra.Database, cache infra.Cache, logger *zap.Logger) services.OrderService {
+func New(repo infra.Database, cache infra.Cache, cfg config.CacheConfig, logger *zap.Logger) services.OrderService {
 	return &orderService{
-		repo:  repo,
-		cache: cache,
-		// broker: broker,
-		logger: logger,
+		repo:     repo,
+		cache:    cache,
+		negative: newNegativeCache(cfg.NegativeTTL, cfg.NegativeMaxEntries),
+		logger:   logger,
 	}
 }
 
@@ -38,17 +43,22 @@ func (s *orderService) ProcessOrder(ctx context.Context, order *models.Order) er
 
 	logger.Info("начинаем обработку заказа")
 
+	if order.Status == "" {
+		order.Status = models.StatusCreated
+	}
+
 	// Сохранение заказа в БД
 	if err := s.repo.Create(ctx, order); err != nil {
-		if strings.Contains(err.Error(), "заказ уже существует") {
-			logger.Info("заказ уже существует в БД")
-			return err
+		if errors.Is(err, models.ErrOrderAlreadyExists) {
+			logger.Info("заказ уже существует в БД, сравниваем с сохраненным")
+			return s.resolveDuplicate(ctx, order)
 		}
 		logger.Error("ошибка при сохранении заказа в базе данных", zap.Error(err))
 		return fmt.Errorf("repo.Create: %w", err)
 	}
 
 	// Сохранение заказа в кэш
+	s.negative.invalidate(order.OrderUID)
 	if err := s.cache.Set(ctx, order.OrderUID, order); err != nil {
 		logger.Warn("ошибка при сохранении заказа в кэше", zap.Error(err))
 	}
@@ -57,6 +67,144 @@ func (s *orderService) ProcessOrder(ctx context.Context, order *models.Order) er
 	return nil
 }
 
+// ProcessOrders сохраняет пачку заказов одним обращением к БД и возвращает итог по каждому заказу.
+// Если БД отвергла данные пачки целиком (например, значение не подходит под тип колонки),
+// заказы сохраняются по одному, чтобы ошибка досталась только проблемному заказу.
+func (s *orderService) ProcessOrders(ctx context.Context, orders []*models.Order) []error {
+	logger := s.logger.With(
+		zap.String("op", "order_service.ProcessOrders"),
+		zap.Int("count", len(orders)),
+	)
+
+	logger.Info("начинаем обработку пачки заказов")
+
+	for _, order := range orders {
+		if order.Status == "" {
+			order.Status = models.StatusCreated
+		}
+	}
+
+	results, err := s.repo.CreateBatch(ctx, orders)
+	if err != nil {
+		if errors.Is(err, models.ErrValidation) {
+			logger.Warn("БД отвергла пачку заказов, сохраняем заказы по одному", zap.Error(err))
+			results = make([]error, len(orders))
+			for i, order := range orders {
+				results[i] = s.ProcessOrder(ctx, order)
+			}
+			return results
+		}
+
+		logger.Error("ошибка при сохранении пачки заказов в базе данных", zap.Error(err))
+		results = make([]error, len(orders))
+		for i := range results {
+			results[i] = fmt.Errorf("repo.CreateBatch: %w", err)
+		}
+		return results
+	}
+
+	for i, order := range orders {
+		if errors.Is(results[i], models.ErrOrderAlreadyExists) {
+			results[i] = s.resolveDuplicate(ctx, order)
+			continue
+		}
+		if results[i] != nil {
+			results[i] = fmt.Errorf("repo.CreateBatch: %w", results[i])
+			continue
+		}
+
+		s.negative.invalidate(order.OrderUID)
+		if err := s.cache.Set(ctx, order.OrderUID, order); err != nil {
+			logger.Warn("ошибка при сохранении заказа в кэше",
+				zap.String("order_uid", order.OrderUID),
+				zap.Error(err),
+			)
+		}
+	}
+
+	logger.Info("пачка заказов обработана")
+	return results
+}
+
+// UpdateOrder заменяет заказ с проверкой версии и обновляет кэш.
+func (s *orderService) UpdateOrder(ctx context.Context, order *models.Order, expectedVersion int64) error {
+	logger := s.logger.With(
+		zap.String("op", "order_service.UpdateOrder"),
+		zap.String("order_uid", order.OrderUID),
+	)
+
+	logger.Info("обновление заказа")
+
+	if err := s.repo.Update(ctx, order, expectedVersion); err != nil {
+		logger.Error("ошибка при обновлении заказа в базе данных", zap.Error(err))
+		return fmt.Errorf("repo.Update: %w", err)
+	}
+
+	s.negative.invalidate(order.OrderUID)
+	if err := s.cache.Set(ctx, order.OrderUID, order); err != nil {
+		logger.Warn("ошибка при сохранении заказа в кэше", zap.Error(err))
+	}
+
+	logger.Info("заказ успешно обновлен", zap.Int64("version", order.Version))
+	return nil
+}
+
+// UpsertOrder сохраняет новый заказ или заменяет сохраненный более новым и обновляет кэш.
+func (s *orderService) UpsertOrder(ctx context.Context, order *models.Order) (models.UpsertResult, error) {
+	logger := s.logger.With(
+		zap.String("op", "order_service.UpsertOrder"),
+		zap.String("order_uid", order.OrderUID),
+	)
+
+	logger.Info("upsert заказа")
+
+	if order.Status == "" {
+		order.Status = models.StatusCreated
+	}
+
+	result, err := s.repo.Upsert(ctx, order)
+	if err != nil {
+		logger.Error("ошибка при upsert заказа в базе данных", zap.Error(err))
+		return "", fmt.Errorf("repo.Upsert: %w", err)
+	}
+
+	s.negative.invalidate(order.OrderUID)
+	if result != models.UpsertSkipped {
+		if err := s.cache.Set(ctx, order.OrderUID, order); err != nil {
+			logger.Warn("ошибка при сохранении заказа в кэше", zap.Error(err))
+		}
+	}
+
+	logger.Info("upsert заказа выполнен", zap.String("result", string(result)))
+	return result, nil
+}
+
+// resolveDuplicate сравнивает заказ с уже сохраненным. Повтор того же заказа (повторная доставка
+// сообщения, повтор запроса клиентом) считается успешным, заказ с другими данными — конфликтом
+// (*models.ConflictError с перечнем расхождений).
+func (s *orderService) resolveDuplicate(ctx context.Context, order *models.Order) error {
+	logger := s.logger.With(
+		zap.String("op", "order_service.resolveDuplicate"),
+		zap.String("order_uid", order.OrderUID),
+	)
+
+	stored, err := s.repo.Read(ctx, order.OrderUID)
+	if err != nil {
+		logger.Error("ошибка при чтении сохраненного заказа", zap.Error(err))
+		return fmt.Errorf("repo.Read: %w", err)
+	}
+
+	diff := models.DiffOrders(stored, order)
+	if len(diff) > 0 {
+		conflict := &models.ConflictError{OrderUID: order.OrderUID, Diff: diff}
+		logger.Warn("заказ уже сохранен с другими данными", zap.Error(conflict))
+		return conflict
+	}
+
+	logger.Info("заказ совпадает с сохраненным, повтор считается успешным")
+	return nil
+}
+
 func (s *orderService) GetOrder(ctx context.Context, orderUID string) (*models.Order, error) {
 	logger := s.logger.With(
 		zap.String("op", "order_service.GetOrder"),
@@ -71,24 +219,68 @@ func (s *orderService) GetOrder(ctx context.Context, orderUID string) (*models.O
 		logger.Info("заказ был успешно найден в кэше")
 		return order, nil
 	}
+
+	if s.negative.missing(orderUID) {
+		logger.Info("заказ недавно не был найден в БД, повторный поиск не выполняется")
+		return nil, fmt.Errorf("repo.Read: %w: %s", models.ErrOrderNotFound, orderUID)
+	}
 	logger.Info("заказ не был найден в кэше, производим поиск в БД")
 
 	// Поиск заказа в БД
-	order, err = s.repo.Read(ctx, orderUID)
+	order, err = s.loadOrder(ctx, orderUID)
 	if err != nil {
+		if errors.Is(err, models.ErrOrderNotFound) {
+			logger.Info("заказ не найден в БД")
+			return nil, fmt.Errorf("repo.Read: %w", err)
+		}
 		logger.Error("ошибка при чтении заказа из базы данных", zap.Error(err))
 		return nil, fmt.Errorf("repo.Read: %w", err)
 	}
 
-	// Сохранение заказа в кэш (т.к. ранее не был найден)
-	if err := s.cache.Set(ctx, orderUID, order); err != nil {
-		logger.Warn("ошибка при сохранении заказа в кэше", zap.Error(err))
-	}
-
 	logger.Info("заказ был успешно найден в БД")
 	return order, nil
 }
 
+// loadOrder читает заказ из БД и сохраняет его в кэш, а промах запоминает в negativeCache.
+// Конкурентные промахи кэша по одному order_uid объединяются в одно чтение. Если общее чтение
+// прервано отменой контекста запроса, который его начал, остальные запросы повторяют чтение.
+func (s *orderService) loadOrder(ctx context.Context, orderUID string) (*models.Order, error) {
+	for {
+		ch := s.reads.DoChan(orderUID, func() (any, error) {
+			since := s.negative.started(orderUID)
+			order, err := s.repo.Read(ctx, orderUID)
+			s.negative.done(orderUID, since, errors.Is(err, models.ErrOrderNotFound))
+			if err != nil {
+				return nil, err
+			}
+
+			// Сохранение заказа в кэш (т.к. ранее не был найден)
+			if err := s.cache.Set(ctx, orderUID, order); err != nil {
+				s.logger.Warn("ошибка при сохранении заказа в кэше",
+					zap.String("op", "order_service.loadOrder"),
+					zap.String("order_uid", orderUID),
+					zap.Error(err),
+				)
+			}
+			return order, nil
+		})
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case res := <-ch:
+			if res.Err != nil {
+				if res.Shared && ctx.Err() == nil &&
+					(errors.Is(res.Err, context.Canceled) || errors.Is(res.Err, context.DeadlineExceeded)) {
+					continue
+				}
+				return nil, res.Err
+			}
+			return res.Val.(*models.Order), nil
+		}
+	}
+}
+
 func (s *orderService) GetAllOrders(ctx context.Context) ([]*models.Order, error) {
 	logger := s.logger.With(
 		zap.String("op", "order_service.GetAllOrders"),
@@ -113,3 +305,191 @@ func (s *orderService) GetAllOrders(ctx context.Context) ([]*models.Order, error
 	)
 	return orders, nil
 }
+
+func (s *orderService) ListOrders(ctx context.Context, filter models.OrderFilter) (*models.OrderPage, error) {
+	logger := s.logger.With(
+		zap.String("op", "order_service.ListOrders"),
+	)
+
+	logger.Info("получение списка заказов")
+
+	page, err := s.repo.List(ctx, filter)
+	if err != nil {
+		logger.Error("ошибка при получении списка заказов из БД", zap.Error(err))
+		return nil, fmt.Errorf("repo.List: %w", err)
+	}
+
+	logger.Info("список заказов успешно получен",
+		zap.Int("count", len(page.Orders)),
+		zap.Bool("has_next", page.NextCursor != ""),
+	)
+	return page, nil
+}
+
+// LookupOrders ищет заказы по вторичному ключу в БД. Если БД недоступна, а кэш поддерживает
+// вторичные индексы, возвращаются найденные в кэше заказы с пометкой FromCache: в кэше могут быть не все заказы.
+func (s *orderService) LookupOrders(ctx context.Context, lookup models.OrderLookup) (*models.LookupResult, error) {
+	logger := s.logger.With(
+		zap.String("op", "order_service.LookupOrders"),
+		zap.String("field", string(lookup.Field)),
+	)
+
+	logger.Info("поиск заказов по вторичному ключу")
+
+	orders, err := s.repo.Lookup(ctx, lookup)
+	if err == nil {
+		logger.Info("заказы найдены в БД", zap.Int("count", len(orders)))
+		return &models.LookupResult{Orders: orders}, nil
+	}
+	if !errors.Is(err, models.ErrUnavailable) {
+		logger.Error("ошибка при поиске заказов в БД", zap.Error(err))
+		return nil, fmt.Errorf("repo.Lookup: %w", err)
+	}
+
+	index, ok := s.cache.(infra.CacheIndex)
+	if !ok {
+		logger.Error("БД недоступна, кэш не поддерживает поиск по вторичным ключам", zap.Error(err))
+		return nil, fmt.Errorf("repo.Lookup: %w", err)
+	}
+
+	cached, cacheErr := index.Lookup(ctx, lookup)
+	if cacheErr != nil || len(cached) == 0 {
+		logger.Error("БД недоступна, заказы в кэше не найдены", zap.Error(err), zap.NamedError("cache_error", cacheErr))
+		return nil, fmt.Errorf("repo.Lookup: %w", err)
+	}
+
+	logger.Warn("БД недоступна, заказы найдены в кэше", zap.Int("count", len(cached)), zap.Error(err))
+	return &models.LookupResult{Orders: cached, FromCache: true}, nil
+}
+
+func (s *orderService) ChangeStatus(ctx context.Context, change models.StatusChange) (*models.Order, error) {
+	logger := s.logger.With(
+		zap.String("op", "order_service.ChangeStatus"),
+		zap.String("order_uid", change.OrderUID),
+		zap.String("to", string(change.To)),
+		zap.String("changed_by", change.ChangedBy),
+	)
+
+	logger.Info("смена статуса заказа")
+
+	order, err := s.repo.UpdateStatus(ctx, change)
+	if err != nil {
+		if errors.Is(err, models.ErrInvalidTransition) || errors.Is(err, models.ErrOrderNotFound) {
+			logger.Info("статус заказа не изменен", zap.Error(err))
+			return nil, fmt.Errorf("repo.UpdateStatus: %w", err)
+		}
+		logger.Error("ошибка при смене статуса заказа в БД", zap.Error(err))
+		return nil, fmt.Errorf("repo.UpdateStatus: %w", err)
+	}
+
+	// Обновление заказа в кэше
+	s.negative.invalidate(order.OrderUID)
+	if err := s.cache.Set(ctx, order.OrderUID, order); err != nil {
+		logger.Warn("ошибка при сохранении заказа в кэше", zap.Error(err))
+	}
+
+	logger.Info("статус заказа успешно изменен")
+	return order, nil
+}
+
+func (s *orderService) GetStatusHistory(ctx context.Context, orderUID string) ([]models.StatusChange, error) {
+	logger := s.logger.With(
+		zap.String("op", "order_service.GetStatusHistory"),
+		zap.String("order_uid", orderUID),
+	)
+
+	logger.Info("получение истории статусов заказа")
+
+	history, err := s.repo.StatusHistory(ctx, orderUID)
+	if err != nil {
+		logger.Error("ошибка при получении истории статусов из БД", zap.Error(err))
+		return nil, fmt.Errorf("repo.StatusHistory: %w", err)
+	}
+
+	logger.Info("история статусов успешно получена", zap.Int("count", len(history)))
+	return history, nil
+}
+
+func (s *orderService) DeleteOrder(ctx context.Context, req models.DeletionRequest) error {
+	logger := s.logger.With(
+		zap.String("op", "order_service.DeleteOrder"),
+		zap.String("order_uid", req.OrderUID),
+		zap.String("requested_by", req.RequestedBy),
+	)
+
+	logger.Info("удаление заказа")
+
+	if err := s.repo.Delete(ctx, req); err != nil {
+		if errors.Is(err, models.ErrOrderNotFound) {
+			logger.Info("заказ не найден", zap.Error(err))
+			return fmt.Errorf("repo.Delete: %w", err)
+		}
+		logger.Error("ошибка при удалении заказа из БД", zap.Error(err))
+		return fmt.Errorf("repo.Delete: %w", err)
+	}
+
+	// Удаление заказа из кэша
+	if err := s.cache.Delete(ctx, req.OrderUID); err != nil {
+		logger.Warn("ошибка при удалении заказа из кэша", zap.Error(err))
+	}
+
+	logger.Info("заказ успешно удален")
+	return nil
+}
+
+func (s *orderService) EraseCustomer(ctx context.Context, req models.ErasureRequest) (*models.ErasureResult, error) {
+	logger := s.logger.With(
+		zap.String("op", "order_service.EraseCustomer"),
+		zap.String("customer_id", req.CustomerID),
+		zap.String("requested_by", req.RequestedBy),
+	)
+
+	logger.Info("обезличивание персональных данных покупателя")
+
+	result, err := s.repo.EraseCustomer(ctx, req)
+	if err != nil {
+		logger.Error("ошибка при обезличивании заказов в БД", zap.Error(err))
+		return nil, fmt.Errorf("repo.EraseCustomer: %w", err)
+	}
+
+	// Из кэша удаляются все заказы покупателя: следующее чтение получит обезличенный заказ из БД
+	for _, uid := range result.OrderUIDs {
+		if err := s.cache.Delete(ctx, uid); err != nil {
+			logger.Warn("ошибка при удалении заказа из кэша", zap.String("order_uid", uid), zap.Error(err))
+		}
+	}
+
+	logger.Info("персональные данные покупателя обезличены",
+		zap.Int("orders", len(result.OrderUIDs)),
+		zap.Int64("audit_id", result.AuditID),
+	)
+	return result, nil
+}
+
+// InvalidateOrder убирает из кэша копию заказа, измененного или удаленного вне этой реплики.
+// Копия той же или более новой версии (например, записанная этой же репликой) остается в кэше.
+func (s *orderService) InvalidateOrder(ctx context.Context, change models.OrderChange) {
+	logger := s.logger.With(
+		zap.String("op", "order_service.InvalidateOrder"),
+		zap.String("order_uid", change.OrderUID),
+		zap.Int64("version", change.Version),
+		zap.Bool("deleted", change.Deleted),
+	)
+
+	// Промах, запомненный до создания заказа другой репликой, больше не действителен
+	s.negative.invalidate(change.OrderUID)
+
+	if !change.Deleted {
+		cached, err := s.cache.Get(ctx, change.OrderUID)
+		if err != nil || cached.CurrentVersion() >= change.Version {
+			return
+		}
+	}
+
+	if err := s.cache.Delete(ctx, change.OrderUID); err != nil {
+		logger.Warn("ошибка при удалении заказа из кэша", zap.Error(err))
+		return
+	}
+
+	logger.Info("устаревший заказ удален из кэша")
+}
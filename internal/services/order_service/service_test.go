@@ -3,13 +3,19 @@ package order_service_test
 import (
 	"context"
 	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 	"go.uber.org/zap"
 
+	"github.com/sunr3d/order-stream-processor/internal/config"
+	"github.com/sunr3d/order-stream-processor/internal/infra/inmem"
 	"github.com/sunr3d/order-stream-processor/internal/services/order_service"
 	"github.com/sunr3d/order-stream-processor/mocks"
 	"github.com/sunr3d/order-stream-processor/models"
@@ -67,7 +73,7 @@ func TestOrderService_ProcessOrder_OK(t *testing.T) {
 	cache := &mocks.Cache{}
 	logger := zap.NewNop()
 
-	svc := order_service.New(repo, cache, logger)
+	svc := order_service.New(repo, cache, config.CacheConfig{}, logger)
 	ctx := context.Background()
 	orderData := createValidOrder()
 
@@ -81,21 +87,50 @@ func TestOrderService_ProcessOrder_OK(t *testing.T) {
 	cache.AssertExpectations(t)
 }
 
-func TestOrderService_ProcessOrder_Duplicate(t *testing.T) {
+func TestOrderService_ProcessOrder_Duplicate_Identical(t *testing.T) {
 	repo := &mocks.Database{}
 	cache := &mocks.Cache{}
 	logger := zap.NewNop()
 
-	svc := order_service.New(repo, cache, logger)
+	svc := order_service.New(repo, cache, config.CacheConfig{}, logger)
 	ctx := context.Background()
 	orderData := createValidOrder()
+	stored := createValidOrder()
+	stored.DateCreated = orderData.DateCreated
+	stored.Status = models.StatusPaid
 
-	repo.On("Create", ctx, mock.AnythingOfType("*models.Order")).Return(errors.New("заказ уже существует в базе данных"))
+	repo.On("Create", ctx, mock.AnythingOfType("*models.Order")).Return(fmt.Errorf("%w в БД: test-123", models.ErrOrderAlreadyExists))
+	repo.On("Read", ctx, "test-123").Return(stored, nil)
 
 	err := svc.ProcessOrder(ctx, orderData)
 
-	assert.Error(t, err)
-	assert.Contains(t, err.Error(), "заказ уже существует в базе данных")
+	assert.NoError(t, err, "повтор того же заказа считается успешным")
+	repo.AssertExpectations(t)
+	cache.AssertNotCalled(t, "Set")
+}
+
+func TestOrderService_ProcessOrder_Duplicate_Conflict(t *testing.T) {
+	repo := &mocks.Database{}
+	cache := &mocks.Cache{}
+	logger := zap.NewNop()
+
+	svc := order_service.New(repo, cache, config.CacheConfig{}, logger)
+	ctx := context.Background()
+	orderData := createValidOrder()
+	stored := createValidOrder()
+	stored.DateCreated = orderData.DateCreated
+	stored.Delivery.City = "Other City"
+
+	repo.On("Create", ctx, mock.AnythingOfType("*models.Order")).Return(fmt.Errorf("%w в БД: test-123", models.ErrOrderAlreadyExists))
+	repo.On("Read", ctx, "test-123").Return(stored, nil)
+
+	err := svc.ProcessOrder(ctx, orderData)
+
+	assert.ErrorIs(t, err, models.ErrOrderAlreadyExists)
+	var conflict *models.ConflictError
+	require.ErrorAs(t, err, &conflict)
+	require.Len(t, conflict.Diff, 1)
+	assert.Equal(t, "/delivery/city", conflict.Diff[0].Path)
 	repo.AssertExpectations(t)
 	cache.AssertNotCalled(t, "Set")
 }
@@ -105,7 +140,7 @@ func TestOrderService_ProcessOrder_Error_Cache(t *testing.T) {
 	cache := &mocks.Cache{}
 	logger := zap.NewNop()
 
-	svc := order_service.New(repo, cache, logger)
+	svc := order_service.New(repo, cache, config.CacheConfig{}, logger)
 	ctx := context.Background()
 	orderData := createValidOrder()
 
@@ -119,13 +154,162 @@ func TestOrderService_ProcessOrder_Error_Cache(t *testing.T) {
 	cache.AssertNotCalled(t, "Set")
 }
 
+// ProcessOrders Tests
+func TestOrderService_ProcessOrders_PerOrderResults(t *testing.T) {
+	repo := &mocks.Database{}
+	cache := &mocks.Cache{}
+	logger := zap.NewNop()
+
+	svc := order_service.New(repo, cache, config.CacheConfig{}, logger)
+	ctx := context.Background()
+	first, second := createValidOrder(), createValidOrder()
+	second.OrderUID = "test-456"
+
+	repo.On("CreateBatch", ctx, []*models.Order{first, second}).Return([]error{
+		nil,
+		fmt.Errorf("%w в БД: test-456", models.ErrOrderAlreadyExists),
+	}, nil)
+	repo.On("Read", ctx, "test-456").Return(createValidOrder(), nil)
+	cache.On("Set", ctx, "test-123", first).Return(nil)
+
+	results := svc.ProcessOrders(ctx, []*models.Order{first, second})
+
+	assert.Len(t, results, 2)
+	assert.NoError(t, results[0])
+	assert.ErrorIs(t, results[1], models.ErrOrderAlreadyExists)
+	assert.Equal(t, models.StatusCreated, first.Status)
+	repo.AssertExpectations(t)
+	cache.AssertExpectations(t)
+}
+
+func TestOrderService_ProcessOrders_Error_DB(t *testing.T) {
+	repo := &mocks.Database{}
+	cache := &mocks.Cache{}
+	logger := zap.NewNop()
+
+	svc := order_service.New(repo, cache, config.CacheConfig{}, logger)
+	ctx := context.Background()
+	orders := []*models.Order{createValidOrder(), createValidOrder()}
+
+	repo.On("CreateBatch", ctx, orders).Return(nil, fmt.Errorf("db.BeginTx: %w", models.ErrUnavailable))
+
+	results := svc.ProcessOrders(ctx, orders)
+
+	assert.Len(t, results, 2)
+	for _, err := range results {
+		assert.ErrorIs(t, err, models.ErrUnavailable)
+	}
+	repo.AssertExpectations(t)
+	cache.AssertNotCalled(t, "Set")
+}
+
+func TestOrderService_ProcessOrders_FallbackOnValidation(t *testing.T) {
+	repo := &mocks.Database{}
+	cache := &mocks.Cache{}
+	logger := zap.NewNop()
+
+	svc := order_service.New(repo, cache, config.CacheConfig{}, logger)
+	ctx := context.Background()
+	good, bad := createValidOrder(), createValidOrder()
+	bad.OrderUID = "test-bad"
+
+	repo.On("CreateBatch", ctx, []*models.Order{good, bad}).Return(nil, fmt.Errorf("store.insertBatch: %w", models.ErrValidation))
+	repo.On("Create", ctx, good).Return(nil)
+	repo.On("Create", ctx, bad).Return(fmt.Errorf("store.insert: %w", models.ErrValidation))
+	cache.On("Set", ctx, "test-123", good).Return(nil)
+
+	results := svc.ProcessOrders(ctx, []*models.Order{good, bad})
+
+	assert.NoError(t, results[0])
+	assert.ErrorIs(t, results[1], models.ErrValidation)
+	repo.AssertExpectations(t)
+	cache.AssertExpectations(t)
+}
+
+// UpdateOrder Tests
+func TestOrderService_UpdateOrder_OK(t *testing.T) {
+	repo := &mocks.Database{}
+	cache := &mocks.Cache{}
+	logger := zap.NewNop()
+
+	svc := order_service.New(repo, cache, config.CacheConfig{}, logger)
+	ctx := context.Background()
+	orderData := createValidOrder()
+
+	repo.On("Update", ctx, orderData, int64(2)).Run(func(args mock.Arguments) {
+		args.Get(1).(*models.Order).Version = 3
+	}).Return(nil)
+	cache.On("Set", ctx, "test-123", orderData).Return(nil)
+
+	err := svc.UpdateOrder(ctx, orderData, 2)
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3), orderData.Version)
+	repo.AssertExpectations(t)
+	cache.AssertExpectations(t)
+}
+
+func TestOrderService_UpdateOrder_VersionConflict(t *testing.T) {
+	repo := &mocks.Database{}
+	cache := &mocks.Cache{}
+	logger := zap.NewNop()
+
+	svc := order_service.New(repo, cache, config.CacheConfig{}, logger)
+	ctx := context.Background()
+
+	repo.On("Update", ctx, mock.AnythingOfType("*models.Order"), int64(1)).Return(models.ErrVersionConflict)
+
+	err := svc.UpdateOrder(ctx, createValidOrder(), 1)
+
+	assert.ErrorIs(t, err, models.ErrVersionConflict)
+	cache.AssertNotCalled(t, "Set")
+}
+
+// UpsertOrder Tests
+func TestOrderService_UpsertOrder_Updated(t *testing.T) {
+	repo := &mocks.Database{}
+	cache := &mocks.Cache{}
+	logger := zap.NewNop()
+
+	svc := order_service.New(repo, cache, config.CacheConfig{}, logger)
+	ctx := context.Background()
+	orderData := createValidOrder()
+
+	repo.On("Upsert", ctx, orderData).Return(models.UpsertUpdated, nil)
+	cache.On("Set", ctx, "test-123", orderData).Return(nil)
+
+	result, err := svc.UpsertOrder(ctx, orderData)
+
+	assert.NoError(t, err)
+	assert.Equal(t, models.UpsertUpdated, result)
+	repo.AssertExpectations(t)
+	cache.AssertExpectations(t)
+}
+
+func TestOrderService_UpsertOrder_Skipped(t *testing.T) {
+	repo := &mocks.Database{}
+	cache := &mocks.Cache{}
+	logger := zap.NewNop()
+
+	svc := order_service.New(repo, cache, config.CacheConfig{}, logger)
+	ctx := context.Background()
+
+	repo.On("Upsert", ctx, mock.AnythingOfType("*models.Order")).Return(models.UpsertSkipped, nil)
+
+	result, err := svc.UpsertOrder(ctx, createValidOrder())
+
+	assert.NoError(t, err)
+	assert.Equal(t, models.UpsertSkipped, result)
+	cache.AssertNotCalled(t, "Set")
+}
+
 // GetOrder Tests
 func TestOrderSerivce_GetOrder_OK_FromDB(t *testing.T) {
 	repo := &mocks.Database{}
 	cache := &mocks.Cache{}
 	logger := zap.NewNop()
 
-	svc := order_service.New(repo, cache, logger)
+	svc := order_service.New(repo, cache, config.CacheConfig{}, logger)
 	ctx := context.Background()
 	expectedOrder := createValidOrder()
 
@@ -146,7 +330,7 @@ func TestOrderSerivce_GetOrder_OK_FromCache(t *testing.T) {
 	cache := &mocks.Cache{}
 	logger := zap.NewNop()
 
-	svc := order_service.New(repo, cache, logger)
+	svc := order_service.New(repo, cache, config.CacheConfig{}, logger)
 	ctx := context.Background()
 	expectedOrder := createValidOrder()
 
@@ -165,28 +349,153 @@ func TestOrderSerivce_GetOrder_NotFound(t *testing.T) {
 	cache := &mocks.Cache{}
 	logger := zap.NewNop()
 
-	svc := order_service.New(repo, cache, logger)
+	svc := order_service.New(repo, cache, config.CacheConfig{}, logger)
 	ctx := context.Background()
 
-	cache.On("Get", ctx, "test-123").Return((*models.Order)(nil), errors.New("заказ не найден"))
-	repo.On("Read", ctx, "test-123").Return((*models.Order)(nil), errors.New("заказ не найден"))
+	cache.On("Get", ctx, "test-123").Return((*models.Order)(nil), models.ErrOrderNotFound)
+	repo.On("Read", ctx, "test-123").Return((*models.Order)(nil), models.ErrOrderNotFound)
 
 	order, err := svc.GetOrder(ctx, "test-123")
 
 	assert.Error(t, err)
 	assert.Nil(t, order)
-	assert.Contains(t, err.Error(), "заказ не найден")
+	assert.ErrorIs(t, err, models.ErrOrderNotFound)
 	cache.AssertExpectations(t)
 	repo.AssertExpectations(t)
 }
 
+func TestOrderService_GetOrder_CoalescesConcurrentMisses(t *testing.T) {
+	repo := &mocks.Database{}
+	cache := &mocks.Cache{}
+	logger := zap.NewNop()
+
+	svc := order_service.New(repo, cache, config.CacheConfig{}, logger)
+	ctx := context.Background()
+	expectedOrder := createValidOrder()
+	const callers = 10
+
+	var misses atomic.Int32
+	release := make(chan struct{})
+	cache.EXPECT().Get(mock.Anything, "test-123").RunAndReturn(func(context.Context, string) (*models.Order, error) {
+		misses.Add(1)
+		return nil, models.ErrOrderNotFound
+	})
+	repo.EXPECT().Read(mock.Anything, "test-123").RunAndReturn(func(context.Context, string) (*models.Order, error) {
+		<-release
+		return expectedOrder, nil
+	}).Once()
+	cache.On("Set", mock.Anything, "test-123", expectedOrder).Return(nil).Once()
+
+	var wg sync.WaitGroup
+	results := make(chan *models.Order, callers)
+	for range callers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			order, err := svc.GetOrder(ctx, "test-123")
+			assert.NoError(t, err)
+			results <- order
+		}()
+	}
+
+	require.Eventually(t, func() bool { return misses.Load() == callers }, time.Second, time.Millisecond)
+	// Даем всем вызовам дойти до ожидания общего чтения
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	wg.Wait()
+	close(results)
+
+	for order := range results {
+		assert.Equal(t, expectedOrder, order)
+	}
+	repo.AssertExpectations(t)
+	cache.AssertExpectations(t)
+}
+
+func TestOrderService_GetOrder_RetriesWhenLeaderCancelled(t *testing.T) {
+	repo := &mocks.Database{}
+	cache := &mocks.Cache{}
+	logger := zap.NewNop()
+
+	svc := order_service.New(repo, cache, config.CacheConfig{}, logger)
+	expectedOrder := createValidOrder()
+
+	leaderCtx, cancelLeader := context.WithCancel(context.Background())
+	leaderReading := make(chan struct{})
+	var reads atomic.Int32
+
+	cache.On("Get", mock.Anything, "test-123").Return((*models.Order)(nil), models.ErrOrderNotFound)
+	repo.EXPECT().Read(mock.Anything, "test-123").RunAndReturn(func(ctx context.Context, _ string) (*models.Order, error) {
+		if reads.Add(1) == 1 {
+			close(leaderReading)
+			<-ctx.Done()
+			return nil, ctx.Err()
+		}
+		return expectedOrder, nil
+	})
+	cache.On("Set", mock.Anything, "test-123", expectedOrder).Return(nil)
+
+	leaderErr := make(chan error, 1)
+	go func() {
+		_, err := svc.GetOrder(leaderCtx, "test-123")
+		leaderErr <- err
+	}()
+	<-leaderReading
+
+	followerResult := make(chan *models.Order, 1)
+	go func() {
+		order, err := svc.GetOrder(context.Background(), "test-123")
+		assert.NoError(t, err)
+		followerResult <- order
+	}()
+	// Даем второму вызову присоединиться к чтению первого
+	time.Sleep(50 * time.Millisecond)
+	cancelLeader()
+
+	assert.ErrorIs(t, <-leaderErr, context.Canceled)
+	assert.Equal(t, expectedOrder, <-followerResult, "отмена чужого запроса не прерывает чтение")
+	assert.Equal(t, int32(2), reads.Load())
+}
+
+func TestOrderService_GetOrder_NegativeCache(t *testing.T) {
+	repo := &mocks.Database{}
+	cache := &mocks.Cache{}
+	logger := zap.NewNop()
+
+	svc := order_service.New(repo, cache, config.CacheConfig{NegativeTTL: time.Minute}, logger)
+	ctx := context.Background()
+
+	cache.On("Get", ctx, "test-123").Return((*models.Order)(nil), models.ErrOrderNotFound)
+	repo.On("Read", ctx, "test-123").Return((*models.Order)(nil), models.ErrOrderNotFound).Once()
+
+	for range 3 {
+		order, err := svc.GetOrder(ctx, "test-123")
+		assert.ErrorIs(t, err, models.ErrOrderNotFound)
+		assert.Nil(t, order)
+	}
+	repo.AssertNumberOfCalls(t, "Read", 1)
+
+	// Создание заказа снимает отметку о промахе
+	created := createValidOrder()
+	repo.On("Create", ctx, created).Return(nil)
+	cache.On("Set", ctx, "test-123", created).Return(nil)
+	require.NoError(t, svc.ProcessOrder(ctx, created))
+
+	repo.On("Read", ctx, "test-123").Return(created, nil).Once()
+	order, err := svc.GetOrder(ctx, "test-123")
+
+	require.NoError(t, err)
+	assert.Equal(t, created, order)
+	repo.AssertNumberOfCalls(t, "Read", 2)
+}
+
 // GetAllOrders Tests
 func TestOrderSerivce_GetAllOrders_OK(t *testing.T) {
 	repo := &mocks.Database{}
 	cache := &mocks.Cache{}
 	logger := zap.NewNop()
 
-	svc := order_service.New(repo, cache, logger)
+	svc := order_service.New(repo, cache, config.CacheConfig{}, logger)
 	ctx := context.Background()
 
 	expectedOrders := []*models.Order{
@@ -212,7 +521,7 @@ func TestOrderSerivce_GetAllOrders_OK_Empty(t *testing.T) {
 	cache := &mocks.Cache{}
 	logger := zap.NewNop()
 
-	svc := order_service.New(repo, cache, logger)
+	svc := order_service.New(repo, cache, config.CacheConfig{}, logger)
 	ctx := context.Background()
 
 	repo.On("ReadAll", ctx).Return([]*models.Order{}, nil)
@@ -231,7 +540,7 @@ func TestOrderSerivce_GetAllOrders_Error_DB(t *testing.T) {
 	cache := &mocks.Cache{}
 	logger := zap.NewNop()
 
-	svc := order_service.New(repo, cache, logger)
+	svc := order_service.New(repo, cache, config.CacheConfig{}, logger)
 	ctx := context.Background()
 
 	repo.On("ReadAll", ctx).Return(([]*models.Order)(nil), errors.New("ошибка БД"))
@@ -250,7 +559,7 @@ func TestOrderSerivce_GetAllOrders_Error_Cache(t *testing.T) {
 	cache := &mocks.Cache{}
 	logger := zap.NewNop()
 
-	svc := order_service.New(repo, cache, logger)
+	svc := order_service.New(repo, cache, config.CacheConfig{}, logger)
 	ctx := context.Background()
 
 	expectedOrders := []*models.Order{
@@ -271,3 +580,290 @@ func TestOrderSerivce_GetAllOrders_Error_Cache(t *testing.T) {
 	repo.AssertExpectations(t)
 	cache.AssertExpectations(t)
 }
+
+// ListOrders Tests
+func TestOrderService_ListOrders_OK(t *testing.T) {
+	repo := &mocks.Database{}
+	cache := &mocks.Cache{}
+	logger := zap.NewNop()
+
+	svc := order_service.New(repo, cache, config.CacheConfig{}, logger)
+	ctx := context.Background()
+	filter := models.OrderFilter{CustomerID: "customer-123", Limit: 10}
+	expectedPage := &models.OrderPage{Orders: []*models.Order{createValidOrder()}}
+
+	repo.On("List", ctx, filter).Return(expectedPage, nil)
+
+	page, err := svc.ListOrders(ctx, filter)
+
+	assert.NoError(t, err)
+	assert.Equal(t, expectedPage, page)
+	repo.AssertExpectations(t)
+	cache.AssertNotCalled(t, "Get")
+}
+
+func TestOrderService_ListOrders_Error_DB(t *testing.T) {
+	repo := &mocks.Database{}
+	cache := &mocks.Cache{}
+	logger := zap.NewNop()
+
+	svc := order_service.New(repo, cache, config.CacheConfig{}, logger)
+	ctx := context.Background()
+	filter := models.OrderFilter{Limit: 10}
+
+	repo.On("List", ctx, filter).Return((*models.OrderPage)(nil), fmt.Errorf("db.QueryContext: %w", models.ErrUnavailable))
+
+	page, err := svc.ListOrders(ctx, filter)
+
+	assert.ErrorIs(t, err, models.ErrUnavailable)
+	assert.Nil(t, page)
+	repo.AssertExpectations(t)
+}
+
+// LookupOrders Tests
+func TestOrderService_LookupOrders_OK(t *testing.T) {
+	repo := &mocks.Database{}
+	cache := &mocks.Cache{}
+	logger := zap.NewNop()
+
+	svc := order_service.New(repo, cache, config.CacheConfig{}, logger)
+	ctx := context.Background()
+	lookup := models.OrderLookup{Field: models.LookupTrackNumber, Value: "TRACK-123", Limit: 10}
+	orders := []*models.Order{createValidOrder()}
+
+	repo.On("Lookup", ctx, lookup).Return(orders, nil)
+
+	result, err := svc.LookupOrders(ctx, lookup)
+
+	require.NoError(t, err)
+	assert.Equal(t, orders, result.Orders)
+	assert.False(t, result.FromCache)
+	repo.AssertExpectations(t)
+}
+
+func TestOrderService_LookupOrders_FallbackToCache(t *testing.T) {
+	repo := &mocks.Database{}
+	cache, err := inmem.New(config.CacheConfig{}, zap.NewNop())
+	require.NoError(t, err)
+	logger := zap.NewNop()
+
+	svc := order_service.New(repo, cache, config.CacheConfig{}, logger)
+	ctx := context.Background()
+	order := createValidOrder()
+	require.NoError(t, cache.Set(ctx, order.OrderUID, order))
+
+	dbErr := fmt.Errorf("db.QueryContext: %w", models.ErrUnavailable)
+	repo.On("Lookup", ctx, mock.Anything).Return(([]*models.Order)(nil), dbErr)
+
+	result, err := svc.LookupOrders(ctx, models.OrderLookup{Field: models.LookupTransaction, Value: "transaction-123", Limit: 10})
+	require.NoError(t, err)
+	assert.True(t, result.FromCache)
+	assert.Equal(t, []*models.Order{order}, result.Orders)
+
+	_, err = svc.LookupOrders(ctx, models.OrderLookup{Field: models.LookupTrackNumber, Value: "missing", Limit: 10})
+	assert.ErrorIs(t, err, models.ErrUnavailable, "без заказов в кэше возвращается ошибка БД")
+}
+
+func TestOrderService_LookupOrders_NoFallbackOnValidation(t *testing.T) {
+	repo := &mocks.Database{}
+	cache := &mocks.Cache{}
+	logger := zap.NewNop()
+
+	svc := order_service.New(repo, cache, config.CacheConfig{}, logger)
+	ctx := context.Background()
+	lookup := models.OrderLookup{Field: "phone", Value: "123", Limit: 10}
+
+	repo.On("Lookup", ctx, lookup).Return(([]*models.Order)(nil), fmt.Errorf("store.lookup: %w", models.ErrValidation))
+
+	result, err := svc.LookupOrders(ctx, lookup)
+
+	assert.ErrorIs(t, err, models.ErrValidation)
+	assert.Nil(t, result)
+	repo.AssertExpectations(t)
+}
+
+// ChangeStatus Tests
+func TestOrderService_ChangeStatus_OK(t *testing.T) {
+	repo := &mocks.Database{}
+	cache := &mocks.Cache{}
+	logger := zap.NewNop()
+
+	svc := order_service.New(repo, cache, config.CacheConfig{}, logger)
+	ctx := context.Background()
+	change := models.StatusChange{OrderUID: "test-123", To: models.StatusPaid, ChangedBy: "operator"}
+	updated := createValidOrder()
+	updated.Status = models.StatusPaid
+
+	repo.On("UpdateStatus", ctx, change).Return(updated, nil)
+	cache.On("Set", ctx, "test-123", updated).Return(nil)
+
+	order, err := svc.ChangeStatus(ctx, change)
+
+	assert.NoError(t, err)
+	assert.Equal(t, models.StatusPaid, order.Status)
+	repo.AssertExpectations(t)
+	cache.AssertExpectations(t)
+}
+
+func TestOrderService_ChangeStatus_InvalidTransition(t *testing.T) {
+	repo := &mocks.Database{}
+	cache := &mocks.Cache{}
+	logger := zap.NewNop()
+
+	svc := order_service.New(repo, cache, config.CacheConfig{}, logger)
+	ctx := context.Background()
+	change := models.StatusChange{OrderUID: "test-123", To: models.StatusDelivered, ChangedBy: "operator"}
+
+	repo.On("UpdateStatus", ctx, change).Return((*models.Order)(nil), fmt.Errorf("%w: created -> delivered", models.ErrInvalidTransition))
+
+	order, err := svc.ChangeStatus(ctx, change)
+
+	assert.ErrorIs(t, err, models.ErrInvalidTransition)
+	assert.Nil(t, order)
+	cache.AssertNotCalled(t, "Set")
+}
+
+// DeleteOrder Tests
+func TestOrderService_DeleteOrder_OK(t *testing.T) {
+	repo := &mocks.Database{}
+	cache := &mocks.Cache{}
+	logger := zap.NewNop()
+
+	svc := order_service.New(repo, cache, config.CacheConfig{}, logger)
+	ctx := context.Background()
+	req := models.DeletionRequest{OrderUID: "test-123", RequestedBy: "dpo"}
+
+	repo.On("Delete", ctx, req).Return(nil)
+	cache.On("Delete", ctx, "test-123").Return(nil)
+
+	err := svc.DeleteOrder(ctx, req)
+
+	assert.NoError(t, err)
+	repo.AssertExpectations(t)
+	cache.AssertExpectations(t)
+}
+
+func TestOrderService_DeleteOrder_NotFound(t *testing.T) {
+	repo := &mocks.Database{}
+	cache := &mocks.Cache{}
+	logger := zap.NewNop()
+
+	svc := order_service.New(repo, cache, config.CacheConfig{}, logger)
+	ctx := context.Background()
+	req := models.DeletionRequest{OrderUID: "test-123", RequestedBy: "dpo"}
+
+	repo.On("Delete", ctx, req).Return(fmt.Errorf("%w: test-123", models.ErrOrderNotFound))
+
+	err := svc.DeleteOrder(ctx, req)
+
+	assert.ErrorIs(t, err, models.ErrOrderNotFound)
+	cache.AssertNotCalled(t, "Delete")
+}
+
+// EraseCustomer Tests
+func TestOrderService_EraseCustomer_EvictsOrders(t *testing.T) {
+	repo := &mocks.Database{}
+	cache := &mocks.Cache{}
+	logger := zap.NewNop()
+
+	svc := order_service.New(repo, cache, config.CacheConfig{}, logger)
+	ctx := context.Background()
+	req := models.ErasureRequest{CustomerID: "test", RequestedBy: "dpo", Reason: "запрос покупателя"}
+	result := &models.ErasureResult{CustomerID: "test", OrderUIDs: []string{"a", "b"}, AuditID: 7}
+
+	repo.On("EraseCustomer", ctx, req).Return(result, nil)
+	cache.On("Delete", ctx, "a").Return(nil)
+	cache.On("Delete", ctx, "b").Return(errors.New("кэш недоступен"))
+
+	got, err := svc.EraseCustomer(ctx, req)
+
+	require.NoError(t, err, "ошибка кэша не отменяет обезличивание")
+	assert.Equal(t, result, got)
+	repo.AssertExpectations(t)
+	cache.AssertExpectations(t)
+}
+
+func TestOrderService_EraseCustomer_Error_DB(t *testing.T) {
+	repo := &mocks.Database{}
+	cache := &mocks.Cache{}
+	logger := zap.NewNop()
+
+	svc := order_service.New(repo, cache, config.CacheConfig{}, logger)
+	ctx := context.Background()
+	req := models.ErasureRequest{CustomerID: "test", RequestedBy: "dpo"}
+
+	repo.On("EraseCustomer", ctx, req).Return((*models.ErasureResult)(nil), models.ErrUnavailable)
+
+	got, err := svc.EraseCustomer(ctx, req)
+
+	assert.ErrorIs(t, err, models.ErrUnavailable)
+	assert.Nil(t, got)
+	cache.AssertNotCalled(t, "Delete")
+}
+
+// InvalidateOrder Tests
+func TestOrderService_InvalidateOrder(t *testing.T) {
+	tests := []struct {
+		name    string
+		cached  *models.Order
+		change  models.OrderChange
+		evicted bool
+	}{
+		{"older copy", &models.Order{OrderUID: "test-123", Version: 1}, models.OrderChange{OrderUID: "test-123", Version: 2}, true},
+		{"same version", &models.Order{OrderUID: "test-123", Version: 2}, models.OrderChange{OrderUID: "test-123", Version: 2}, false},
+		{"newer copy", &models.Order{OrderUID: "test-123", Version: 3}, models.OrderChange{OrderUID: "test-123", Version: 2}, false},
+		{"not cached", nil, models.OrderChange{OrderUID: "test-123", Version: 2}, false},
+		{"deleted", nil, models.OrderChange{OrderUID: "test-123", Version: 2, Deleted: true}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &mocks.Database{}
+			cache := &mocks.Cache{}
+			logger := zap.NewNop()
+
+			svc := order_service.New(repo, cache, config.CacheConfig{}, logger)
+			ctx := context.Background()
+
+			if tt.cached != nil {
+				cache.On("Get", ctx, "test-123").Return(tt.cached, nil)
+			} else {
+				cache.On("Get", ctx, "test-123").Return((*models.Order)(nil), models.ErrOrderNotFound)
+			}
+			cache.On("Delete", ctx, "test-123").Return(nil)
+
+			svc.InvalidateOrder(ctx, tt.change)
+
+			if tt.evicted {
+				cache.AssertCalled(t, "Delete", ctx, "test-123")
+			} else {
+				cache.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything)
+			}
+		})
+	}
+}
+
+func TestOrderService_InvalidateOrder_ClearsNegativeCache(t *testing.T) {
+	repo := &mocks.Database{}
+	cache := &mocks.Cache{}
+	logger := zap.NewNop()
+
+	svc := order_service.New(repo, cache, config.CacheConfig{NegativeTTL: time.Minute}, logger)
+	ctx := context.Background()
+
+	cache.On("Get", ctx, "test-123").Return((*models.Order)(nil), models.ErrOrderNotFound)
+	repo.On("Read", ctx, "test-123").Return((*models.Order)(nil), models.ErrOrderNotFound).Once()
+	_, err := svc.GetOrder(ctx, "test-123")
+	require.ErrorIs(t, err, models.ErrOrderNotFound)
+
+	// Заказ создан другой репликой
+	svc.InvalidateOrder(ctx, models.OrderChange{OrderUID: "test-123", Version: 1})
+
+	created := createValidOrder()
+	repo.On("Read", ctx, "test-123").Return(created, nil).Once()
+	cache.On("Set", ctx, "test-123", created).Return(nil)
+
+	order, err := svc.GetOrder(ctx, "test-123")
+	require.NoError(t, err)
+	assert.Equal(t, created, order)
+}
@@ -0,0 +1,136 @@
+package outbox_relay
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/sunr3d/order-stream-processor/internal/config"
+	"github.com/sunr3d/order-stream-processor/internal/interfaces/infra"
+	"github.com/sunr3d/order-stream-processor/models"
+)
+
+const (
+	headerEventID   = "event-id"
+	headerEventType = "event-type"
+)
+
+// relay переносит события из outbox в Kafka с гарантией at-least-once.
+// Прогресс хранится в самой таблице outbox, поэтому после падения relay продолжает
+// с первого неопубликованного события; потребители дедуплицируют по заголовку event-id.
+type relay struct {
+	outbox infra.Outbox
+	broker infra.Broker
+	topic  string
+	cfg    config.OutboxConfig
+	logger *zap.Logger
+}
+
+func New(outbox infra.Outbox, broker infra.Broker, topic string, cfg config.OutboxConfig, logger *zap.Logger) *relay {
+	return &relay{
+		outbox: outbox,
+		broker: broker,
+		topic:  topic,
+		cfg:    cfg,
+		logger: logger,
+	}
+}
+
+// Run публикует события до отмены контекста.
+func (r *relay) Run(ctx context.Context) {
+	logger := r.logger.With(
+		zap.String("op", "outbox_relay.Run"),
+		zap.String("topic", r.topic),
+	)
+
+	logger.Info("запуск outbox relay",
+		zap.Duration("poll_interval", r.cfg.PollInterval),
+		zap.Int("batch_size", r.cfg.BatchSize),
+		zap.Duration("retention", r.cfg.Retention),
+	)
+
+	ticker := time.NewTicker(r.cfg.PollInterval)
+	defer ticker.Stop()
+
+	var cleanup <-chan time.Time
+	if r.cfg.Retention > 0 && r.cfg.CleanupInterval > 0 {
+		cleanupTicker := time.NewTicker(r.cfg.CleanupInterval)
+		defer cleanupTicker.Stop()
+		cleanup = cleanupTicker.C
+	}
+
+	for {
+		// Пока outbox отдает полные пачки, продолжаем без паузы
+		for {
+			n, err := r.RunOnce(ctx)
+			if err != nil {
+				if ctx.Err() == nil {
+					logger.Warn("ошибка при публикации событий outbox", zap.Error(err))
+				}
+				break
+			}
+			if n < r.cfg.BatchSize {
+				break
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			logger.Info("outbox relay остановлен")
+			return
+		case <-cleanup:
+			if _, err := r.Cleanup(ctx); err != nil && ctx.Err() == nil {
+				logger.Warn("ошибка при удалении опубликованных событий outbox", zap.Error(err))
+			}
+		case <-ticker.C:
+		}
+	}
+}
+
+// RunOnce публикует одну пачку событий и возвращает число опубликованных.
+func (r *relay) RunOnce(ctx context.Context) (int, error) {
+	n, err := r.outbox.ProcessOutbox(ctx, r.cfg.BatchSize, r.publish)
+	if err != nil {
+		return n, fmt.Errorf("outbox.ProcessOutbox: %w", err)
+	}
+	return n, nil
+}
+
+// Cleanup удаляет события, опубликованные раньше OUTBOX_RETENTION, и возвращает их число.
+func (r *relay) Cleanup(ctx context.Context) (int64, error) {
+	if r.cfg.Retention <= 0 {
+		return 0, nil
+	}
+
+	n, err := r.outbox.DeletePublishedOutbox(ctx, time.Now().Add(-r.cfg.Retention))
+	if err != nil {
+		return 0, fmt.Errorf("outbox.DeletePublishedOutbox: %w", err)
+	}
+	return n, nil
+}
+
+func (r *relay) publish(ctx context.Context, ev models.OrderEvent) error {
+	value, err := json.Marshal(ev)
+	if err != nil {
+		return fmt.Errorf("json.Marshal: %w", err)
+	}
+
+	msg := infra.Message{
+		Topic: r.topic,
+		Key:   []byte(ev.OrderUID),
+		Value: value,
+		Headers: map[string]string{
+			headerEventID:   strconv.FormatInt(ev.ID, 10),
+			headerEventType: ev.Type,
+		},
+	}
+
+	if err := r.broker.Publish(ctx, msg); err != nil {
+		return fmt.Errorf("broker.Publish: %w", err)
+	}
+	return nil
+}
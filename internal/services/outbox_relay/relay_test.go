@@ -0,0 +1,121 @@
+package outbox_relay_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap"
+
+	"github.com/sunr3d/order-stream-processor/internal/config"
+	"github.com/sunr3d/order-stream-processor/internal/interfaces/infra"
+	"github.com/sunr3d/order-stream-processor/internal/services/outbox_relay"
+	"github.com/sunr3d/order-stream-processor/mocks"
+	"github.com/sunr3d/order-stream-processor/models"
+)
+
+type publishFunc = func(context.Context, models.OrderEvent) error
+
+func testEvent() models.OrderEvent {
+	return models.OrderEvent{
+		ID:         42,
+		Type:       models.EventOrderCreated,
+		OrderUID:   "test-123",
+		OccurredAt: time.Now(),
+		Order:      &models.Order{OrderUID: "test-123"},
+	}
+}
+
+func TestRelay_RunOnce_OK(t *testing.T) {
+	outbox := &mocks.Outbox{}
+	broker := &mocks.Broker{}
+	cfg := config.OutboxConfig{BatchSize: 10, PollInterval: time.Second}
+	relay := outbox_relay.New(outbox, broker, "order-events", cfg, zap.NewNop())
+	ctx := context.Background()
+
+	outbox.On("ProcessOutbox", ctx, 10, mock.Anything).
+		Run(func(args mock.Arguments) {
+			publish := args.Get(2).(publishFunc)
+			assert.NoError(t, publish(ctx, testEvent()))
+		}).
+		Return(1, nil)
+
+	broker.On("Publish", ctx, mock.MatchedBy(func(msg infra.Message) bool {
+		var ev models.OrderEvent
+		if err := json.Unmarshal(msg.Value, &ev); err != nil {
+			return false
+		}
+		return msg.Topic == "order-events" &&
+			string(msg.Key) == "test-123" &&
+			msg.Headers["event-id"] == "42" &&
+			msg.Headers["event-type"] == models.EventOrderCreated &&
+			ev.Order.OrderUID == "test-123"
+	})).Return(nil)
+
+	n, err := relay.RunOnce(ctx)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, n)
+	outbox.AssertExpectations(t)
+	broker.AssertExpectations(t)
+}
+
+func TestRelay_RunOnce_Error_Broker(t *testing.T) {
+	outbox := &mocks.Outbox{}
+	broker := &mocks.Broker{}
+	cfg := config.OutboxConfig{BatchSize: 10, PollInterval: time.Second}
+	relay := outbox_relay.New(outbox, broker, "order-events", cfg, zap.NewNop())
+	ctx := context.Background()
+
+	var publishErr error
+	outbox.On("ProcessOutbox", ctx, 10, mock.Anything).
+		Run(func(args mock.Arguments) {
+			publish := args.Get(2).(publishFunc)
+			publishErr = publish(ctx, testEvent())
+		}).
+		Return(0, errors.New("publish: kafka недоступна"))
+
+	broker.On("Publish", ctx, mock.AnythingOfType("infra.Message")).Return(errors.New("kafka недоступна"))
+
+	n, err := relay.RunOnce(ctx)
+
+	assert.Error(t, err)
+	assert.Error(t, publishErr)
+	assert.Zero(t, n)
+	outbox.AssertExpectations(t)
+	broker.AssertExpectations(t)
+}
+
+func TestRelay_Cleanup(t *testing.T) {
+	outbox := &mocks.Outbox{}
+	cfg := config.OutboxConfig{BatchSize: 10, PollInterval: time.Second, Retention: time.Hour}
+	relay := outbox_relay.New(outbox, &mocks.Broker{}, "order-events", cfg, zap.NewNop())
+	ctx := context.Background()
+
+	start := time.Now()
+	outbox.On("DeletePublishedOutbox", ctx, mock.MatchedBy(func(before time.Time) bool {
+		return !before.Before(start.Add(-time.Hour)) && !before.After(time.Now().Add(-time.Hour))
+	})).Return(int64(5), nil)
+
+	n, err := relay.Cleanup(ctx)
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(5), n)
+	outbox.AssertExpectations(t)
+}
+
+func TestRelay_Cleanup_Disabled(t *testing.T) {
+	outbox := &mocks.Outbox{}
+	cfg := config.OutboxConfig{BatchSize: 10, PollInterval: time.Second}
+	relay := outbox_relay.New(outbox, &mocks.Broker{}, "order-events", cfg, zap.NewNop())
+
+	n, err := relay.Cleanup(context.Background())
+
+	assert.NoError(t, err)
+	assert.Zero(t, n)
+	outbox.AssertNotCalled(t, "DeletePublishedOutbox", mock.Anything, mock.Anything)
+}
@@ -0,0 +1,7 @@
+-- Таблица заказов
+CREATE TABLE IF NOT EXISTS orders (
+    order_uid VARCHAR(255) PRIMARY KEY,
+    data JSONB NOT NULL
+);
+-- Индекс для фильтрации по содержимому заказа
+CREATE INDEX IF NOT EXISTS idx_orders_data ON orders USING GIN (data);
@@ -0,0 +1 @@
+DROP SCHEMA IF EXISTS normalized CASCADE;
@@ -0,0 +1,66 @@
+-- Нормализованное хранение заказов (POSTGRES_STORAGE=normalized)
+CREATE SCHEMA IF NOT EXISTS normalized;
+
+CREATE TABLE IF NOT EXISTS normalized.orders (
+    order_uid VARCHAR(255) PRIMARY KEY,
+    track_number VARCHAR(255) NOT NULL,
+    entry VARCHAR(255) NOT NULL,
+    locale VARCHAR(35) NOT NULL,
+    internal_signature TEXT NOT NULL,
+    customer_id VARCHAR(255) NOT NULL,
+    delivery_service VARCHAR(255) NOT NULL,
+    shardkey VARCHAR(255) NOT NULL,
+    sm_id INTEGER NOT NULL,
+    date_created TIMESTAMPTZ NOT NULL,
+    oof_shard VARCHAR(255) NOT NULL,
+    status VARCHAR(32) NOT NULL DEFAULT ''
+);
+CREATE INDEX IF NOT EXISTS idx_normalized_orders_track_number ON normalized.orders (track_number);
+CREATE INDEX IF NOT EXISTS idx_normalized_orders_customer_id ON normalized.orders (customer_id);
+CREATE INDEX IF NOT EXISTS idx_normalized_orders_date_created ON normalized.orders (date_created, order_uid);
+
+CREATE TABLE IF NOT EXISTS normalized.deliveries (
+    order_uid VARCHAR(255) PRIMARY KEY REFERENCES normalized.orders (order_uid) ON DELETE CASCADE,
+    name TEXT NOT NULL,
+    phone VARCHAR(64) NOT NULL,
+    zip VARCHAR(32) NOT NULL,
+    city TEXT NOT NULL,
+    address TEXT NOT NULL,
+    region TEXT NOT NULL,
+    email VARCHAR(255) NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS normalized.payments (
+    order_uid VARCHAR(255) PRIMARY KEY REFERENCES normalized.orders (order_uid) ON DELETE CASCADE,
+    transaction VARCHAR(255) NOT NULL,
+    request_id VARCHAR(255) NOT NULL,
+    currency VARCHAR(16) NOT NULL,
+    provider VARCHAR(255) NOT NULL,
+    amount BIGINT NOT NULL,
+    payment_dt TIMESTAMPTZ NOT NULL,
+    bank VARCHAR(255) NOT NULL,
+    delivery_cost BIGINT NOT NULL,
+    goods_total BIGINT NOT NULL,
+    custom_fee BIGINT NOT NULL
+);
+CREATE INDEX IF NOT EXISTS idx_normalized_payments_transaction ON normalized.payments (transaction);
+
+CREATE TABLE IF NOT EXISTS normalized.items (
+    id BIGSERIAL PRIMARY KEY,
+    order_uid VARCHAR(255) NOT NULL REFERENCES normalized.orders (order_uid) ON DELETE CASCADE,
+    position INTEGER NOT NULL,
+    chrt_id BIGINT NOT NULL,
+    track_number VARCHAR(255) NOT NULL,
+    price BIGINT NOT NULL,
+    rid VARCHAR(255) NOT NULL,
+    name TEXT NOT NULL,
+    sale INTEGER NOT NULL,
+    size VARCHAR(64) NOT NULL,
+    total_price BIGINT NOT NULL,
+    nm_id BIGINT NOT NULL,
+    brand VARCHAR(255) NOT NULL,
+    status INTEGER NOT NULL,
+    UNIQUE (order_uid, position)
+);
+CREATE INDEX IF NOT EXISTS idx_normalized_items_chrt_id ON normalized.items (chrt_id);
+CREATE INDEX IF NOT EXISTS idx_normalized_items_brand ON normalized.items (brand);
@@ -0,0 +1,3 @@
+-- Номер ревизии заказа для оптимистичной блокировки (If-Match/ETag)
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS version BIGINT NOT NULL DEFAULT 1;
+ALTER TABLE normalized.orders ADD COLUMN IF NOT EXISTS version BIGINT NOT NULL DEFAULT 1;
@@ -0,0 +1,11 @@
+-- Журнал удаления заказов и обезличивания персональных данных покупателей
+CREATE TABLE IF NOT EXISTS erasure_audit (
+    id BIGSERIAL PRIMARY KEY,
+    action VARCHAR(32) NOT NULL,
+    customer_id VARCHAR(255) NOT NULL,
+    order_uids TEXT[] NOT NULL,
+    requested_by VARCHAR(255) NOT NULL,
+    reason TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+CREATE INDEX IF NOT EXISTS idx_erasure_audit_customer_id ON erasure_audit (customer_id, created_at);
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_erasure_audit_created_at;
+ALTER TABLE normalized.orders DROP COLUMN IF EXISTS updated_at;
+ALTER TABLE orders DROP COLUMN IF EXISTS updated_at;
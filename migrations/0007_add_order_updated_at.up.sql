@@ -0,0 +1,6 @@
+-- Время последнего изменения заказа для догрузки кэша после снимка
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS updated_at TIMESTAMPTZ NOT NULL DEFAULT now();
+CREATE INDEX IF NOT EXISTS idx_orders_updated_at ON orders (updated_at);
+ALTER TABLE normalized.orders ADD COLUMN IF NOT EXISTS updated_at TIMESTAMPTZ NOT NULL DEFAULT now();
+CREATE INDEX IF NOT EXISTS idx_normalized_orders_updated_at ON normalized.orders (updated_at);
+CREATE INDEX IF NOT EXISTS idx_erasure_audit_created_at ON erasure_audit (created_at) WHERE action = 'order.deleted';
@@ -0,0 +1,22 @@
+-- Уведомления об изменениях заказов для инвалидации кэша на других репликах.
+-- NOTIFY доставляется слушателям только после фиксации транзакции.
+CREATE OR REPLACE FUNCTION notify_order_change() RETURNS trigger AS $$
+BEGIN
+    IF TG_OP = 'DELETE' THEN
+        PERFORM pg_notify('order_changes',
+            json_build_object('order_uid', OLD.order_uid, 'version', OLD.version, 'deleted', true)::text);
+        RETURN OLD;
+    END IF;
+    PERFORM pg_notify('order_changes',
+        json_build_object('order_uid', NEW.order_uid, 'version', NEW.version, 'deleted', false)::text);
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS orders_notify_change ON orders;
+CREATE TRIGGER orders_notify_change AFTER INSERT OR UPDATE OR DELETE ON orders
+    FOR EACH ROW EXECUTE FUNCTION notify_order_change();
+
+DROP TRIGGER IF EXISTS orders_notify_change ON normalized.orders;
+CREATE TRIGGER orders_notify_change AFTER INSERT OR UPDATE OR DELETE ON normalized.orders
+    FOR EACH ROW EXECUTE FUNCTION notify_order_change();
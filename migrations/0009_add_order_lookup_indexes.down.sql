@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_orders_customer_id;
+DROP INDEX IF EXISTS idx_orders_transaction;
+DROP INDEX IF EXISTS idx_orders_track_number;
@@ -0,0 +1,5 @@
+-- Индексы вторичных ключей поиска заказов (трек-номер, транзакция оплаты, покупатель) для хранения в JSONB.
+-- В нормализованной схеме такие индексы созданы вместе с таблицами (0004).
+CREATE INDEX IF NOT EXISTS idx_orders_track_number ON orders ((data->>'track_number'));
+CREATE INDEX IF NOT EXISTS idx_orders_transaction ON orders ((data->'payment'->>'transaction'));
+CREATE INDEX IF NOT EXISTS idx_orders_customer_id ON orders ((data->>'customer_id'));
@@ -0,0 +1 @@
+DROP INDEX IF EXISTS idx_outbox_published_at;
@@ -0,0 +1,2 @@
+-- Индекс для удаления опубликованных событий старше OUTBOX_RETENTION
+CREATE INDEX IF NOT EXISTS idx_outbox_published_at ON outbox (published_at) WHERE published_at IS NOT NULL;
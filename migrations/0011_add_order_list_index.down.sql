@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_orders_date_created;
+DROP FUNCTION IF EXISTS order_date_created(JSONB);
@@ -0,0 +1,8 @@
+-- Ключ сортировки списка заказов для хранения в JSONB. Приведение text -> timestamptz не IMMUTABLE,
+-- поэтому оно обернуто в функцию: date_created хранится в RFC 3339 со смещением и не зависит от TimeZone сессии.
+CREATE OR REPLACE FUNCTION order_date_created(data JSONB) RETURNS TIMESTAMPTZ AS $$
+    SELECT (data->>'date_created')::timestamptz
+$$ LANGUAGE sql IMMUTABLE PARALLEL SAFE;
+
+-- Индекс keyset-пагинации GET /orders и поиска по вторичным ключам (ORDER BY date_created, order_uid)
+CREATE INDEX IF NOT EXISTS idx_orders_date_created ON orders (order_date_created(data), order_uid);
@@ -0,0 +1 @@
+DROP INDEX IF EXISTS idx_erasure_audit_order_uids;
@@ -0,0 +1,2 @@
+-- Индекс для проверки журнала аудита при создании заказа: удаленные и обезличенные заказы не создаются повторно
+CREATE INDEX IF NOT EXISTS idx_erasure_audit_order_uids ON erasure_audit USING GIN (order_uids);
@@ -0,0 +1,9 @@
+package models
+
+// OrderChange — уведомление об изменении или удалении заказа в БД.
+type OrderChange struct {
+	OrderUID string `json:"order_uid"`
+	// Version — версия заказа после изменения (для удаленного — последняя версия)
+	Version int64 `json:"version"`
+	Deleted bool  `json:"deleted"`
+}
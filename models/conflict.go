@@ -0,0 +1,142 @@
+package models
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// FieldDiff — расхождение одного поля заказа. Path — JSON Pointer (RFC 6901) относительно заказа.
+type FieldDiff struct {
+	Path     string `json:"path"`
+	Stored   any    `json:"stored"`
+	Incoming any    `json:"incoming"`
+}
+
+// ConflictError означает, что заказ с таким order_uid уже сохранен с другими данными.
+// Оборачивает ErrOrderAlreadyExists, поэтому errors.Is продолжает распознавать дубликат.
+type ConflictError struct {
+	OrderUID string
+	Diff     []FieldDiff
+}
+
+func (e *ConflictError) Error() string {
+	paths := make([]string, 0, len(e.Diff))
+	for _, d := range e.Diff {
+		paths = append(paths, d.Path)
+	}
+	return fmt.Sprintf("%s с другими данными: %s (расхождения: %s)",
+		ErrOrderAlreadyExists, e.OrderUID, strings.Join(paths, ", "))
+}
+
+func (e *ConflictError) Unwrap() error {
+	return ErrOrderAlreadyExists
+}
+
+// DiffOrders сравнивает заказы по содержимому и возвращает расхождения, упорядоченные по пути.
+// Статус и версия не сравниваются: они меняются после создания заказа. Время создания сравнивается
+// как момент времени с точностью PostgreSQL (микросекунды), отсутствующий список товаров
+// равен пустому.
+func DiffOrders(stored, incoming *Order) []FieldDiff {
+	var diff []FieldDiff
+	diffValues("", diffTree(stored), diffTree(incoming), &diff)
+	return diff
+}
+
+// diffTree приводит заказ к дереву JSON-значений для сравнения.
+func diffTree(order *Order) any {
+	o := *order
+	o.Status = ""
+	o.Version = 0
+	o.DateCreated = o.DateCreated.UTC().Truncate(time.Microsecond)
+
+	data, err := json.Marshal(&o)
+	if err != nil {
+		return nil
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var v any
+	if err := dec.Decode(&v); err != nil {
+		return nil
+	}
+	return v
+}
+
+func diffValues(path string, stored, incoming any, diff *[]FieldDiff) {
+	if isEmptyValue(stored) && isEmptyValue(incoming) {
+		return
+	}
+
+	switch s := stored.(type) {
+	case map[string]any:
+		in, ok := incoming.(map[string]any)
+		if !ok {
+			break
+		}
+
+		keys := make([]string, 0, len(s)+len(in))
+		for k := range s {
+			keys = append(keys, k)
+		}
+		for k := range in {
+			if _, ok := s[k]; !ok {
+				keys = append(keys, k)
+			}
+		}
+		slices.Sort(keys)
+
+		for _, k := range keys {
+			diffValues(path+"/"+escapePointer(k), s[k], in[k], diff)
+		}
+		return
+	case []any:
+		in, ok := incoming.([]any)
+		if !ok && incoming != nil {
+			break
+		}
+
+		for i := range max(len(s), len(in)) {
+			var sv, iv any
+			if i < len(s) {
+				sv = s[i]
+			}
+			if i < len(in) {
+				iv = in[i]
+			}
+			diffValues(path+"/"+strconv.Itoa(i), sv, iv, diff)
+		}
+		return
+	}
+
+	if _, ok := incoming.([]any); ok && stored == nil {
+		diffValues(path, []any{}, incoming, diff)
+		return
+	}
+
+	if !reflect.DeepEqual(stored, incoming) {
+		*diff = append(*diff, FieldDiff{Path: path, Stored: stored, Incoming: incoming})
+	}
+}
+
+func isEmptyValue(v any) bool {
+	switch v := v.(type) {
+	case nil:
+		return true
+	case []any:
+		return len(v) == 0
+	default:
+		return false
+	}
+}
+
+// escapePointer экранирует сегмент JSON Pointer.
+func escapePointer(s string) string {
+	return strings.NewReplacer("~", "~0", "/", "~1").Replace(s)
+}
@@ -0,0 +1,67 @@
+package models_test
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sunr3d/order-stream-processor/models"
+)
+
+func conflictOrder() *models.Order {
+	return &models.Order{
+		OrderUID:    "test-123",
+		TrackNumber: "TRACK-123",
+		DateCreated: time.Date(2025, 1, 2, 3, 4, 5, 123456789, time.UTC),
+		Delivery:    models.Delivery{Name: "Test User", City: "Moscow"},
+		Payment:     models.Payment{Amount: 100},
+		Items:       []models.Item{{ChrtID: 1, Price: 100}},
+	}
+}
+
+func TestDiffOrders_Identical(t *testing.T) {
+	stored, incoming := conflictOrder(), conflictOrder()
+	stored.Status = models.StatusShipped
+	stored.DateCreated = stored.DateCreated.In(time.FixedZone("MSK", 3*60*60)).Truncate(time.Microsecond)
+
+	assert.Empty(t, models.DiffOrders(stored, incoming), "статус, часовой пояс и наносекунды не считаются расхождением")
+}
+
+func TestDiffOrders_EmptyItems(t *testing.T) {
+	stored, incoming := conflictOrder(), conflictOrder()
+	stored.Items, incoming.Items = []models.Item{}, nil
+
+	assert.Empty(t, models.DiffOrders(stored, incoming))
+}
+
+func TestDiffOrders_Fields(t *testing.T) {
+	stored, incoming := conflictOrder(), conflictOrder()
+	incoming.Delivery.City = "Kazan"
+	incoming.Payment.Amount = 200
+	incoming.Items = append(incoming.Items, models.Item{ChrtID: 2})
+
+	diff := models.DiffOrders(stored, incoming)
+
+	require.Len(t, diff, 3)
+	assert.Equal(t, "/delivery/city", diff[0].Path)
+	assert.Equal(t, "Moscow", diff[0].Stored)
+	assert.Equal(t, "Kazan", diff[0].Incoming)
+	assert.Equal(t, "/items/1", diff[1].Path)
+	assert.Nil(t, diff[1].Stored)
+	assert.Equal(t, "/payment/amount", diff[2].Path)
+	assert.Equal(t, json.Number("100"), diff[2].Stored)
+}
+
+func TestConflictError(t *testing.T) {
+	err := &models.ConflictError{
+		OrderUID: "test-123",
+		Diff:     []models.FieldDiff{{Path: "/delivery/city"}, {Path: "/payment/amount"}},
+	}
+
+	assert.ErrorIs(t, err, models.ErrOrderAlreadyExists)
+	assert.Contains(t, err.Error(), "test-123")
+	assert.Contains(t, err.Error(), "/delivery/city, /payment/amount")
+}
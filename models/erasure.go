@@ -0,0 +1,46 @@
+package models
+
+import "time"
+
+// ErasedValue заменяет персональные данные при обезличивании заказа.
+const ErasedValue = "[erased]"
+
+// Действия журнала аудита удаления персональных данных
+const (
+	AuditOrderDeleted   = "order.deleted"
+	AuditCustomerErased = "customer.erased"
+)
+
+// DeletionRequest — запрос на удаление заказа.
+type DeletionRequest struct {
+	OrderUID    string
+	RequestedBy string
+	Reason      string
+}
+
+// ErasureRequest — запрос на обезличивание персональных данных покупателя во всех его заказах.
+type ErasureRequest struct {
+	CustomerID  string
+	RequestedBy string
+	Reason      string
+}
+
+// ErasureResult — итог обезличивания: затронутые заказы и запись журнала аудита.
+type ErasureResult struct {
+	CustomerID string    `json:"customer_id"`
+	OrderUIDs  []string  `json:"order_uids"`
+	AuditID    int64     `json:"audit_id"`
+	ErasedAt   time.Time `json:"erased_at"`
+}
+
+// AnonymizeDelivery заменяет персональные данные получателя (имя, телефон, email, адрес).
+// Город, регион, индекс и платежные данные сохраняются. Возвращает false, если заказ уже обезличен.
+func (o *Order) AnonymizeDelivery() bool {
+	d := &o.Delivery
+	if d.Name == ErasedValue && d.Phone == ErasedValue && d.Email == ErasedValue && d.Address == ErasedValue {
+		return false
+	}
+
+	d.Name, d.Phone, d.Email, d.Address = ErasedValue, ErasedValue, ErasedValue, ErasedValue
+	return true
+}
@@ -0,0 +1,28 @@
+package models_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/sunr3d/order-stream-processor/models"
+)
+
+func TestOrder_AnonymizeDelivery(t *testing.T) {
+	order := &models.Order{
+		Delivery: models.Delivery{
+			Name: "Test Testov", Phone: "+9720000000", Zip: "2639809", City: "Kiryat Mozkin",
+			Address: "Ploshad Mira 15", Region: "Kraiot", Email: "test@gmail.com",
+		},
+		Payment: models.Payment{Transaction: "b563feb7b2b84b6test", Amount: 1817},
+	}
+
+	assert.True(t, order.AnonymizeDelivery())
+	assert.Equal(t, models.Delivery{
+		Name: models.ErasedValue, Phone: models.ErasedValue, Zip: "2639809", City: "Kiryat Mozkin",
+		Address: models.ErasedValue, Region: "Kraiot", Email: models.ErasedValue,
+	}, order.Delivery)
+	assert.Equal(t, 1817, order.Payment.Amount, "платежные данные сохраняются")
+
+	assert.False(t, order.AnonymizeDelivery(), "повторное обезличивание ничего не меняет")
+}
@@ -0,0 +1,15 @@
+package models
+
+import "errors"
+
+// Доменные ошибки. Слои оборачивают их через %w, обработчики сопоставляют через errors.Is,
+// поэтому текст сообщений можно менять, не ломая коды ответов.
+var (
+	ErrOrderNotFound      = errors.New("заказ не найден")
+	ErrOrderAlreadyExists = errors.New("заказ уже существует")
+	ErrValidation         = errors.New("ошибка валидации")
+	ErrUnavailable        = errors.New("хранилище недоступно")
+	ErrInvalidTransition  = errors.New("недопустимая смена статуса заказа")
+	ErrVersionConflict    = errors.New("версия заказа не совпадает")
+	ErrOrderErased        = errors.New("заказ удален или обезличен")
+)
@@ -0,0 +1,21 @@
+package models
+
+import "time"
+
+const (
+	EventOrderCreated = "order.created"
+	EventOrderUpdated = "order.updated"
+	// EventOrderStatusChanged публикуется после смены статуса, заказ в событии уже с новым статусом
+	EventOrderStatusChanged = "order.status_changed"
+	// EventOrderDeleted публикуется без заказа: данные удаленного заказа не распространяются дальше
+	EventOrderDeleted = "order.deleted"
+)
+
+// OrderEvent — событие об изменении заказа, публикуемое через outbox.
+type OrderEvent struct {
+	ID         int64     `json:"event_id"`
+	Type       string    `json:"event_type"`
+	OrderUID   string    `json:"order_uid"`
+	OccurredAt time.Time `json:"occurred_at"`
+	Order      *Order    `json:"order,omitempty"`
+}
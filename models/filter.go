@@ -0,0 +1,33 @@
+package models
+
+import "time"
+
+type SortOrder string
+
+const (
+	SortAsc  SortOrder = "asc"
+	SortDesc SortOrder = "desc"
+)
+
+// OrderFilter — параметры постраничной выборки заказов. Пустые поля не фильтруют.
+// Заказы сортируются по date_created (с order_uid для однозначности),
+// Cursor — непрозрачная позиция, полученная из OrderPage.NextCursor.
+type OrderFilter struct {
+	CustomerID      string
+	DeliveryService string
+	TrackNumber     string
+	CreatedFrom     time.Time
+	CreatedTo       time.Time
+	Currency        string
+	Provider        string
+	Brand           string
+
+	Sort   SortOrder
+	Limit  int
+	Cursor string
+}
+
+type OrderPage struct {
+	Orders     []*Order
+	NextCursor string
+}
@@ -0,0 +1,52 @@
+package models
+
+import "cmp"
+
+// LookupField — вторичный ключ поиска заказов.
+type LookupField string
+
+const (
+	LookupTrackNumber LookupField = "track_number"
+	LookupTransaction LookupField = "transaction"
+	LookupCustomer    LookupField = "customer_id"
+)
+
+// LookupFields — все вторичные ключи поиска заказов.
+var LookupFields = []LookupField{LookupTrackNumber, LookupTransaction, LookupCustomer}
+
+// OrderLookup — поиск до Limit заказов, у которых вторичный ключ Field равен Value.
+// Заказы возвращаются от новых к старым (см. CompareNewestFirst).
+type OrderLookup struct {
+	Field LookupField
+	Value string
+	Limit int
+}
+
+// LookupResult — найденные заказы. FromCache означает, что БД была недоступна и заказы найдены в кэше,
+// поэтому результат может быть неполным.
+type LookupResult struct {
+	Orders    []*Order
+	FromCache bool
+}
+
+// LookupKey возвращает значение вторичного ключа field заказа.
+func (o *Order) LookupKey(field LookupField) string {
+	switch field {
+	case LookupTrackNumber:
+		return o.TrackNumber
+	case LookupTransaction:
+		return o.Payment.Transaction
+	case LookupCustomer:
+		return o.CustomerID
+	default:
+		return ""
+	}
+}
+
+// CompareNewestFirst упорядочивает заказы по убыванию date_created, затем order_uid.
+func CompareNewestFirst(a, b *Order) int {
+	if c := b.DateCreated.Compare(a.DateCreated); c != 0 {
+		return c
+	}
+	return cmp.Compare(b.OrderUID, a.OrderUID)
+}
@@ -17,8 +17,47 @@ type Order struct {
 	SmID              int       `json:"sm_id"`
 	DateCreated       time.Time `json:"date_created"`
 	OofShard          string    `json:"oof_shard"`
+
+	Status OrderStatus `json:"status,omitempty"`
+	// Version — номер ревизии заказа, увеличивается при каждом изменении (ETag в HTTP API)
+	Version int64 `json:"version,omitempty"`
+}
+
+// CurrentStatus возвращает статус заказа; заказы, сохраненные до появления статусов, считаются созданными.
+func (o *Order) CurrentStatus() OrderStatus {
+	if o.Status == "" {
+		return StatusCreated
+	}
+	return o.Status
+}
+
+// CurrentVersion возвращает версию заказа; заказы, прочитанные без версии, считаются первой ревизией.
+func (o *Order) CurrentVersion() int64 {
+	if o.Version <= 0 {
+		return 1
+	}
+	return o.Version
 }
 
+// Supersedes сообщает, должен ли заказ заменить сохраненный при upsert: если версия указана,
+// сравниваются версии, иначе — время создания.
+func (o *Order) Supersedes(stored *Order) bool {
+	if o.Version > 0 {
+		return o.Version > stored.Version
+	}
+	return o.DateCreated.After(stored.DateCreated)
+}
+
+// UpsertResult — итог upsert заказа.
+type UpsertResult string
+
+const (
+	UpsertCreated UpsertResult = "created"
+	UpsertUpdated UpsertResult = "updated"
+	// UpsertSkipped — сохраненный заказ новее или совпадает с присланным
+	UpsertSkipped UpsertResult = "skipped"
+)
+
 type Delivery struct {
 	Name    string `json:"name"`
 	Phone   string `json:"phone"`
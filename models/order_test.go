@@ -0,0 +1,38 @@
+package models_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/sunr3d/order-stream-processor/models"
+)
+
+func TestOrder_Supersedes(t *testing.T) {
+	now := time.Now()
+	stored := &models.Order{Version: 3, DateCreated: now}
+
+	tests := []struct {
+		name     string
+		incoming models.Order
+		want     bool
+	}{
+		{"higher version", models.Order{Version: 4, DateCreated: now.Add(-time.Hour)}, true},
+		{"same version", models.Order{Version: 3, DateCreated: now.Add(time.Hour)}, false},
+		{"lower version", models.Order{Version: 2, DateCreated: now.Add(time.Hour)}, false},
+		{"no version, newer", models.Order{DateCreated: now.Add(time.Second)}, true},
+		{"no version, same date", models.Order{DateCreated: now}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.incoming.Supersedes(stored))
+		})
+	}
+}
+
+func TestOrder_CurrentVersion(t *testing.T) {
+	assert.Equal(t, int64(1), (&models.Order{}).CurrentVersion())
+	assert.Equal(t, int64(5), (&models.Order{Version: 5}).CurrentVersion())
+}
@@ -0,0 +1,20 @@
+package models
+
+import "time"
+
+// OrderChanges — изменения заказов в БД начиная с некоторого момента.
+type OrderChanges struct {
+	// Count — число прочитанных созданных или измененных заказов
+	Count int
+	// Deleted — order_uid удаленных заказов
+	Deleted []string
+	// Watermark — время БД, на которое получены изменения: следующая догрузка начинается с него
+	Watermark time.Time
+}
+
+// CacheSnapshot — снимок содержимого кэша. Watermark — время БД, с которого кэш
+// нужно догрузить из БД после восстановления из снимка.
+type CacheSnapshot struct {
+	Watermark time.Time
+	Orders    []*Order
+}
@@ -0,0 +1,42 @@
+package models_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/sunr3d/order-stream-processor/models"
+)
+
+func TestOrderStatus_CanTransitionTo(t *testing.T) {
+	tests := []struct {
+		from, to models.OrderStatus
+		allowed  bool
+	}{
+		{models.StatusCreated, models.StatusPaid, true},
+		{models.StatusCreated, models.StatusCancelled, true},
+		{models.StatusCreated, models.StatusShipped, false},
+		{models.StatusPaid, models.StatusAssembled, true},
+		{models.StatusAssembled, models.StatusShipped, true},
+		{models.StatusShipped, models.StatusDelivered, true},
+		{models.StatusShipped, models.StatusCancelled, false},
+		{models.StatusDelivered, models.StatusReturned, true},
+		{models.StatusCancelled, models.StatusPaid, false},
+		{models.StatusReturned, models.StatusDelivered, false},
+		{models.StatusPaid, models.StatusPaid, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.from)+"->"+string(tt.to), func(t *testing.T) {
+			assert.Equal(t, tt.allowed, tt.from.CanTransitionTo(tt.to))
+		})
+	}
+}
+
+func TestOrder_CurrentStatus_DefaultsToCreated(t *testing.T) {
+	order := &models.Order{}
+	assert.Equal(t, models.StatusCreated, order.CurrentStatus())
+
+	order.Status = models.StatusShipped
+	assert.Equal(t, models.StatusShipped, order.CurrentStatus())
+}
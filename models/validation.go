@@ -0,0 +1,30 @@
+package models
+
+import "strings"
+
+// Violation — нарушение одного правила валидации заказа. Path — JSON Pointer (RFC 6901)
+// относительно заказа, Code — машиночитаемый код нарушения, Value — отклоненное значение.
+type Violation struct {
+	Path    string `json:"path"`
+	Code    string `json:"code"`
+	Value   any    `json:"value"`
+	Message string `json:"message"`
+}
+
+// ValidationError содержит все нарушения, найденные при валидации заказа.
+// Оборачивает ErrValidation, поэтому errors.Is продолжает распознавать ошибку валидации.
+type ValidationError struct {
+	Violations []Violation
+}
+
+func (e *ValidationError) Error() string {
+	msgs := make([]string, 0, len(e.Violations))
+	for _, v := range e.Violations {
+		msgs = append(msgs, v.Path+": "+v.Message)
+	}
+	return strings.Join(msgs, "; ")
+}
+
+func (e *ValidationError) Unwrap() error {
+	return ErrValidation
+}